@@ -53,7 +53,124 @@ const deleteProductBatch = async (batchId) => {
   }
 }
 
+const createStockAdjustment = async (data) => {
+  try {
+    const result = await bru.sendRequest({
+      url: `${baseUrl}/api/${apiVersion}/inventory/adjustments`,
+      method: "POST",
+      headers: {
+        "Content-Type": "application/json",
+        "Authorization": `Bearer ${bru.getVar('jwt_token')}`
+      },
+      data
+    })
+
+    if (!result.data?.success) {
+      throw new Error(`Failed to create stock adjustment: ${result.data?.error || 'Unknown error'}`)
+    }
+
+    return result.data.data
+  } catch (error) {
+    console.error("❌ Stock adjustment creation failed:", error.message)
+    throw error
+  }
+}
+
+const createReorderRule = async (data) => {
+  try {
+    const result = await bru.sendRequest({
+      url: `${baseUrl}/api/${apiVersion}/inventory/reorder-rules`,
+      method: "POST",
+      headers: {
+        "Content-Type": "application/json",
+        "Authorization": `Bearer ${bru.getVar('jwt_token')}`
+      },
+      data
+    })
+
+    if (!result.data?.success) {
+      throw new Error(`Failed to create reorder rule: ${result.data?.error || 'Unknown error'}`)
+    }
+
+    return result.data.data
+  } catch (error) {
+    console.error("❌ Reorder rule creation failed:", error.message)
+    throw error
+  }
+}
+
+const deleteReorderRule = async (ruleId) => {
+  if (!ruleId) {
+    console.warn("⚠️ No reorder rule ID provided for deletion")
+    return
+  }
+
+  try {
+    await bru.sendRequest({
+      url: `${baseUrl}/api/${apiVersion}/inventory/reorder-rules/${ruleId}`,
+      method: "DELETE",
+      headers: {
+        "Authorization": `Bearer ${bru.getVar('jwt_token')}`
+      }
+    })
+  } catch (error) {
+    console.error("❌ Reorder rule deletion failed:", error.message)
+    throw error
+  }
+}
+
+const quarantineExpiredStock = async (data) => {
+  try {
+    const result = await bru.sendRequest({
+      url: `${baseUrl}/api/${apiVersion}/inventory/expired/quarantine`,
+      method: "POST",
+      headers: {
+        "Content-Type": "application/json",
+        "Authorization": `Bearer ${bru.getVar('jwt_token')}`
+      },
+      data
+    })
+
+    if (!result.data?.success) {
+      throw new Error(`Failed to quarantine expired stock: ${result.data?.error || 'Unknown error'}`)
+    }
+
+    return result.data.data
+  } catch (error) {
+    console.error("❌ Expired stock quarantine failed:", error.message)
+    throw error
+  }
+}
+
+const writeOffExpiredStock = async (data) => {
+  try {
+    const result = await bru.sendRequest({
+      url: `${baseUrl}/api/${apiVersion}/inventory/expired/write-off`,
+      method: "POST",
+      headers: {
+        "Content-Type": "application/json",
+        "Authorization": `Bearer ${bru.getVar('jwt_token')}`
+      },
+      data
+    })
+
+    if (!result.data?.success) {
+      throw new Error(`Failed to write off expired stock: ${result.data?.error || 'Unknown error'}`)
+    }
+
+    return result.data.data
+  } catch (error) {
+    console.error("❌ Expired stock write-off failed:", error.message)
+    throw error
+  }
+}
+
 module.exports = {
   createProductBatch,
-  deleteProductBatch
+  deleteProductBatch,
+  createStockAdjustment,
+  createReorderRule,
+  deleteReorderRule,
+  quarantineExpiredStock,
+  writeOffExpiredStock
 }
@@ -0,0 +1,79 @@
+const baseUrl = bru.interpolate("{{baseUrl}}");
+const apiVersion = bru.interpolate("{{apiVersion}}");
+
+const createLocation = async (data) => {
+  const cachedLocation = bru.getVar(data.name)
+  if (cachedLocation) {
+    return cachedLocation
+  }
+
+  try {
+    const result = await bru.sendRequest({
+      url: `${baseUrl}/api/${apiVersion}/locations`,
+      method: "POST",
+      headers: {
+        "Content-Type": "application/json",
+        "Authorization": `Bearer ${bru.getVar('jwt_token')}`
+      },
+      data
+    })
+
+    if (!result.data?.success) {
+      throw new Error(`Failed to create location: ${result.data?.error || 'Unknown error'}`)
+    }
+
+    bru.setVar(data.name, result.data.data)
+
+    return result.data.data
+  } catch (error) {
+    console.error("❌ Location creation failed:", error.message)
+    throw error
+  }
+}
+
+const getDefaultLocation = async () => {
+  try {
+    const result = await bru.sendRequest({
+      url: `${baseUrl}/api/${apiVersion}/locations`,
+      method: "GET",
+      headers: {
+        "Authorization": `Bearer ${bru.getVar('jwt_token')}`
+      }
+    })
+
+    if (!result.data?.success) {
+      throw new Error(`Failed to list locations: ${result.data?.error || 'Unknown error'}`)
+    }
+
+    return result.data.data.find(l => l.isDefault) || null
+  } catch (error) {
+    console.error("❌ Location lookup failed:", error.message)
+    throw error
+  }
+}
+
+const unassignUser = async (locationId, userId) => {
+  if (!locationId || !userId) {
+    console.warn("⚠️ No location or user ID provided for unassignment")
+    return
+  }
+
+  try {
+    await bru.sendRequest({
+      url: `${baseUrl}/api/${apiVersion}/locations/${locationId}/users/${userId}`,
+      method: "DELETE",
+      headers: {
+        "Authorization": `Bearer ${bru.getVar('jwt_token')}`
+      }
+    })
+  } catch (error) {
+    console.error("❌ User unassignment failed:", error.message)
+    throw error
+  }
+}
+
+module.exports = {
+  createLocation,
+  getDefaultLocation,
+  unassignUser
+}
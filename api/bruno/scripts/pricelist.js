@@ -0,0 +1,89 @@
+const baseUrl = bru.interpolate("{{baseUrl}}");
+const apiVersion = bru.interpolate("{{apiVersion}}");
+
+const createPriceList = async (data) => {
+  const cachedList = bru.getVar(data.name)
+  if (cachedList) {
+    return cachedList
+  }
+
+  try {
+    const result = await bru.sendRequest({
+      url: `${baseUrl}/api/${apiVersion}/price-lists`,
+      method: "POST",
+      headers: {
+        "Content-Type": "application/json",
+        "Authorization": `Bearer ${bru.getVar('jwt_token')}`
+      },
+      data
+    })
+
+    if (!result.data?.success) {
+      throw new Error(`Failed to create price list: ${result.data?.error || 'Unknown error'}`)
+    }
+
+    bru.setVar(data.name, result.data.data)
+
+    return result.data.data
+  } catch (error) {
+    console.error("❌ Price list creation failed:", error.message)
+    throw error
+  }
+}
+
+const addPrice = async (listId, data) => {
+  const cacheKey = `${listId}.${data.productId}.${data.effectiveFrom}`
+  const cachedPrice = bru.getVar(cacheKey)
+  if (cachedPrice) {
+    return cachedPrice
+  }
+
+  try {
+    const result = await bru.sendRequest({
+      url: `${baseUrl}/api/${apiVersion}/price-lists/${listId}/prices`,
+      method: "POST",
+      headers: {
+        "Content-Type": "application/json",
+        "Authorization": `Bearer ${bru.getVar('jwt_token')}`
+      },
+      data
+    })
+
+    if (!result.data?.success) {
+      throw new Error(`Failed to add price: ${result.data?.error || 'Unknown error'}`)
+    }
+
+    bru.setVar(cacheKey, result.data.data)
+
+    return result.data.data
+  } catch (error) {
+    console.error("❌ Price creation failed:", error.message)
+    throw error
+  }
+}
+
+const assignCustomer = async (listId, customerId) => {
+  if (!listId || !customerId) {
+    console.warn("⚠️ Price list ID and customer ID are required for assignment")
+    return
+  }
+
+  try {
+    await bru.sendRequest({
+      url: `${baseUrl}/api/${apiVersion}/price-lists/${listId}/customers/${customerId}`,
+      method: "PUT",
+      headers: {
+        "Authorization": `Bearer ${bru.getVar('jwt_token')}`
+      }
+    })
+  } catch (error) {
+    console.error("❌ Price list assignment failed:", error.message)
+    throw error
+  }
+}
+
+module.exports = {
+  createPriceList,
+  addPrice,
+  assignCustomer
+}
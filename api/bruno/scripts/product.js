@@ -105,9 +105,73 @@ const deleteProductCategory = async (categoryId) => {
   }
 }
 
+const createAttribute = async (categoryId, data) => {
+  const key = `${categoryId}.${data.name}`
+  const cachedAttribute = bru.getVar(key)
+  if (cachedAttribute) {
+    return cachedAttribute
+  }
+
+  try {
+    const result = await bru.sendRequest({
+      url: `${baseUrl}/api/${apiVersion}/products/categories/${categoryId}/attributes`,
+      method: "POST",
+      headers: {
+        "Content-Type": "application/json",
+        "Authorization": `Bearer ${bru.getVar('jwt_token')}`
+      },
+      data
+    })
+
+    if (!result.data?.success) {
+      throw new Error(`Failed to create attribute: ${result.data?.error || 'Unknown error'}`)
+    }
+
+    bru.setVar(key, result.data.data)
+
+    return result.data.data
+  } catch (error) {
+    console.error("❌ Attribute creation failed:", error.message)
+    throw error
+  }
+}
+
+const createVariant = async (productId, data) => {
+  const key = `${productId}.${JSON.stringify(data.attributes)}`
+  const cachedVariant = bru.getVar(key)
+  if (cachedVariant) {
+    return cachedVariant
+  }
+
+  try {
+    const result = await bru.sendRequest({
+      url: `${baseUrl}/api/${apiVersion}/products/${productId}/variants`,
+      method: "POST",
+      headers: {
+        "Content-Type": "application/json",
+        "Authorization": `Bearer ${bru.getVar('jwt_token')}`
+      },
+      data
+    })
+
+    if (!result.data?.success) {
+      throw new Error(`Failed to create variant: ${result.data?.error || 'Unknown error'}`)
+    }
+
+    bru.setVar(key, result.data.data)
+
+    return result.data.data
+  } catch (error) {
+    console.error("❌ Variant creation failed:", error.message)
+    throw error
+  }
+}
+
 module.exports = {
   createProductCategory,
   createProduct,
   deleteProduct,
-  deleteProductCategory
+  deleteProductCategory,
+  createAttribute,
+  createVariant
 }
\ No newline at end of file
@@ -0,0 +1,57 @@
+const baseUrl = bru.interpolate("{{baseUrl}}");
+const apiVersion = bru.interpolate("{{apiVersion}}");
+
+const createPromotion = async (data) => {
+  const cachedPromotion = bru.getVar(data.name)
+  if (cachedPromotion) {
+    return cachedPromotion
+  }
+
+  try {
+    const result = await bru.sendRequest({
+      url: `${baseUrl}/api/${apiVersion}/promotions`,
+      method: "POST",
+      headers: {
+        "Content-Type": "application/json",
+        "Authorization": `Bearer ${bru.getVar('jwt_token')}`
+      },
+      data
+    })
+
+    if (!result.data?.success) {
+      throw new Error(`Failed to create promotion: ${result.data?.error || 'Unknown error'}`)
+    }
+
+    bru.setVar(data.name, result.data.data)
+
+    return result.data.data
+  } catch (error) {
+    console.error("❌ Promotion creation failed:", error.message)
+    throw error
+  }
+}
+
+const deletePromotion = async (promotionId) => {
+  if (!promotionId) {
+    console.warn("⚠️ No promotion ID provided for deletion")
+    return
+  }
+
+  try {
+    await bru.sendRequest({
+      url: `${baseUrl}/api/${apiVersion}/promotions/${promotionId}`,
+      method: "DELETE",
+      headers: {
+        "Authorization": `Bearer ${bru.getVar('jwt_token')}`
+      }
+    })
+  } catch (error) {
+    console.error("❌ Promotion deletion failed:", error.message)
+    throw error
+  }
+}
+
+module.exports = {
+  createPromotion,
+  deletePromotion
+}
@@ -0,0 +1,51 @@
+const baseUrl = bru.interpolate("{{baseUrl}}");
+const apiVersion = bru.interpolate("{{apiVersion}}");
+
+const createPurchaseOrder = async (data) => {
+  try {
+    const result = await bru.sendRequest({
+      url: `${baseUrl}/api/${apiVersion}/purchase-orders`,
+      method: "POST",
+      headers: {
+        "Content-Type": "application/json",
+        "Authorization": `Bearer ${bru.getVar('jwt_token')}`
+      },
+      data
+    })
+
+    if (!result.data?.success) {
+      throw new Error(`Failed to create purchase order: ${result.data?.error || 'Unknown error'}`)
+    }
+
+    return result.data.data
+  } catch (error) {
+    console.error("❌ Purchase order creation failed:", error.message)
+    throw error
+  }
+}
+
+const sendPurchaseOrder = async (orderId) => {
+  try {
+    const result = await bru.sendRequest({
+      url: `${baseUrl}/api/${apiVersion}/purchase-orders/${orderId}/send`,
+      method: "POST",
+      headers: {
+        "Authorization": `Bearer ${bru.getVar('jwt_token')}`
+      }
+    })
+
+    if (!result.data?.success) {
+      throw new Error(`Failed to send purchase order: ${result.data?.error || 'Unknown error'}`)
+    }
+
+    return result.data.data
+  } catch (error) {
+    console.error("❌ Purchase order sending failed:", error.message)
+    throw error
+  }
+}
+
+module.exports = {
+  createPurchaseOrder,
+  sendPurchaseOrder
+}
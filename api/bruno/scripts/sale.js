@@ -0,0 +1,29 @@
+const baseUrl = bru.interpolate("{{baseUrl}}");
+const apiVersion = bru.interpolate("{{apiVersion}}");
+
+const createSale = async (data) => {
+  try {
+    const result = await bru.sendRequest({
+      url: `${baseUrl}/api/${apiVersion}/sales`,
+      method: "POST",
+      headers: {
+        "Content-Type": "application/json",
+        "Authorization": `Bearer ${bru.getVar('jwt_token')}`
+      },
+      data
+    })
+
+    if (!result.data?.success) {
+      throw new Error(`Failed to create sale: ${result.data?.error || 'Unknown error'}`)
+    }
+
+    return result.data.data
+  } catch (error) {
+    console.error("❌ Sale creation failed:", error.message)
+    throw error
+  }
+}
+
+module.exports = {
+  createSale
+}
@@ -0,0 +1,52 @@
+const baseUrl = bru.interpolate("{{baseUrl}}");
+const apiVersion = bru.interpolate("{{apiVersion}}");
+
+const getCurrentShift = async () => {
+  try {
+    const result = await bru.sendRequest({
+      url: `${baseUrl}/api/${apiVersion}/shifts/current`,
+      method: "GET",
+      headers: {
+        "Authorization": `Bearer ${bru.getVar('jwt_token')}`
+      }
+    })
+
+    return result.data?.success ? result.data.data : null
+  } catch (error) {
+    // 404 means the user has no open shift
+    return null
+  }
+}
+
+const openShift = async (data = { openingFloat: 100.00 }) => {
+  const currentShift = await getCurrentShift()
+  if (currentShift) {
+    return currentShift
+  }
+
+  try {
+    const result = await bru.sendRequest({
+      url: `${baseUrl}/api/${apiVersion}/shifts`,
+      method: "POST",
+      headers: {
+        "Content-Type": "application/json",
+        "Authorization": `Bearer ${bru.getVar('jwt_token')}`
+      },
+      data
+    })
+
+    if (!result.data?.success) {
+      throw new Error(`Failed to open shift: ${result.data?.error || 'Unknown error'}`)
+    }
+
+    return result.data.data
+  } catch (error) {
+    console.error("❌ Shift opening failed:", error.message)
+    throw error
+  }
+}
+
+module.exports = {
+  getCurrentShift,
+  openShift
+}
@@ -0,0 +1,60 @@
+const baseUrl = bru.interpolate("{{baseUrl}}");
+const apiVersion = bru.interpolate("{{apiVersion}}");
+
+const createStocktake = async (data) => {
+  const cachedStocktake = bru.getVar(data.name)
+  if (cachedStocktake) {
+    return cachedStocktake
+  }
+
+  try {
+    const result = await bru.sendRequest({
+      url: `${baseUrl}/api/${apiVersion}/stocktakes`,
+      method: "POST",
+      headers: {
+        "Content-Type": "application/json",
+        "Authorization": `Bearer ${bru.getVar('jwt_token')}`
+      },
+      data
+    })
+
+    if (!result.data?.success) {
+      throw new Error(`Failed to create stocktake: ${result.data?.error || 'Unknown error'}`)
+    }
+
+    bru.setVar(data.name, result.data.data)
+
+    return result.data.data
+  } catch (error) {
+    console.error("❌ Stocktake creation failed:", error.message)
+    throw error
+  }
+}
+
+const submitCounts = async (stocktakeId, data) => {
+  try {
+    const result = await bru.sendRequest({
+      url: `${baseUrl}/api/${apiVersion}/stocktakes/${stocktakeId}/counts`,
+      method: "POST",
+      headers: {
+        "Content-Type": "application/json",
+        "Authorization": `Bearer ${bru.getVar('jwt_token')}`
+      },
+      data
+    })
+
+    if (!result.data?.success) {
+      throw new Error(`Failed to submit counts: ${result.data?.error || 'Unknown error'}`)
+    }
+
+    return result.data.data
+  } catch (error) {
+    console.error("❌ Count submission failed:", error.message)
+    throw error
+  }
+}
+
+module.exports = {
+  createStocktake,
+  submitCounts
+}
@@ -0,0 +1,57 @@
+const baseUrl = bru.interpolate("{{baseUrl}}");
+const apiVersion = bru.interpolate("{{apiVersion}}");
+
+const createSupplier = async (data) => {
+  const cachedSupplier = bru.getVar(data.name)
+  if (cachedSupplier) {
+    return cachedSupplier
+  }
+
+  try {
+    const result = await bru.sendRequest({
+      url: `${baseUrl}/api/${apiVersion}/suppliers`,
+      method: "POST",
+      headers: {
+        "Content-Type": "application/json",
+        "Authorization": `Bearer ${bru.getVar('jwt_token')}`
+      },
+      data
+    })
+
+    if (!result.data?.success) {
+      throw new Error(`Failed to create supplier: ${result.data?.error || 'Unknown error'}`)
+    }
+
+    bru.setVar(data.name, result.data.data)
+
+    return result.data.data
+  } catch (error) {
+    console.error("❌ Supplier creation failed:", error.message)
+    throw error
+  }
+}
+
+const deleteSupplier = async (supplierId) => {
+  if (!supplierId) {
+    console.warn("⚠️ No supplier ID provided for deletion")
+    return
+  }
+
+  try {
+    await bru.sendRequest({
+      url: `${baseUrl}/api/${apiVersion}/suppliers/${supplierId}`,
+      method: "DELETE",
+      headers: {
+        "Authorization": `Bearer ${bru.getVar('jwt_token')}`
+      }
+    })
+  } catch (error) {
+    console.error("❌ Supplier deletion failed:", error.message)
+    throw error
+  }
+}
+
+module.exports = {
+  createSupplier,
+  deleteSupplier
+}
@@ -0,0 +1,68 @@
+const baseUrl = bru.interpolate("{{baseUrl}}");
+const apiVersion = bru.interpolate("{{apiVersion}}");
+
+const createTaxClass = async (data) => {
+  const cachedClass = bru.getVar(data.name)
+  if (cachedClass) {
+    return cachedClass
+  }
+
+  try {
+    const result = await bru.sendRequest({
+      url: `${baseUrl}/api/${apiVersion}/tax-classes`,
+      method: "POST",
+      headers: {
+        "Content-Type": "application/json",
+        "Authorization": `Bearer ${bru.getVar('jwt_token')}`
+      },
+      data
+    })
+
+    if (!result.data?.success) {
+      throw new Error(`Failed to create tax class: ${result.data?.error || 'Unknown error'}`)
+    }
+
+    bru.setVar(data.name, result.data.data)
+
+    return result.data.data
+  } catch (error) {
+    console.error("❌ Tax class creation failed:", error.message)
+    throw error
+  }
+}
+
+const addTaxRate = async (classId, data) => {
+  const cacheKey = `${classId}.${data.name}.${data.effectiveFrom}`
+  const cachedRate = bru.getVar(cacheKey)
+  if (cachedRate) {
+    return cachedRate
+  }
+
+  try {
+    const result = await bru.sendRequest({
+      url: `${baseUrl}/api/${apiVersion}/tax-classes/${classId}/rates`,
+      method: "POST",
+      headers: {
+        "Content-Type": "application/json",
+        "Authorization": `Bearer ${bru.getVar('jwt_token')}`
+      },
+      data
+    })
+
+    if (!result.data?.success) {
+      throw new Error(`Failed to add tax rate: ${result.data?.error || 'Unknown error'}`)
+    }
+
+    bru.setVar(cacheKey, result.data.data)
+
+    return result.data.data
+  } catch (error) {
+    console.error("❌ Tax rate creation failed:", error.message)
+    throw error
+  }
+}
+
+module.exports = {
+  createTaxClass,
+  addTaxRate
+}
@@ -16,15 +16,29 @@ import (
 	"github.com/shanmugharajk/go-react-web-api/api/internal/modules/auth"
 	"github.com/shanmugharajk/go-react-web-api/api/internal/modules/customer"
 	"github.com/shanmugharajk/go-react-web-api/api/internal/modules/inventory"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/modules/location"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/modules/payment"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/modules/pricelist"
 	"github.com/shanmugharajk/go-react-web-api/api/internal/modules/product"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/modules/promotion"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/modules/purchasing"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/modules/sale"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/modules/search"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/modules/shift"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/modules/stocktake"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/modules/supplier"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/modules/tax"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/modules/transfer"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/blob"
 	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/logger"
 )
 
 // App represents the application.
 type App struct {
-	cfg    *config.Config
-	db     *db.DB
-	server *httpserver.Server
+	cfg             *config.Config
+	db              *db.DB
+	server          *httpserver.Server
+	lowStockMonitor *inventory.LowStockMonitor
 }
 
 // New creates a new application instance.
@@ -47,21 +61,109 @@ func New() (*App, error) {
 	// Auto-migrate models (order matters for FK constraints)
 	if err := database.AutoMigrate(
 		&auth.User{},
+		&tax.Class{},
+		&tax.ClassRate{},
 		&product.ProductCategory{},
+		&product.Attribute{},
+		&product.AttributeValue{},
+		&pricelist.PriceList{},
+		&pricelist.Price{},
 		&customer.Customer{},
+		&customer.LedgerEntry{},
 		&product.Product{},
+		&product.Barcode{},
+		&product.Unit{},
+		&product.VariantAttribute{},
+		&product.KitComponent{},
+		&product.Image{},
+		&location.Location{},
 		&inventory.ProductBatch{},
+		&inventory.StockMovement{},
+		&inventory.StockAdjustment{},
+		&inventory.AdjustmentLine{},
+		&inventory.ReorderRule{},
+		&inventory.StockAlert{},
+		&stocktake.Stocktake{},
+		&stocktake.Line{},
+		&stocktake.Count{},
+		&transfer.Transfer{},
+		&transfer.Line{},
+		&supplier.Supplier{},
+		&purchasing.PurchaseOrder{},
+		&purchasing.PurchaseOrderLine{},
+		&purchasing.GoodsReceipt{},
+		&purchasing.GoodsReceiptLine{},
+		&promotion.Promotion{},
+		&sale.Sale{},
+		&sale.SaleLine{},
+		&sale.SaleDiscount{},
+		&sale.SaleTax{},
+		&sale.SaleReturn{},
+		&sale.SaleReturnLine{},
+		&shift.Shift{},
+		&shift.ShiftTotal{},
+		&shift.CashMovement{},
+		&shift.ZReport{},
+		&payment.Payment{},
 	); err != nil {
 		return nil, fmt.Errorf("failed to run auto-migrations: %w", err)
 	}
 
+	// Run one-off data migrations. Money columns are converted to minor units
+	// and quantities to thousandths first so the later migrations read them
+	// in their current form.
+	if err := database.RunMigrations(
+		inventory.MigrateMoneyToMinorUnits,
+		customer.MigrateMoneyToMinorUnits,
+		sale.MigrateMoneyToMinorUnits,
+		shift.MigrateMoneyToMinorUnits,
+		payment.MigrateMoneyToMinorUnits,
+		inventory.MigrateQuantitiesToThousandths,
+		stocktake.MigrateQuantitiesToThousandths,
+		transfer.MigrateQuantitiesToThousandths,
+		purchasing.MigrateQuantitiesToThousandths,
+		sale.MigrateQuantitiesToThousandths,
+		inventory.MigrateOpeningMovements,
+		customer.MigrateBalancesToLedger,
+		sale.MigrateCompleteExistingSales,
+		location.MigrateDefaultLocation,
+		purchasing.MigrateOrderLineUnits,
+		inventory.MigrateBatchTimesToUTC,
+	); err != nil {
+		return nil, fmt.Errorf("failed to run data migrations: %w", err)
+	}
+
+	// Set up full-text search, which needs SQLite built with FTS5
+	if err := search.Setup(database); err != nil {
+		if !errors.Is(err, search.ErrUnavailable) {
+			return nil, fmt.Errorf("failed to set up search: %w", err)
+		}
+		logger.Warn("Search is unavailable: SQLite was built without FTS5 (build with -tags sqlite_fts5)")
+	}
+
+	// Initialize blob storage for uploaded files
+	blobs, err := blob.NewDiskStore(cfg.Storage.Dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize blob storage: %w", err)
+	}
+
 	// Create HTTP server
-	server := httpserver.New(cfg, database)
+	server := httpserver.New(cfg, database, blobs)
+
+	// Start the low-stock check unless it is disabled
+	var lowStockMonitor *inventory.LowStockMonitor
+	if cfg.Inventory.LowStockCheckInterval > 0 {
+		lowStockMonitor = inventory.NewLowStockMonitor(
+			database,
+			time.Duration(cfg.Inventory.LowStockCheckInterval)*time.Second,
+		)
+	}
 
 	return &App{
-		cfg:    cfg,
-		db:     database,
-		server: server,
+		cfg:             cfg,
+		db:              database,
+		server:          server,
+		lowStockMonitor: lowStockMonitor,
 	}, nil
 }
 
@@ -100,6 +202,11 @@ func (a *App) Run() error {
 			return fmt.Errorf("graceful shutdown failed: %w", err)
 		}
 
+		// Stop the low-stock check before the database goes away
+		if a.lowStockMonitor != nil {
+			a.lowStockMonitor.Stop()
+		}
+
 		// Close database connection
 		if err := a.db.Close(); err != nil {
 			logger.Error("Failed to close database", "error", err)
@@ -0,0 +1,14 @@
+package common
+
+import "time"
+
+// UTC returns an optional time in UTC, the zone times are stored and
+// compared in. SQLite compares stored times as text, so times in other
+// zones would not compare in time order.
+func UTC(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	u := t.UTC()
+	return &u
+}
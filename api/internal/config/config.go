@@ -4,8 +4,10 @@ import (
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
 
 	"github.com/joho/godotenv"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/money"
 )
 
 // Config holds all application configuration.
@@ -14,6 +16,11 @@ type Config struct {
 	Server        ServerConfig
 	Database      DatabaseConfig
 	Auth          AuthConfig
+	Receipt       ReceiptConfig
+	Tax           TaxConfig
+	Inventory     InventoryConfig
+	Storage       StorageConfig
+	Image         ImageConfig
 }
 
 // ServerConfig holds HTTP server configuration.
@@ -37,6 +44,40 @@ type AuthConfig struct {
 	TrustProxy      bool   // Whether to trust X-Forwarded-For and X-Real-IP headers
 }
 
+// ReceiptConfig holds receipt layout configuration.
+type ReceiptConfig struct {
+	StoreName   string   // Printed at the top of every receipt
+	HeaderLines []string // Lines printed under the store name (address, tax number, etc.)
+	FooterLines []string // Lines printed at the end of the receipt
+	PaperWidth  int      // Characters per line (42 for 80mm paper, 32 for 58mm)
+}
+
+// TaxConfig holds tax calculation configuration.
+type TaxConfig struct {
+	PricesIncludeTax bool   // Whether selling prices already include tax
+	Rounding         string // "line" rounds the tax on each line, "invoice" rounds once per rate on the sale total
+}
+
+// InventoryConfig holds stock control configuration.
+type InventoryConfig struct {
+	AdjustmentApprovalThreshold money.Amount // Adjustments worth more than this at cost need a second user's approval
+	LowStockCheckInterval       int          // Seconds between low-stock alert checks (0 disables the check)
+	ExpiryMarkdownDays          int          // Batches this many days or fewer from expiry sell at a markdown (0 disables markdowns)
+	ExpiryMarkdownPercent       int          // Percentage taken off the price of batches near expiry
+}
+
+// StorageConfig holds blob storage configuration.
+type StorageConfig struct {
+	Dir string // Directory uploaded files are kept in
+}
+
+// ImageConfig holds product image configuration.
+type ImageConfig struct {
+	MaxBytes      int // Largest image file accepted, in bytes
+	MaxDimension  int // Largest width or height accepted, in pixels
+	ThumbnailSize int // Thumbnails fit in a square this many pixels wide
+}
+
 // Load reads configuration from environment variables.
 func Load() (*Config, error) {
 	// Load .env file if it exists
@@ -59,6 +100,30 @@ func Load() (*Config, error) {
 			JWTDuration:     getEnvAsInt("AUTH_JWT_DURATION", 86400),     // 24 hours
 			TrustProxy:      getEnvAsBool("AUTH_TRUST_PROXY", false),     // Only trust proxy headers if explicitly enabled
 		},
+		Receipt: ReceiptConfig{
+			StoreName:   getEnv("RECEIPT_STORE_NAME", "POS"),
+			HeaderLines: getEnvAsList("RECEIPT_HEADER_LINES", nil), // Lines separated by "|"
+			FooterLines: getEnvAsList("RECEIPT_FOOTER_LINES", []string{"Thank you!"}),
+			PaperWidth:  getEnvAsInt("RECEIPT_PAPER_WIDTH", 42),
+		},
+		Tax: TaxConfig{
+			PricesIncludeTax: getEnvAsBool("TAX_PRICES_INCLUDE_TAX", false),
+			Rounding:         getEnv("TAX_ROUNDING", "line"),
+		},
+		Inventory: InventoryConfig{
+			AdjustmentApprovalThreshold: getEnvAsAmount("INVENTORY_ADJUSTMENT_APPROVAL_THRESHOLD", 100 * money.Scale),
+			LowStockCheckInterval:       getEnvAsInt("INVENTORY_LOW_STOCK_CHECK_INTERVAL", 300),
+			ExpiryMarkdownDays:          getEnvAsInt("INVENTORY_EXPIRY_MARKDOWN_DAYS", 0),
+			ExpiryMarkdownPercent:       getEnvAsInt("INVENTORY_EXPIRY_MARKDOWN_PERCENT", 0),
+		},
+		Storage: StorageConfig{
+			Dir: getEnv("STORAGE_DIR", "./data/blobs"),
+		},
+		Image: ImageConfig{
+			MaxBytes:      getEnvAsInt("IMAGE_MAX_BYTES", 5<<20), // 5 MiB
+			MaxDimension:  getEnvAsInt("IMAGE_MAX_DIMENSION", 4096),
+			ThumbnailSize: getEnvAsInt("IMAGE_THUMBNAIL_SIZE", 256),
+		},
 	}
 
 	// Validate configuration
@@ -97,6 +162,24 @@ func getEnvAsBool(key string, defaultValue bool) bool {
 	return defaultValue
 }
 
+// getEnvAsAmount retrieves an environment variable as a money amount in major units or returns a default value.
+func getEnvAsAmount(key string, defaultValue money.Amount) money.Amount {
+	if value := os.Getenv(key); value != "" {
+		if amount, err := money.Parse(value); err == nil {
+			return amount
+		}
+	}
+	return defaultValue
+}
+
+// getEnvAsList retrieves an environment variable as a "|"-separated list or returns a default value.
+func getEnvAsList(key string, defaultValue []string) []string {
+	if value := os.Getenv(key); value != "" {
+		return strings.Split(value, "|")
+	}
+	return defaultValue
+}
+
 // ServerAddr returns the full server address.
 func (c *Config) ServerAddr() string {
 	return fmt.Sprintf("%s:%d", c.Server.Host, c.Server.Port)
@@ -133,6 +216,45 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("AUTH_JWT_DURATION cannot exceed 30 days (2592000 seconds)")
 	}
 
+	// Validate receipt paper width
+	if c.Receipt.PaperWidth < 24 || c.Receipt.PaperWidth > 80 {
+		return fmt.Errorf("RECEIPT_PAPER_WIDTH must be between 24 and 80 characters")
+	}
+
+	// Validate adjustment approval threshold
+	if c.Inventory.AdjustmentApprovalThreshold < 0 {
+		return fmt.Errorf("INVENTORY_ADJUSTMENT_APPROVAL_THRESHOLD cannot be negative")
+	}
+
+	// Validate low-stock check interval
+	if c.Inventory.LowStockCheckInterval < 0 {
+		return fmt.Errorf("INVENTORY_LOW_STOCK_CHECK_INTERVAL cannot be negative")
+	}
+
+	// Validate expiry markdown
+	if c.Inventory.ExpiryMarkdownDays < 0 {
+		return fmt.Errorf("INVENTORY_EXPIRY_MARKDOWN_DAYS cannot be negative")
+	}
+	if c.Inventory.ExpiryMarkdownPercent < 0 || c.Inventory.ExpiryMarkdownPercent > 100 {
+		return fmt.Errorf("INVENTORY_EXPIRY_MARKDOWN_PERCENT must be between 0 and 100")
+	}
+
+	// Validate image limits
+	if c.Image.MaxBytes < 1 {
+		return fmt.Errorf("IMAGE_MAX_BYTES must be positive")
+	}
+	if c.Image.MaxDimension < 1 {
+		return fmt.Errorf("IMAGE_MAX_DIMENSION must be positive")
+	}
+	if c.Image.ThumbnailSize < 1 || c.Image.ThumbnailSize > c.Image.MaxDimension {
+		return fmt.Errorf("IMAGE_THUMBNAIL_SIZE must be between 1 and IMAGE_MAX_DIMENSION")
+	}
+
+	// Validate tax rounding mode
+	if c.Tax.Rounding != "line" && c.Tax.Rounding != "invoice" {
+		return fmt.Errorf("TAX_ROUNDING must be either line or invoice")
+	}
+
 	return nil
 }
 
@@ -39,6 +39,14 @@ func New(dsn string) (*DB, error) {
 	return &DB{DB: gormDB}, nil
 }
 
+// Transaction runs fn inside a database transaction. The transaction is
+// committed if fn returns nil and rolled back otherwise.
+func (db *DB) Transaction(fn func(tx *DB) error) error {
+	return db.DB.Transaction(func(tx *gorm.DB) error {
+		return fn(&DB{DB: tx})
+	})
+}
+
 // Close closes the database connection.
 func (db *DB) Close() error {
 	sqlDB, err := db.DB.DB()
@@ -0,0 +1,54 @@
+package db
+
+import (
+	"fmt"
+	"time"
+)
+
+// Migration is a one-off data migration that runs after schema auto-migration.
+// IDs must be unique and never change once a migration has shipped.
+type Migration struct {
+	ID string
+	Up func(tx *DB) error
+}
+
+// schemaMigration records an applied migration.
+type schemaMigration struct {
+	ID        string    `gorm:"primaryKey"`
+	AppliedAt time.Time `gorm:"not null"`
+}
+
+// TableName specifies the table name for applied migrations.
+func (schemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+// RunMigrations applies the migrations that have not been recorded yet, in
+// order. Each migration runs in its own transaction together with its record.
+func (db *DB) RunMigrations(migrations ...Migration) error {
+	if err := db.AutoMigrate(&schemaMigration{}); err != nil {
+		return fmt.Errorf("failed to create migrations table: %w", err)
+	}
+
+	for _, m := range migrations {
+		var count int64
+		if err := db.Model(&schemaMigration{}).Where("id = ?", m.ID).Count(&count).Error; err != nil {
+			return fmt.Errorf("failed to check migration %s: %w", m.ID, err)
+		}
+		if count > 0 {
+			continue
+		}
+
+		err := db.Transaction(func(tx *DB) error {
+			if err := m.Up(tx); err != nil {
+				return err
+			}
+			return tx.Create(&schemaMigration{ID: m.ID, AppliedAt: time.Now()}).Error
+		})
+		if err != nil {
+			return fmt.Errorf("migration %s failed: %w", m.ID, err)
+		}
+	}
+
+	return nil
+}
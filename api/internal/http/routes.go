@@ -9,7 +9,20 @@ import (
 	"github.com/shanmugharajk/go-react-web-api/api/internal/modules/auth"
 	"github.com/shanmugharajk/go-react-web-api/api/internal/modules/customer"
 	"github.com/shanmugharajk/go-react-web-api/api/internal/modules/inventory"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/modules/location"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/modules/payment"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/modules/pricelist"
 	"github.com/shanmugharajk/go-react-web-api/api/internal/modules/product"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/modules/promotion"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/modules/purchasing"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/modules/receipt"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/modules/sale"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/modules/search"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/modules/shift"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/modules/stocktake"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/modules/supplier"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/modules/tax"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/modules/transfer"
 	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/response"
 )
 
@@ -122,26 +135,167 @@ func (s *Server) setupRoutes() {
 			// Get current user
 			r.Get("/auth/me", authHandler.GetCurrentUser)
 
-			// Product read operations
-			productHandler := product.NewHandler(s.db)
+			// Product read operations. Products can be filtered by the
+			// categoryId query parameter, which takes in its subcategories.
+			// Lookup finds a product by a scanned barcode or by SKU.
+			// Variants are the sizes, colours and so on a product comes in.
+			productHandler := product.NewHandler(s.db, s.blobs)
 			r.Get("/products", productHandler.GetAll)
+			r.Get("/products/lookup", productHandler.Lookup)
 			r.Get("/products/{id}", productHandler.GetByID)
+			r.Get("/products/{id}/variants", productHandler.GetVariants)
 
-			// Product category read operations
+			// Product image read operations. Image files and thumbnails
+			// never change under their URLs, so clients may cache them.
+			imageHandler := product.NewImageHandler(s.db, s.blobs, s.config.Image)
+			r.Get("/products/{id}/images", imageHandler.GetByProductID)
+			r.Get("/products/{id}/images/{imageId}", imageHandler.Serve)
+			r.Get("/products/{id}/images/{imageId}/thumbnail", imageHandler.ServeThumbnail)
+
+			// Product category read operations. The tree nests each
+			// category's subcategories under it.
 			categoryHandler := product.NewCategoryHandler(s.db)
 			r.Get("/products/categories", categoryHandler.GetAll)
+			r.Get("/products/categories/tree", categoryHandler.GetTree)
 			r.Get("/products/categories/{id}", categoryHandler.GetByID)
 
-			// Inventory (batches) read operations
+			// Tax class read operations
+			taxHandler := tax.NewHandler(s.db)
+			r.Get("/tax-classes", taxHandler.GetAll)
+			r.Get("/tax-classes/{id}", taxHandler.GetByID)
+
+			// Promotion read operations
+			promotionHandler := promotion.NewHandler(s.db)
+			r.Get("/promotions", promotionHandler.GetAll)
+			r.Get("/promotions/{id}", promotionHandler.GetByID)
+
+			// Price list read operations
+			priceListHandler := pricelist.NewHandler(s.db)
+			r.Get("/price-lists", priceListHandler.GetAll)
+			r.Get("/price-lists/resolve", priceListHandler.Resolve)
+			r.Get("/price-lists/{id}", priceListHandler.GetByID)
+			r.Get("/price-lists/{id}/customers", priceListHandler.GetCustomers)
+
+			// Supplier read operations
+			supplierHandler := supplier.NewHandler(s.db)
+			r.Get("/suppliers", supplierHandler.GetAll)
+			r.Get("/suppliers/{id}", supplierHandler.GetByID)
+
+			// Purchase order read operations
+			purchaseOrderHandler := purchasing.NewHandler(s.db)
+			r.Get("/purchase-orders", purchaseOrderHandler.GetAll)
+			r.Get("/purchase-orders/{id}", purchaseOrderHandler.GetByID)
+			r.Get("/purchase-orders/{id}/receipts", purchaseOrderHandler.GetReceipts)
+
+			// Location read operations
+			locationHandler := location.NewHandler(s.db)
+			r.Get("/locations", locationHandler.GetAll)
+			r.Get("/locations/{id}", locationHandler.GetByID)
+			r.Get("/locations/{id}/users", locationHandler.GetUsers)
+
+			// Inventory (batches) read operations. Batches, the variant
+			// grid, the stock of each of a product's variants, the number
+			// of a kit its components make up, and the stock value per
+			// category can be filtered by the locationId query parameter.
+			// Stock by category rolls categories up to the level query
+			// parameter, 0 being the top of the tree.
 			batchHandler := inventory.NewBatchHandler(s.db)
 			r.Get("/inventory/batches", batchHandler.GetAll)
 			r.Get("/inventory/batches/{id}", batchHandler.GetByID)
 			r.Get("/inventory/batches/product/{productId}", batchHandler.GetByProductID)
+			r.Get("/inventory/batches/{id}/reconciliation", batchHandler.Reconcile)
+			r.Get("/inventory/variant-grid/{productId}", batchHandler.VariantGrid)
+			r.Get("/inventory/kit-availability/{productId}", batchHandler.KitAvailability)
+			r.Get("/inventory/stock-by-category", batchHandler.StockByCategory)
+
+			// Stock movement ledger read operations
+			movementHandler := inventory.NewMovementHandler(s.db)
+			r.Get("/inventory/batches/{id}/movements", movementHandler.GetByBatchID)
+			r.Get("/inventory/movements/product/{productId}", movementHandler.GetByProductID)
+
+			// Inventory allocation preview (FEFO/FIFO batch picking)
+			allocationHandler := inventory.NewAllocationHandler(s.db)
+			r.Get("/inventory/allocations/preview", allocationHandler.Preview)
+
+			// Stock adjustment read operations
+			adjustmentHandler := inventory.NewAdjustmentHandler(s.db, s.config.Inventory)
+			r.Get("/inventory/adjustments", adjustmentHandler.GetAll)
+			r.Get("/inventory/adjustments/{id}", adjustmentHandler.GetByID)
+
+			// Reorder rule and low-stock read operations. Both can be
+			// filtered by the locationId query parameter.
+			reorderRuleHandler := inventory.NewReorderRuleHandler(s.db)
+			r.Get("/inventory/reorder-rules", reorderRuleHandler.GetAll)
+			r.Get("/inventory/reorder-rules/{id}", reorderRuleHandler.GetByID)
+			r.Get("/inventory/low-stock", reorderRuleHandler.LowStock)
+
+			// Stock alert read operations. Alerts are raised and resolved
+			// by the background low-stock check.
+			alertHandler := inventory.NewAlertHandler(s.db)
+			r.Get("/inventory/alerts", alertHandler.GetAll)
+			r.Get("/inventory/alerts/{id}", alertHandler.GetByID)
+
+			// Expiry report read operations. Expiring batches are those that
+			// expire within the within query parameter (30d by default).
+			expiryHandler := inventory.NewExpiryHandler(s.db, s.config.Inventory)
+			r.Get("/inventory/expiring", expiryHandler.GetExpiring)
+			r.Get("/inventory/expired", expiryHandler.GetExpired)
+
+			// Stocktake read operations. Blind counts hide their expected
+			// quantities until they are finalized.
+			stocktakeHandler := stocktake.NewHandler(s.db, s.config.Inventory)
+			r.Get("/stocktakes", stocktakeHandler.GetAll)
+			r.Get("/stocktakes/{id}", stocktakeHandler.GetByID)
+			r.Get("/stocktakes/{id}/variance", stocktakeHandler.Variance)
+
+			// Transfer read operations
+			transferHandler := transfer.NewHandler(s.db)
+			r.Get("/transfers", transferHandler.GetAll)
+			r.Get("/transfers/{id}", transferHandler.GetByID)
 
 			// Customer read operations
 			customerHandler := customer.NewHandler(s.db)
 			r.Get("/customers", customerHandler.GetAll)
 			r.Get("/customers/{id}", customerHandler.GetByID)
+
+			// Search over products and customers. Results match every word
+			// of the q query parameter as a prefix, best first, and can be
+			// limited to the type query parameter (product or customer).
+			searchHandler := search.NewHandler(s.db)
+			r.Get("/search", searchHandler.Search)
+
+			// Customer ledger read operations
+			ledgerHandler := customer.NewLedgerHandler(s.db)
+			r.Get("/customers/{id}/ledger", ledgerHandler.GetLedger)
+
+			// Sale read operations. Sales by category rolls categories up
+			// to the level query parameter and takes from and to dates.
+			saleHandler := sale.NewHandler(s.db, s.config.Tax, s.config.Inventory)
+			r.Get("/sales", saleHandler.GetAll)
+			r.Get("/sales/by-category", saleHandler.ByCategory)
+			r.Get("/sales/{id}", saleHandler.GetByID)
+
+			// Sale return read operations
+			returnHandler := sale.NewReturnHandler(s.db)
+			r.Get("/sales/{id}/returns", returnHandler.GetBySaleID)
+
+			// Sale payment read operations
+			paymentHandler := payment.NewHandler(s.db)
+			r.Get("/sales/{id}/payments", paymentHandler.GetBySaleID)
+
+			// Sale receipts (text, HTML or raw ESC/POS)
+			receiptHandler := receipt.NewHandler(s.db, s.config.Receipt)
+			r.Get("/sales/{id}/receipt", receiptHandler.GetBySaleID)
+
+			// Shift read operations
+			shiftHandler := shift.NewHandler(s.db)
+			r.Get("/shifts", shiftHandler.GetAll)
+			r.Get("/shifts/current", shiftHandler.GetCurrent)
+			r.Get("/shifts/{id}", shiftHandler.GetByID)
+
+			// Z-report reprints
+			zReportHandler := shift.NewZReportHandler(s.db)
+			r.Get("/shifts/{id}/z-report", zReportHandler.GetByShiftID)
 		})
 
 		// =================================================================
@@ -155,16 +309,78 @@ func (s *Server) setupRoutes() {
 			r.Use(RequireAuth(s.sessionStore, s.jwtService, s.authService))
 
 			// Product mutations
-			productHandler := product.NewHandler(s.db)
+			productHandler := product.NewHandler(s.db, s.blobs)
 			r.Post("/products", productHandler.Create)
 			r.Put("/products/{id}", productHandler.Update)
 			r.Delete("/products/{id}", productHandler.Delete)
-
-			// Product category mutations
+			r.Post("/products/{id}/variants", productHandler.CreateVariant)
+			r.Put("/products/{id}/variants/{variantId}", productHandler.UpdateVariant)
+
+			// Product image mutations. Images are uploaded as the image
+			// field of a multipart form; the first in order is the
+			// product's primary image.
+			imageHandler := product.NewImageHandler(s.db, s.blobs, s.config.Image)
+			r.Post("/products/{id}/images", imageHandler.Upload)
+			r.Put("/products/{id}/images/order", imageHandler.Reorder)
+			r.Delete("/products/{id}/images/{imageId}", imageHandler.Delete)
+
+			// Product category mutations. Attributes are the ways the
+			// variants of the category's products differ; moving a
+			// category moves its subcategories with it.
 			categoryHandler := product.NewCategoryHandler(s.db)
 			r.Post("/products/categories", categoryHandler.Create)
 			r.Put("/products/categories/{id}", categoryHandler.Update)
 			r.Delete("/products/categories/{id}", categoryHandler.Delete)
+			r.Post("/products/categories/{id}/move", categoryHandler.Move)
+			r.Post("/products/categories/{id}/attributes", categoryHandler.CreateAttribute)
+			r.Put("/products/categories/{id}/attributes/{attributeId}", categoryHandler.UpdateAttribute)
+			r.Delete("/products/categories/{id}/attributes/{attributeId}", categoryHandler.DeleteAttribute)
+
+			// Tax class mutations
+			taxHandler := tax.NewHandler(s.db)
+			r.Post("/tax-classes", taxHandler.Create)
+			r.Put("/tax-classes/{id}", taxHandler.Update)
+			r.Post("/tax-classes/{id}/rates", taxHandler.AddRate)
+
+			// Promotion mutations. Apply only previews discounts but takes a
+			// cart in its body, so it is posted like the mutations.
+			promotionHandler := promotion.NewHandler(s.db)
+			r.Post("/promotions", promotionHandler.Create)
+			r.Post("/promotions/apply", promotionHandler.Apply)
+			r.Put("/promotions/{id}", promotionHandler.Update)
+			r.Delete("/promotions/{id}", promotionHandler.Delete)
+
+			// Price list mutations
+			priceListHandler := pricelist.NewHandler(s.db)
+			r.Post("/price-lists", priceListHandler.Create)
+			r.Put("/price-lists/{id}", priceListHandler.Update)
+			r.Post("/price-lists/{id}/prices", priceListHandler.AddPrice)
+			r.Put("/price-lists/{id}/customers/{customerId}", priceListHandler.AssignCustomer)
+			r.Delete("/price-lists/{id}/customers/{customerId}", priceListHandler.UnassignCustomer)
+
+			// Supplier mutations
+			supplierHandler := supplier.NewHandler(s.db)
+			r.Post("/suppliers", supplierHandler.Create)
+			r.Put("/suppliers/{id}", supplierHandler.Update)
+			r.Delete("/suppliers/{id}", supplierHandler.Delete)
+
+			// Purchase order mutations. Receiving goods creates the batches
+			// they are stocked in.
+			purchaseOrderHandler := purchasing.NewHandler(s.db)
+			r.Post("/purchase-orders", purchaseOrderHandler.Create)
+			r.Put("/purchase-orders/{id}", purchaseOrderHandler.Update)
+			r.Post("/purchase-orders/{id}/send", purchaseOrderHandler.Send)
+			r.Post("/purchase-orders/{id}/receipts", purchaseOrderHandler.Receive)
+			r.Post("/purchase-orders/{id}/close", purchaseOrderHandler.Close)
+
+			// Location mutations. Assigning a user to a location makes it
+			// their default location.
+			locationHandler := location.NewHandler(s.db)
+			r.Post("/locations", locationHandler.Create)
+			r.Put("/locations/{id}", locationHandler.Update)
+			r.Delete("/locations/{id}", locationHandler.Delete)
+			r.Put("/locations/{id}/users/{userId}", locationHandler.AssignUser)
+			r.Delete("/locations/{id}/users/{userId}", locationHandler.UnassignUser)
 
 			// Inventory (batches) mutations
 			batchHandler := inventory.NewBatchHandler(s.db)
@@ -172,11 +388,66 @@ func (s *Server) setupRoutes() {
 			r.Put("/inventory/batches/{id}", batchHandler.Update)
 			r.Delete("/inventory/batches/{id}", batchHandler.Delete)
 
+			// Stock adjustment mutations. Adjustments above the approval
+			// threshold wait for a second user to approve them.
+			adjustmentHandler := inventory.NewAdjustmentHandler(s.db, s.config.Inventory)
+			r.Post("/inventory/adjustments", adjustmentHandler.Create)
+			r.Post("/inventory/adjustments/{id}/approve", adjustmentHandler.Approve)
+			r.Post("/inventory/adjustments/{id}/reject", adjustmentHandler.Reject)
+			r.Post("/inventory/adjustments/{id}/reverse", adjustmentHandler.Reverse)
+
+			// Reorder rule mutations
+			reorderRuleHandler := inventory.NewReorderRuleHandler(s.db)
+			r.Post("/inventory/reorder-rules", reorderRuleHandler.Create)
+			r.Put("/inventory/reorder-rules/{id}", reorderRuleHandler.Update)
+			r.Delete("/inventory/reorder-rules/{id}", reorderRuleHandler.Delete)
+
+			// Expired stock mutations. Expired stock is quarantined, then
+			// written off through a stock adjustment.
+			expiryHandler := inventory.NewExpiryHandler(s.db, s.config.Inventory)
+			r.Post("/inventory/expired/quarantine", expiryHandler.Quarantine)
+			r.Post("/inventory/expired/write-off", expiryHandler.WriteOff)
+
+			// Stocktake mutations. Finalizing posts the variances as a stock
+			// adjustment.
+			stocktakeHandler := stocktake.NewHandler(s.db, s.config.Inventory)
+			r.Post("/stocktakes", stocktakeHandler.Create)
+			r.Post("/stocktakes/{id}/counts", stocktakeHandler.SubmitCounts)
+			r.Post("/stocktakes/{id}/finalize", stocktakeHandler.Finalize)
+			r.Post("/stocktakes/{id}/cancel", stocktakeHandler.Cancel)
+
+			// Transfer mutations. Stock leaves the source location when a
+			// transfer is created and arrives when it is received.
+			transferHandler := transfer.NewHandler(s.db)
+			r.Post("/transfers", transferHandler.Create)
+			r.Post("/transfers/{id}/receive", transferHandler.Receive)
+			r.Post("/transfers/{id}/cancel", transferHandler.Cancel)
+
 			// Customer mutations
 			customerHandler := customer.NewHandler(s.db)
 			r.Post("/customers", customerHandler.Create)
 			r.Put("/customers/{id}", customerHandler.Update)
 			r.Delete("/customers/{id}", customerHandler.Delete)
+
+			// Customer ledger postings
+			ledgerHandler := customer.NewLedgerHandler(s.db)
+			r.Post("/customers/{id}/ledger/payments", ledgerHandler.RecordPayment)
+			r.Post("/customers/{id}/ledger/adjustments", ledgerHandler.RecordAdjustment)
+
+			// Sale mutations
+			saleHandler := sale.NewHandler(s.db, s.config.Tax, s.config.Inventory)
+			r.Post("/sales", saleHandler.Create)
+			r.Post("/sales/{id}/payments", saleHandler.Pay)
+
+			// Sale returns
+			returnHandler := sale.NewReturnHandler(s.db)
+			r.Post("/sales/{id}/returns", returnHandler.Create)
+
+			// Shift mutations
+			shiftHandler := shift.NewHandler(s.db)
+			r.Post("/shifts", shiftHandler.Open)
+			r.Post("/shifts/{id}/cash-movements", shiftHandler.AddCashMovement)
+			r.Post("/shifts/{id}/close", shiftHandler.Close)
 		})
 	})
 }
@@ -10,6 +10,7 @@ import (
 	"github.com/shanmugharajk/go-react-web-api/api/internal/config"
 	"github.com/shanmugharajk/go-react-web-api/api/internal/db"
 	"github.com/shanmugharajk/go-react-web-api/api/internal/modules/auth"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/blob"
 	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/jwt"
 	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/logger"
 	"github.com/shanmugharajk/go-react-web-api/api/internal/sessions"
@@ -24,10 +25,11 @@ type Server struct {
 	sessionStore *sessions.SQLiteStore
 	jwtService   *jwt.TokenService
 	authService  *auth.Service
+	blobs        blob.Store
 }
 
-// New creates a new HTTP server instance.
-func New(cfg *config.Config, database *db.DB) *Server {
+// New creates a new HTTP server instance. Uploaded files are kept in blobs.
+func New(cfg *config.Config, database *db.DB, blobs blob.Store) *Server {
 	// Initialize session store with 1-hour cleanup interval
 	sessionStore := sessions.NewStore(
 		database.DB,
@@ -48,6 +50,7 @@ func New(cfg *config.Config, database *db.DB) *Server {
 		sessionStore: sessionStore,
 		jwtService:   jwtService,
 		authService:  authService,
+		blobs:        blobs,
 	}
 
 	// Setup middleware
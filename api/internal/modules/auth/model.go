@@ -14,6 +14,10 @@ type User struct {
 	Name         string    `gorm:"not null" json:"name"`
 	CreatedAt    time.Time `json:"createdAt"`
 	UpdatedAt    time.Time `json:"updatedAt"`
+
+	// DefaultLocationID is the location the user works at. Stock they sell or
+	// receive without naming a location comes from or goes to it.
+	DefaultLocationID *uuid.UUID `gorm:"type:char(36);index" json:"defaultLocationId"`
 }
 
 // LoginRequest represents a login request payload.
@@ -3,6 +3,7 @@ package auth
 import (
 	"github.com/google/uuid"
 	"github.com/shanmugharajk/go-react-web-api/api/internal/db"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/errors"
 )
 
 // Repository handles data access for auth.
@@ -41,3 +42,40 @@ func (r *Repository) FindByID(id uuid.UUID) (*User, error) {
 	}
 	return &user, nil
 }
+
+// FindByDefaultLocationID retrieves the users whose default location is the
+// given one.
+func (r *Repository) FindByDefaultLocationID(locationID uuid.UUID) ([]User, error) {
+	var users []User
+	if err := r.db.Where("default_location_id = ?", locationID).Order("name ASC").Find(&users).Error; err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// AssignDefaultLocation sets a user's default location, replacing any
+// location they were assigned to.
+func (r *Repository) AssignDefaultLocation(id, locationID uuid.UUID) error {
+	result := r.db.Model(&User{}).Where("id = ?", id).Update("default_location_id", locationID)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.ErrNotFound
+	}
+	return nil
+}
+
+// UnassignDefaultLocation clears a user's default location. It fails with
+// ErrNotFound unless the user is assigned to that location.
+func (r *Repository) UnassignDefaultLocation(id, locationID uuid.UUID) error {
+	result := r.db.Model(&User{}).Where("id = ? AND default_location_id = ?", id, locationID).
+		Update("default_location_id", nil)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.ErrNotFound
+	}
+	return nil
+}
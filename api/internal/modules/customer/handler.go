@@ -21,7 +21,7 @@ type Handler struct {
 // NewHandler creates a new Handler instance.
 func NewHandler(database *db.DB) *Handler {
 	repo := NewCustomerRepository(database)
-	service := NewCustomerService(repo)
+	service := NewCustomerService(database, repo)
 	return &Handler{service: service}
 }
 
@@ -149,3 +149,123 @@ func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) {
 
 	response.NoContent(w)
 }
+
+// LedgerHandler handles HTTP requests for the customer ledger.
+type LedgerHandler struct {
+	service *LedgerService
+}
+
+// NewLedgerHandler creates a new LedgerHandler instance.
+func NewLedgerHandler(database *db.DB) *LedgerHandler {
+	customers := NewCustomerRepository(database)
+	repo := NewLedgerRepository(database)
+	service := NewLedgerService(customers, repo)
+	return &LedgerHandler{service: service}
+}
+
+// Routes returns the customer ledger routes, mounted under a customer.
+func (h *LedgerHandler) Routes() chi.Router {
+	r := chi.NewRouter()
+	r.Get("/", h.GetLedger)
+	r.Post("/payments", h.RecordPayment)
+	r.Post("/adjustments", h.RecordAdjustment)
+	return r
+}
+
+// GetLedger retrieves a customer's running-balance history.
+func (h *LedgerHandler) GetLedger(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid customer ID")
+		return
+	}
+
+	ledger, err := h.service.GetLedger(id)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			response.Error(w, http.StatusNotFound, "Customer not found")
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, "Failed to fetch customer ledger")
+		return
+	}
+
+	response.Success(w, ledger)
+}
+
+// RecordPayment records a payment received on a customer's account.
+func (h *LedgerHandler) RecordPayment(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid customer ID")
+		return
+	}
+
+	var req RecordPaymentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	user, err := auth.GetUserFromContext(r.Context())
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "Failed to retrieve user")
+		return
+	}
+
+	entry, err := h.service.RecordPayment(id, req, user)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			response.Error(w, http.StatusNotFound, "Customer not found")
+			return
+		}
+		if validator.IsValidationError(err) {
+			response.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, "Failed to record payment")
+		return
+	}
+
+	response.Created(w, entry)
+}
+
+// RecordAdjustment posts a manual correction to a customer's account.
+func (h *LedgerHandler) RecordAdjustment(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid customer ID")
+		return
+	}
+
+	var req RecordAdjustmentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "Invalid request payload")
+		return
+	}
+
+	user, err := auth.GetUserFromContext(r.Context())
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "Failed to retrieve user")
+		return
+	}
+
+	entry, err := h.service.RecordAdjustment(id, req, user)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			response.Error(w, http.StatusNotFound, "Customer not found")
+			return
+		}
+		if validator.IsValidationError(err) {
+			response.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, "Failed to record adjustment")
+		return
+	}
+
+	response.Created(w, entry)
+}
@@ -1,34 +1,110 @@
 package customer
 
 import (
+	"time"
+
 	"github.com/google/uuid"
 	"github.com/shanmugharajk/go-react-web-api/api/internal/common"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/money"
 )
 
 // Customer represents a customer in the system.
+// Balance is what the customer owes: the sum of their ledger debits minus
+// credits. It only changes by posting ledger entries; a negative balance is
+// credit held by the customer.
 type Customer struct {
-	ID      uuid.UUID `gorm:"type:char(36);primarykey" json:"id"`
-	Name    string    `gorm:"not null" json:"name"`
-	Email   string    `gorm:"unique;not null" json:"email"`
-	Mobile  string    `gorm:"not null" json:"mobile"`
-	Balance float64   `gorm:"default:0" json:"balance"`
-	Active  bool      `gorm:"default:true" json:"active"`
+	ID      uuid.UUID    `gorm:"type:char(36);primarykey" json:"id"`
+	Name    string       `gorm:"not null" json:"name"`
+	Email   string       `gorm:"unique;not null" json:"email"`
+	Mobile  string       `gorm:"not null" json:"mobile"`
+	Balance money.Amount `gorm:"default:0" json:"balance"`
+	Active  bool         `gorm:"default:true" json:"active"`
+	// PriceListID is the price list the customer buys at, assigned through
+	// the price list. It has no foreign key constraint because adding one
+	// would make sqlite rebuild the existing customers table.
+	PriceListID *uuid.UUID `gorm:"type:char(36);index" json:"priceListId"`
 	common.AuditFields
 }
 
 // CreateCustomerRequest represents the request payload for creating a customer.
+// Balance is an opening balance, posted to the customer's ledger.
 type CreateCustomerRequest struct {
-	Name   string `json:"name" validate:"required,min=1,max=255"`
-	Email  string `json:"email" validate:"required,email"`
-	Mobile string `json:"mobile" validate:"required,min=1,max=20"`
-	Balance float64 `json:"balance" validate:"gte=0"`
+	Name    string       `json:"name" validate:"required,min=1,max=255"`
+	Email   string       `json:"email" validate:"required,email"`
+	Mobile  string       `json:"mobile" validate:"required,min=1,max=20"`
+	Balance money.Amount `json:"balance" validate:"gte=0"`
 }
 
 // UpdateCustomerRequest represents the request payload for updating a customer.
-// Only allows updating email, balance, and active status to maintain historical data integrity.
+// Only allows updating email and active status to maintain historical data integrity.
 // Name and mobile are immutable to avoid confusion with historical records and transactions.
+// Balance changes go through the customer ledger.
 type UpdateCustomerRequest struct {
-	Email   string  `json:"email" validate:"required,email"`
-	Balance float64 `json:"balance" validate:"gte=0"`
-	Active  bool    `json:"active"`
+	Email  string `json:"email" validate:"required,email"`
+	Active bool   `json:"active"`
+}
+
+// LedgerEntryType is the side of the customer's account an entry posts to.
+// Debits increase what the customer owes, credits decrease it.
+type LedgerEntryType string
+
+// Ledger entry types.
+const (
+	EntryDebit  LedgerEntryType = "debit"
+	EntryCredit LedgerEntryType = "credit"
+)
+
+// LedgerEntryKind is the business event behind a ledger entry.
+type LedgerEntryKind string
+
+// Ledger entry kinds.
+const (
+	KindOpeningBalance   LedgerEntryKind = "opening_balance"
+	KindCreditSale       LedgerEntryKind = "credit_sale"
+	KindCreditRedemption LedgerEntryKind = "credit_redemption"
+	KindPayment          LedgerEntryKind = "payment"
+	KindRefund           LedgerEntryKind = "refund"
+	KindAdjustment       LedgerEntryKind = "adjustment"
+)
+
+// LedgerEntry is an immutable posting to a customer's account.
+type LedgerEntry struct {
+	ID            uuid.UUID       `gorm:"type:char(36);primaryKey" json:"id"`
+	CustomerID    uuid.UUID       `gorm:"type:char(36);index;not null" json:"customerId"`
+	Type          LedgerEntryType `gorm:"not null" json:"type"`
+	Kind          LedgerEntryKind `gorm:"not null" json:"kind"`
+	Amount        money.Amount    `gorm:"not null" json:"amount"`
+	BalanceAfter  money.Amount    `gorm:"not null" json:"balanceAfter"`
+	Reason        string          `json:"reason"`
+	ReferenceType string          `json:"referenceType"`
+	ReferenceID   *uuid.UUID      `gorm:"type:char(36);index" json:"referenceId"`
+	CreatedAt     time.Time       `gorm:"index" json:"createdAt"`
+	CreatedBy     uuid.UUID       `gorm:"type:char(36)" json:"createdBy"`
+
+	Customer Customer `gorm:"foreignKey:CustomerID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:RESTRICT;" json:"-"`
+}
+
+// TableName specifies the table name for the LedgerEntry model.
+func (LedgerEntry) TableName() string {
+	return "customer_ledger_entries"
+}
+
+// Ledger is a customer's running-balance history.
+type Ledger struct {
+	CustomerID uuid.UUID     `json:"customerId"`
+	Balance    money.Amount  `json:"balance"`
+	Entries    []LedgerEntry `json:"entries"`
+}
+
+// RecordPaymentRequest represents a payment received on a customer's account.
+type RecordPaymentRequest struct {
+	Amount money.Amount `json:"amount" validate:"required,gt=0"`
+	Reason string       `json:"reason" validate:"max=1000"`
+}
+
+// RecordAdjustmentRequest represents a manual correction to a customer's account.
+type RecordAdjustmentRequest struct {
+	Type   LedgerEntryType `json:"type" validate:"required,oneof=debit credit"`
+	Amount money.Amount    `json:"amount" validate:"required,gt=0"`
+	Reason string          `json:"reason" validate:"required,min=1,max=1000"`
 }
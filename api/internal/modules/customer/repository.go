@@ -4,6 +4,8 @@ import (
 	"github.com/google/uuid"
 	"github.com/shanmugharajk/go-react-web-api/api/internal/db"
 	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/errors"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/money"
+	"gorm.io/gorm"
 )
 
 // CustomerRepository handles database operations for customers.
@@ -42,9 +44,48 @@ func (r *CustomerRepository) Create(customer *Customer) error {
 	return r.db.Create(customer).Error
 }
 
+// FindByPriceListID retrieves the customers assigned to a price list.
+func (r *CustomerRepository) FindByPriceListID(priceListID uuid.UUID) ([]Customer, error) {
+	var customers []Customer
+	if err := r.db.Where("price_list_id = ?", priceListID).Order("name ASC").Find(&customers).Error; err != nil {
+		return nil, err
+	}
+	return customers, nil
+}
+
 // Update updates an existing customer in the database.
+// The balance is never written here; it only changes through ledger postings.
+// Nor is the price list, which is assigned through the price list.
 func (r *CustomerRepository) Update(customer *Customer) error {
-	return r.db.Save(customer).Error
+	return r.db.Omit("balance", "price_list_id").Save(customer).Error
+}
+
+// AssignPriceList assigns a customer to a price list, replacing any list
+// the customer was on.
+func (r *CustomerRepository) AssignPriceList(id, priceListID, userID uuid.UUID) error {
+	result := r.db.Model(&Customer{}).Where("id = ?", id).
+		Updates(map[string]any{"price_list_id": priceListID, "updated_by": userID})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.ErrNotFound
+	}
+	return nil
+}
+
+// UnassignPriceList takes a customer off a price list. It fails with
+// ErrNotFound unless the customer is on that list.
+func (r *CustomerRepository) UnassignPriceList(id, priceListID, userID uuid.UUID) error {
+	result := r.db.Model(&Customer{}).Where("id = ? AND price_list_id = ?", id, priceListID).
+		Updates(map[string]any{"price_list_id": nil, "updated_by": userID})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.ErrNotFound
+	}
+	return nil
 }
 
 // Delete soft deletes a customer by setting active to false.
@@ -58,3 +99,109 @@ func (r *CustomerRepository) Delete(id uuid.UUID) error {
 	}
 	return nil
 }
+
+// LedgerRepository handles data access for customer ledger entries.
+// Entries are append-only, so there is no update or delete.
+type LedgerRepository struct {
+	db *db.DB
+}
+
+// NewLedgerRepository creates a new customer ledger repository.
+func NewLedgerRepository(database *db.DB) *LedgerRepository {
+	return &LedgerRepository{db: database}
+}
+
+// FindByCustomerID retrieves a customer's ledger entries in posting order.
+func (r *LedgerRepository) FindByCustomerID(customerID uuid.UUID) ([]LedgerEntry, error) {
+	var entries []LedgerEntry
+	if err := r.db.Where("customer_id = ?", customerID).Order("created_at ASC").Find(&entries).Error; err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// Post records a ledger entry and applies it to the customer's balance in a
+// single transaction. The entry's CustomerID, Type, Kind and Amount must be
+// set; ID and BalanceAfter are filled in.
+func (r *LedgerRepository) Post(entry *LedgerEntry) error {
+	delta := entry.Amount
+	if entry.Type == EntryCredit {
+		delta = -delta
+	}
+
+	return r.db.Transaction(func(tx *db.DB) error {
+		result := tx.Model(&Customer{}).Where("id = ?", entry.CustomerID).
+			Update("balance", gorm.Expr("balance + ?", delta))
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.ErrNotFound
+		}
+
+		var balance money.Amount
+		if err := tx.Model(&Customer{}).Where("id = ?", entry.CustomerID).
+			Select("balance").Scan(&balance).Error; err != nil {
+			return err
+		}
+
+		if entry.ID == uuid.Nil {
+			entry.ID = uuid.New()
+		}
+		entry.BalanceAfter = balance
+		return tx.Omit("Customer").Create(entry).Error
+	})
+}
+
+// MigrateMoneyToMinorUnits converts balances and ledger amounts stored as
+// decimals to exact minor units.
+var MigrateMoneyToMinorUnits = db.Migration{
+	ID: "20261016_customer_money_minor_units",
+	Up: func(tx *db.DB) error {
+		if err := money.MigrateColumns(tx.DB, "customers", "balance"); err != nil {
+			return err
+		}
+		return money.MigrateColumns(tx.DB, "customer_ledger_entries", "amount", "balance_after")
+	},
+}
+
+// MigrateBalancesToLedger records an opening balance entry for every customer
+// whose balance predates the ledger, so each balance equals its entries.
+var MigrateBalancesToLedger = db.Migration{
+	ID: "20261016_customer_balances_to_ledger",
+	Up: func(tx *db.DB) error {
+		var customers []Customer
+		if err := tx.Where("balance <> 0").Find(&customers).Error; err != nil {
+			return err
+		}
+
+		for _, c := range customers {
+			var count int64
+			if err := tx.Model(&LedgerEntry{}).Where("customer_id = ?", c.ID).Count(&count).Error; err != nil {
+				return err
+			}
+			if count > 0 {
+				continue
+			}
+
+			entry := &LedgerEntry{
+				ID:           uuid.New(),
+				CustomerID:   c.ID,
+				Type:         EntryDebit,
+				Kind:         KindOpeningBalance,
+				Amount:       c.Balance,
+				BalanceAfter: c.Balance,
+				Reason:       "balance carried over to ledger",
+				CreatedBy:    c.CreatedBy,
+			}
+			if c.Balance < 0 {
+				entry.Type = EntryCredit
+				entry.Amount = -c.Balance
+			}
+			if err := tx.Omit("Customer").Create(entry).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+}
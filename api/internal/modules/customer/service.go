@@ -4,17 +4,20 @@ import (
 	"github.com/google/uuid"
 	"github.com/shanmugharajk/go-react-web-api/api/internal/modules/auth"
 	"github.com/shanmugharajk/go-react-web-api/api/internal/common"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/db"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/logger"
 	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/validator"
 )
 
 // CustomerService handles business logic for customers.
 type CustomerService struct {
+	db   *db.DB
 	repo *CustomerRepository
 }
 
 // NewCustomerService creates a new CustomerService instance.
-func NewCustomerService(repo *CustomerRepository) *CustomerService {
-	return &CustomerService{repo: repo}
+func NewCustomerService(database *db.DB, repo *CustomerRepository) *CustomerService {
+	return &CustomerService{db: database, repo: repo}
 }
 
 // GetAll retrieves all customers.
@@ -35,18 +38,40 @@ func (s *CustomerService) Create(req CreateCustomerRequest, user *auth.User) (*C
 	}
 
 	customer := &Customer{
-		Name:    req.Name,
-		Email:   req.Email,
-		Mobile:  req.Mobile,
-		Balance: req.Balance,
-		Active:  true,
+		Name:   req.Name,
+		Email:  req.Email,
+		Mobile: req.Mobile,
+		Active: true,
 		AuditFields: common.AuditFields{
 			CreatedBy: user.ID,
 			UpdatedBy: user.ID,
 		},
 	}
 
-	if err := s.repo.Create(customer); err != nil {
+	// The opening balance is posted to the ledger like any other change.
+	err := s.db.Transaction(func(tx *db.DB) error {
+		if err := NewCustomerRepository(tx).Create(customer); err != nil {
+			return err
+		}
+		if req.Balance == 0 {
+			return nil
+		}
+
+		entry := &LedgerEntry{
+			CustomerID: customer.ID,
+			Type:       EntryDebit,
+			Kind:       KindOpeningBalance,
+			Amount:     req.Balance,
+			Reason:     "opening balance",
+			CreatedBy:  user.ID,
+		}
+		if err := NewLedgerRepository(tx).Post(entry); err != nil {
+			return err
+		}
+		customer.Balance = entry.BalanceAfter
+		return nil
+	})
+	if err != nil {
 		return nil, err
 	}
 
@@ -66,7 +91,6 @@ func (s *CustomerService) Update(id uuid.UUID, req UpdateCustomerRequest, user *
 	}
 
 	customer.Email = req.Email
-	customer.Balance = req.Balance
 	customer.Active = req.Active
 	customer.UpdatedBy = user.ID
 
@@ -81,3 +105,78 @@ func (s *CustomerService) Update(id uuid.UUID, req UpdateCustomerRequest, user *
 func (s *CustomerService) Delete(id uuid.UUID) error {
 	return s.repo.Delete(id)
 }
+
+// LedgerService handles business logic for the customer ledger.
+type LedgerService struct {
+	customers *CustomerRepository
+	repo      *LedgerRepository
+}
+
+// NewLedgerService creates a new customer ledger service.
+func NewLedgerService(customers *CustomerRepository, repo *LedgerRepository) *LedgerService {
+	return &LedgerService{customers: customers, repo: repo}
+}
+
+// GetLedger retrieves a customer's balance and running-balance history.
+func (s *LedgerService) GetLedger(customerID uuid.UUID) (*Ledger, error) {
+	customer, err := s.customers.FindByID(customerID)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := s.repo.FindByCustomerID(customerID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Ledger{
+		CustomerID: customer.ID,
+		Balance:    customer.Balance,
+		Entries:    entries,
+	}, nil
+}
+
+// RecordPayment credits a payment received on a customer's account.
+func (s *LedgerService) RecordPayment(customerID uuid.UUID, req RecordPaymentRequest, user *auth.User) (*LedgerEntry, error) {
+	if err := validator.Struct(req); err != nil {
+		return nil, err
+	}
+
+	entry := &LedgerEntry{
+		CustomerID: customerID,
+		Type:       EntryCredit,
+		Kind:       KindPayment,
+		Amount:     req.Amount,
+		Reason:     req.Reason,
+		CreatedBy:  user.ID,
+	}
+	if err := s.repo.Post(entry); err != nil {
+		return nil, err
+	}
+
+	logger.Info("customer payment recorded", "customer_id", customerID, "amount", req.Amount, "created_by", user.ID)
+	return entry, nil
+}
+
+// RecordAdjustment posts a manual correction to a customer's account.
+// Adjustments always carry a reason so the balance can be audited.
+func (s *LedgerService) RecordAdjustment(customerID uuid.UUID, req RecordAdjustmentRequest, user *auth.User) (*LedgerEntry, error) {
+	if err := validator.Struct(req); err != nil {
+		return nil, err
+	}
+
+	entry := &LedgerEntry{
+		CustomerID: customerID,
+		Type:       req.Type,
+		Kind:       KindAdjustment,
+		Amount:     req.Amount,
+		Reason:     req.Reason,
+		CreatedBy:  user.ID,
+	}
+	if err := s.repo.Post(entry); err != nil {
+		return nil, err
+	}
+
+	logger.Info("customer balance adjusted", "customer_id", customerID, "type", req.Type, "amount", req.Amount, "created_by", user.ID)
+	return entry, nil
+}
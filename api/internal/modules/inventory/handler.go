@@ -2,13 +2,19 @@ package inventory
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/config"
 	"github.com/shanmugharajk/go-react-web-api/api/internal/db"
 	"github.com/shanmugharajk/go-react-web-api/api/internal/modules/auth"
 	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/errors"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/quantity"
 	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/response"
 	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/validator"
 )
@@ -21,7 +27,7 @@ type BatchHandler struct {
 // NewBatchHandler creates a new batch handler.
 func NewBatchHandler(database *db.DB) *BatchHandler {
 	repo := NewBatchRepository(database)
-	service := NewBatchService(repo)
+	service := NewBatchService(database, repo)
 	return &BatchHandler{service: service}
 }
 
@@ -33,13 +39,28 @@ func (h *BatchHandler) Routes() chi.Router {
 	r.Get("/{id}", h.GetByID)
 	r.Put("/{id}", h.Update)
 	r.Delete("/{id}", h.Delete)
+	r.Get("/{id}/reconciliation", h.Reconcile)
 	r.Get("/product/{productId}", h.GetByProductID)
+	r.Get("/variant-grid/{productId}", h.VariantGrid)
+	r.Get("/kit-availability/{productId}", h.KitAvailability)
+	r.Get("/stock-by-category", h.StockByCategory)
 	return r
 }
 
-// GetAll handles retrieving all product batches.
+// GetAll handles retrieving all product batches, optionally only those held
+// at a location.
 func (h *BatchHandler) GetAll(w http.ResponseWriter, r *http.Request) {
-	batches, err := h.service.GetAll()
+	var locationID *uuid.UUID
+	if locationIDStr := r.URL.Query().Get("locationId"); locationIDStr != "" {
+		id, err := uuid.Parse(locationIDStr)
+		if err != nil {
+			response.Error(w, http.StatusBadRequest, "invalid location ID")
+			return
+		}
+		locationID = &id
+	}
+
+	batches, err := h.service.GetAll(locationID)
 	if err != nil {
 		response.Error(w, http.StatusInternalServerError, "failed to retrieve product batches")
 		return
@@ -66,7 +87,8 @@ func (h *BatchHandler) GetByID(w http.ResponseWriter, r *http.Request) {
 	response.Success(w, batch)
 }
 
-// GetByProductID handles retrieving all batches for a product.
+// GetByProductID handles retrieving all batches for a product, optionally
+// only those held at a location.
 func (h *BatchHandler) GetByProductID(w http.ResponseWriter, r *http.Request) {
 	productIDStr := chi.URLParam(r, "productId")
 	productID, err := uuid.Parse(productIDStr)
@@ -75,7 +97,17 @@ func (h *BatchHandler) GetByProductID(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	batches, err := h.service.GetByProductID(productID)
+	var locationID *uuid.UUID
+	if locationIDStr := r.URL.Query().Get("locationId"); locationIDStr != "" {
+		id, err := uuid.Parse(locationIDStr)
+		if err != nil {
+			response.Error(w, http.StatusBadRequest, "invalid location ID")
+			return
+		}
+		locationID = &id
+	}
+
+	batches, err := h.service.GetByProductID(productID, locationID)
 	if err != nil {
 		response.Error(w, http.StatusInternalServerError, "failed to retrieve product batches")
 		return
@@ -84,6 +116,135 @@ func (h *BatchHandler) GetByProductID(w http.ResponseWriter, r *http.Request) {
 	response.Success(w, batches)
 }
 
+// VariantGrid handles retrieving the stock of a product's variants,
+// optionally only that held at a location.
+func (h *BatchHandler) VariantGrid(w http.ResponseWriter, r *http.Request) {
+	productIDStr := chi.URLParam(r, "productId")
+	productID, err := uuid.Parse(productIDStr)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid product ID")
+		return
+	}
+
+	var locationID *uuid.UUID
+	if locationIDStr := r.URL.Query().Get("locationId"); locationIDStr != "" {
+		id, err := uuid.Parse(locationIDStr)
+		if err != nil {
+			response.Error(w, http.StatusBadRequest, "invalid location ID")
+			return
+		}
+		locationID = &id
+	}
+
+	grid, err := h.service.VariantGrid(productID, locationID)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			response.Error(w, http.StatusNotFound, "product not found")
+			return
+		}
+		if errors.IsUnprocessable(err) {
+			response.Error(w, http.StatusUnprocessableEntity, err.Error())
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, "failed to retrieve variant grid")
+		return
+	}
+
+	response.Success(w, grid)
+}
+
+// KitAvailability handles retrieving how many of a kit its components'
+// stock makes up, optionally only that held at a location.
+func (h *BatchHandler) KitAvailability(w http.ResponseWriter, r *http.Request) {
+	productIDStr := chi.URLParam(r, "productId")
+	productID, err := uuid.Parse(productIDStr)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid product ID")
+		return
+	}
+
+	var locationID *uuid.UUID
+	if locationIDStr := r.URL.Query().Get("locationId"); locationIDStr != "" {
+		id, err := uuid.Parse(locationIDStr)
+		if err != nil {
+			response.Error(w, http.StatusBadRequest, "invalid location ID")
+			return
+		}
+		locationID = &id
+	}
+
+	availability, err := h.service.KitAvailability(productID, locationID)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			response.Error(w, http.StatusNotFound, "product not found")
+			return
+		}
+		if errors.IsUnprocessable(err) {
+			response.Error(w, http.StatusUnprocessableEntity, err.Error())
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, "failed to retrieve kit availability")
+		return
+	}
+
+	response.Success(w, availability)
+}
+
+// StockByCategory handles retrieving the stock value of every category,
+// rolled up to the requested level of the category tree, optionally only
+// stock held at a location.
+func (h *BatchHandler) StockByCategory(w http.ResponseWriter, r *http.Request) {
+	level := 0
+	if levelStr := r.URL.Query().Get("level"); levelStr != "" {
+		n, err := strconv.Atoi(levelStr)
+		if err != nil || n < 0 {
+			response.Error(w, http.StatusBadRequest, "invalid level")
+			return
+		}
+		level = n
+	}
+
+	var locationID *uuid.UUID
+	if locationIDStr := r.URL.Query().Get("locationId"); locationIDStr != "" {
+		id, err := uuid.Parse(locationIDStr)
+		if err != nil {
+			response.Error(w, http.StatusBadRequest, "invalid location ID")
+			return
+		}
+		locationID = &id
+	}
+
+	stock, err := h.service.StockByCategory(level, locationID)
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to retrieve stock by category")
+		return
+	}
+
+	response.Success(w, stock)
+}
+
+// Reconcile handles comparing a batch's quantity with its movement ledger.
+func (h *BatchHandler) Reconcile(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid batch ID")
+		return
+	}
+
+	reconciliation, err := h.service.Reconcile(id)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			response.Error(w, http.StatusNotFound, "product batch not found")
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, "failed to reconcile product batch")
+		return
+	}
+
+	response.Success(w, reconciliation)
+}
+
 // Create handles creating a new product batch.
 func (h *BatchHandler) Create(w http.ResponseWriter, r *http.Request) {
 	var req CreateProductBatchRequest
@@ -104,6 +265,10 @@ func (h *BatchHandler) Create(w http.ResponseWriter, r *http.Request) {
 			response.Error(w, http.StatusBadRequest, err.Error())
 			return
 		}
+		if errors.IsUnprocessable(err) {
+			response.Error(w, http.StatusUnprocessableEntity, err.Error())
+			return
+		}
 		response.Error(w, http.StatusInternalServerError, "failed to create product batch")
 		return
 	}
@@ -169,9 +334,727 @@ func (h *BatchHandler) Delete(w http.ResponseWriter, r *http.Request) {
 			response.Error(w, http.StatusNotFound, "product batch not found")
 			return
 		}
+		if errors.IsConflict(err) {
+			response.Error(w, http.StatusConflict, err.Error())
+			return
+		}
 		response.Error(w, http.StatusInternalServerError, "failed to delete product batch")
 		return
 	}
 
 	response.NoContent(w)
 }
+
+// AllocationHandler handles HTTP requests for batch allocation.
+type AllocationHandler struct {
+	service *AllocationService
+}
+
+// NewAllocationHandler creates a new allocation handler.
+func NewAllocationHandler(database *db.DB) *AllocationHandler {
+	repo := NewBatchRepository(database)
+	service := NewAllocationService(repo)
+	return &AllocationHandler{service: service}
+}
+
+// Routes returns the allocation routes.
+func (h *AllocationHandler) Routes() chi.Router {
+	r := chi.NewRouter()
+	r.Get("/preview", h.Preview)
+	return r
+}
+
+// Preview handles previewing which batches would be picked for a product.
+func (h *AllocationHandler) Preview(w http.ResponseWriter, r *http.Request) {
+	productID, err := uuid.Parse(r.URL.Query().Get("productId"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid product ID")
+		return
+	}
+
+	requested, err := quantity.Parse(r.URL.Query().Get("quantity"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid quantity")
+		return
+	}
+
+	var locationID *uuid.UUID
+	if locationIDStr := r.URL.Query().Get("locationId"); locationIDStr != "" {
+		id, err := uuid.Parse(locationIDStr)
+		if err != nil {
+			response.Error(w, http.StatusBadRequest, "invalid location ID")
+			return
+		}
+		locationID = &id
+	}
+
+	preview, err := h.service.Preview(AllocationPreviewRequest{
+		ProductID:  productID,
+		LocationID: locationID,
+		Unit:       r.URL.Query().Get("unit"),
+		Quantity:   requested,
+	})
+	if err != nil {
+		if errors.IsNotFound(err) {
+			response.Error(w, http.StatusNotFound, "product not found")
+			return
+		}
+		if validator.IsValidationError(err) {
+			response.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if errors.IsConflict(err) {
+			response.Error(w, http.StatusConflict, err.Error())
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, "failed to preview allocation")
+		return
+	}
+
+	response.Success(w, preview)
+}
+
+// MovementHandler handles HTTP requests for stock movements.
+type MovementHandler struct {
+	service *MovementService
+}
+
+// NewMovementHandler creates a new stock movement handler.
+func NewMovementHandler(database *db.DB) *MovementHandler {
+	repo := NewMovementRepository(database)
+	service := NewMovementService(repo)
+	return &MovementHandler{service: service}
+}
+
+// Routes returns the stock movement routes.
+func (h *MovementHandler) Routes() chi.Router {
+	r := chi.NewRouter()
+	r.Get("/batch/{id}", h.GetByBatchID)
+	r.Get("/product/{productId}", h.GetByProductID)
+	return r
+}
+
+// GetByBatchID handles retrieving the movement history of a batch.
+func (h *MovementHandler) GetByBatchID(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid batch ID")
+		return
+	}
+
+	movements, err := h.service.GetByBatchID(id)
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to retrieve stock movements")
+		return
+	}
+
+	response.Success(w, movements)
+}
+
+// GetByProductID handles retrieving the movement history of a product.
+func (h *MovementHandler) GetByProductID(w http.ResponseWriter, r *http.Request) {
+	productIDStr := chi.URLParam(r, "productId")
+	productID, err := uuid.Parse(productIDStr)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid product ID")
+		return
+	}
+
+	movements, err := h.service.GetByProductID(productID)
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to retrieve stock movements")
+		return
+	}
+
+	response.Success(w, movements)
+}
+
+// AdjustmentHandler handles HTTP requests for stock adjustments.
+type AdjustmentHandler struct {
+	service *AdjustmentService
+}
+
+// NewAdjustmentHandler creates a new stock adjustment handler.
+func NewAdjustmentHandler(database *db.DB, inventoryCfg config.InventoryConfig) *AdjustmentHandler {
+	repo := NewAdjustmentRepository(database)
+	service := NewAdjustmentService(database, repo, inventoryCfg)
+	return &AdjustmentHandler{service: service}
+}
+
+// Routes returns the stock adjustment routes.
+func (h *AdjustmentHandler) Routes() chi.Router {
+	r := chi.NewRouter()
+	r.Get("/", h.GetAll)
+	r.Post("/", h.Create)
+	r.Get("/{id}", h.GetByID)
+	r.Post("/{id}/approve", h.Approve)
+	r.Post("/{id}/reject", h.Reject)
+	r.Post("/{id}/reverse", h.Reverse)
+	return r
+}
+
+// GetAll handles retrieving stock adjustments, optionally filtered by the
+// status query parameter.
+func (h *AdjustmentHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	status := AdjustmentStatus(r.URL.Query().Get("status"))
+	switch status {
+	case "", AdjustmentPending, AdjustmentApplied, AdjustmentRejected, AdjustmentReversed:
+	default:
+		response.Error(w, http.StatusBadRequest, "invalid adjustment status")
+		return
+	}
+
+	adjustments, err := h.service.GetAll(status)
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to retrieve stock adjustments")
+		return
+	}
+
+	response.Success(w, adjustments)
+}
+
+// GetByID handles retrieving a stock adjustment by ID.
+func (h *AdjustmentHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid adjustment ID")
+		return
+	}
+
+	adjustment, err := h.service.GetByID(id)
+	if err != nil {
+		response.Error(w, http.StatusNotFound, "stock adjustment not found")
+		return
+	}
+
+	response.Success(w, adjustment)
+}
+
+// Create handles recording a stock adjustment.
+func (h *AdjustmentHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var req CreateAdjustmentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	user, err := auth.GetUserFromContext(r.Context())
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to retrieve user")
+		return
+	}
+
+	adjustment, err := h.service.Create(req, user)
+	if err != nil {
+		if validator.IsValidationError(err) {
+			response.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if errors.IsConflict(err) {
+			response.Error(w, http.StatusConflict, err.Error())
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, "failed to create stock adjustment")
+		return
+	}
+
+	response.Created(w, adjustment)
+}
+
+// Approve handles approving a pending stock adjustment.
+func (h *AdjustmentHandler) Approve(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid adjustment ID")
+		return
+	}
+
+	user, err := auth.GetUserFromContext(r.Context())
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to retrieve user")
+		return
+	}
+
+	adjustment, err := h.service.Approve(id, user)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			response.Error(w, http.StatusNotFound, "stock adjustment not found")
+			return
+		}
+		if errors.IsForbidden(err) {
+			response.Error(w, http.StatusForbidden, err.Error())
+			return
+		}
+		if errors.IsConflict(err) {
+			response.Error(w, http.StatusConflict, err.Error())
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, "failed to approve stock adjustment")
+		return
+	}
+
+	response.Success(w, adjustment)
+}
+
+// Reject handles rejecting a pending stock adjustment.
+func (h *AdjustmentHandler) Reject(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid adjustment ID")
+		return
+	}
+
+	user, err := auth.GetUserFromContext(r.Context())
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to retrieve user")
+		return
+	}
+
+	adjustment, err := h.service.Reject(id, user)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			response.Error(w, http.StatusNotFound, "stock adjustment not found")
+			return
+		}
+		if errors.IsConflict(err) {
+			response.Error(w, http.StatusConflict, err.Error())
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, "failed to reject stock adjustment")
+		return
+	}
+
+	response.Success(w, adjustment)
+}
+
+// Reverse handles reversing an applied stock adjustment.
+func (h *AdjustmentHandler) Reverse(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid adjustment ID")
+		return
+	}
+
+	user, err := auth.GetUserFromContext(r.Context())
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to retrieve user")
+		return
+	}
+
+	adjustment, err := h.service.Reverse(id, user)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			response.Error(w, http.StatusNotFound, "stock adjustment not found")
+			return
+		}
+		if errors.IsConflict(err) {
+			response.Error(w, http.StatusConflict, err.Error())
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, "failed to reverse stock adjustment")
+		return
+	}
+
+	response.Success(w, adjustment)
+}
+
+// ReorderRuleHandler handles HTTP requests for reorder rules and the
+// low-stock report.
+type ReorderRuleHandler struct {
+	service *ReorderRuleService
+}
+
+// NewReorderRuleHandler creates a new reorder rule handler.
+func NewReorderRuleHandler(database *db.DB) *ReorderRuleHandler {
+	repo := NewReorderRuleRepository(database)
+	service := NewReorderRuleService(database, repo)
+	return &ReorderRuleHandler{service: service}
+}
+
+// Routes returns the reorder rule routes.
+func (h *ReorderRuleHandler) Routes() chi.Router {
+	r := chi.NewRouter()
+	r.Get("/", h.GetAll)
+	r.Post("/", h.Create)
+	r.Get("/{id}", h.GetByID)
+	r.Put("/{id}", h.Update)
+	r.Delete("/{id}", h.Delete)
+	return r
+}
+
+// GetAll handles retrieving all reorder rules, optionally only those for a
+// location.
+func (h *ReorderRuleHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	var locationID *uuid.UUID
+	if locationIDStr := r.URL.Query().Get("locationId"); locationIDStr != "" {
+		id, err := uuid.Parse(locationIDStr)
+		if err != nil {
+			response.Error(w, http.StatusBadRequest, "invalid location ID")
+			return
+		}
+		locationID = &id
+	}
+
+	rules, err := h.service.GetAll(locationID)
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to retrieve reorder rules")
+		return
+	}
+
+	response.Success(w, rules)
+}
+
+// GetByID handles retrieving a reorder rule by ID.
+func (h *ReorderRuleHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid reorder rule ID")
+		return
+	}
+
+	rule, err := h.service.GetByID(id)
+	if err != nil {
+		response.Error(w, http.StatusNotFound, "reorder rule not found")
+		return
+	}
+
+	response.Success(w, rule)
+}
+
+// LowStock handles retrieving the products below their reorder rule's
+// minimum, optionally only those under rules for a location.
+func (h *ReorderRuleHandler) LowStock(w http.ResponseWriter, r *http.Request) {
+	var locationID *uuid.UUID
+	if locationIDStr := r.URL.Query().Get("locationId"); locationIDStr != "" {
+		id, err := uuid.Parse(locationIDStr)
+		if err != nil {
+			response.Error(w, http.StatusBadRequest, "invalid location ID")
+			return
+		}
+		locationID = &id
+	}
+
+	items, err := h.service.GetLowStock(locationID)
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to retrieve low stock")
+		return
+	}
+
+	response.Success(w, items)
+}
+
+// Create handles creating a reorder rule.
+func (h *ReorderRuleHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var req CreateReorderRuleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	user, err := auth.GetUserFromContext(r.Context())
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to retrieve user")
+		return
+	}
+
+	rule, err := h.service.Create(req, user)
+	if err != nil {
+		if validator.IsValidationError(err) {
+			response.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if errors.IsConflict(err) {
+			response.Error(w, http.StatusConflict, err.Error())
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, "failed to create reorder rule")
+		return
+	}
+
+	response.Created(w, rule)
+}
+
+// Update handles updating a reorder rule.
+func (h *ReorderRuleHandler) Update(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid reorder rule ID")
+		return
+	}
+
+	var req UpdateReorderRuleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	user, err := auth.GetUserFromContext(r.Context())
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to retrieve user")
+		return
+	}
+
+	rule, err := h.service.Update(id, req, user)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			response.Error(w, http.StatusNotFound, "reorder rule not found")
+			return
+		}
+		if validator.IsValidationError(err) {
+			response.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, "failed to update reorder rule")
+		return
+	}
+
+	response.Success(w, rule)
+}
+
+// Delete handles deleting a reorder rule.
+func (h *ReorderRuleHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid reorder rule ID")
+		return
+	}
+
+	if err := h.service.Delete(id); err != nil {
+		if errors.IsNotFound(err) {
+			response.Error(w, http.StatusNotFound, "reorder rule not found")
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, "failed to delete reorder rule")
+		return
+	}
+
+	response.NoContent(w)
+}
+
+// AlertHandler handles HTTP requests for stock alerts.
+type AlertHandler struct {
+	service *AlertService
+}
+
+// NewAlertHandler creates a new stock alert handler.
+func NewAlertHandler(database *db.DB) *AlertHandler {
+	repo := NewAlertRepository(database)
+	service := NewAlertService(repo)
+	return &AlertHandler{service: service}
+}
+
+// Routes returns the stock alert routes.
+func (h *AlertHandler) Routes() chi.Router {
+	r := chi.NewRouter()
+	r.Get("/", h.GetAll)
+	r.Get("/{id}", h.GetByID)
+	return r
+}
+
+// GetAll handles retrieving stock alerts, optionally filtered by the status
+// query parameter.
+func (h *AlertHandler) GetAll(w http.ResponseWriter, r *http.Request) {
+	status := AlertStatus(r.URL.Query().Get("status"))
+	switch status {
+	case "", AlertOpen, AlertResolved:
+	default:
+		response.Error(w, http.StatusBadRequest, "invalid alert status")
+		return
+	}
+
+	alerts, err := h.service.GetAll(status)
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to retrieve stock alerts")
+		return
+	}
+
+	response.Success(w, alerts)
+}
+
+// GetByID handles retrieving a stock alert by ID.
+func (h *AlertHandler) GetByID(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid alert ID")
+		return
+	}
+
+	alert, err := h.service.GetByID(id)
+	if err != nil {
+		response.Error(w, http.StatusNotFound, "stock alert not found")
+		return
+	}
+
+	response.Success(w, alert)
+}
+
+// ExpiryHandler handles HTTP requests for expiring and expired stock.
+type ExpiryHandler struct {
+	service *ExpiryService
+}
+
+// NewExpiryHandler creates a new expiry handler.
+func NewExpiryHandler(database *db.DB, inventoryCfg config.InventoryConfig) *ExpiryHandler {
+	repo := NewBatchRepository(database)
+	service := NewExpiryService(database, repo, inventoryCfg)
+	return &ExpiryHandler{service: service}
+}
+
+// Routes returns the expiry routes.
+func (h *ExpiryHandler) Routes() chi.Router {
+	r := chi.NewRouter()
+	r.Get("/expiring", h.GetExpiring)
+	r.Get("/expired", h.GetExpired)
+	r.Post("/expired/quarantine", h.Quarantine)
+	r.Post("/expired/write-off", h.WriteOff)
+	return r
+}
+
+// GetExpiring handles retrieving the batches that expire within the
+// duration given by the within query parameter, 30 days by default. Batches
+// can be filtered by the locationId query parameter.
+func (h *ExpiryHandler) GetExpiring(w http.ResponseWriter, r *http.Request) {
+	within := 30 * 24 * time.Hour
+	if withinStr := r.URL.Query().Get("within"); withinStr != "" {
+		d, err := parseWithin(withinStr)
+		if err != nil {
+			response.Error(w, http.StatusBadRequest, "invalid within duration")
+			return
+		}
+		within = d
+	}
+
+	var locationID *uuid.UUID
+	if locationIDStr := r.URL.Query().Get("locationId"); locationIDStr != "" {
+		id, err := uuid.Parse(locationIDStr)
+		if err != nil {
+			response.Error(w, http.StatusBadRequest, "invalid location ID")
+			return
+		}
+		locationID = &id
+	}
+
+	batches, err := h.service.GetExpiring(within, locationID)
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to retrieve expiring batches")
+		return
+	}
+
+	response.Success(w, batches)
+}
+
+// GetExpired handles retrieving the expired batches that still hold stock,
+// optionally only those held at a location.
+func (h *ExpiryHandler) GetExpired(w http.ResponseWriter, r *http.Request) {
+	var locationID *uuid.UUID
+	if locationIDStr := r.URL.Query().Get("locationId"); locationIDStr != "" {
+		id, err := uuid.Parse(locationIDStr)
+		if err != nil {
+			response.Error(w, http.StatusBadRequest, "invalid location ID")
+			return
+		}
+		locationID = &id
+	}
+
+	batches, err := h.service.GetExpired(locationID)
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to retrieve expired batches")
+		return
+	}
+
+	response.Success(w, batches)
+}
+
+// Quarantine handles moving the available stock of expired batches into
+// quarantine.
+func (h *ExpiryHandler) Quarantine(w http.ResponseWriter, r *http.Request) {
+	var req ExpiredStockRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	user, err := auth.GetUserFromContext(r.Context())
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to retrieve user")
+		return
+	}
+
+	batches, err := h.service.Quarantine(req, user)
+	if err != nil {
+		if validator.IsValidationError(err) {
+			response.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if errors.IsConflict(err) {
+			response.Error(w, http.StatusConflict, err.Error())
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, "failed to quarantine expired stock")
+		return
+	}
+
+	response.Success(w, batches)
+}
+
+// WriteOff handles writing off the quarantined stock of expired batches.
+func (h *ExpiryHandler) WriteOff(w http.ResponseWriter, r *http.Request) {
+	var req ExpiredStockRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	user, err := auth.GetUserFromContext(r.Context())
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to retrieve user")
+		return
+	}
+
+	adjustment, err := h.service.WriteOff(req, user)
+	if err != nil {
+		if validator.IsValidationError(err) {
+			response.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if errors.IsConflict(err) {
+			response.Error(w, http.StatusConflict, err.Error())
+			return
+		}
+		if errors.IsUnprocessable(err) {
+			response.Error(w, http.StatusUnprocessableEntity, err.Error())
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, "failed to write off expired stock")
+		return
+	}
+
+	response.Created(w, adjustment)
+}
+
+// parseWithin parses a report window given as whole days, such as "30d", or
+// as a duration, such as "36h". The window must be positive.
+func parseWithin(s string) (time.Duration, error) {
+	d, err := time.ParseDuration(s)
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		var n int
+		n, err = strconv.Atoi(days)
+		d = time.Duration(n) * 24 * time.Hour
+	}
+	if err != nil {
+		return 0, err
+	}
+	if d <= 0 {
+		return 0, fmt.Errorf("within must be positive")
+	}
+	return d, nil
+}
@@ -5,36 +5,356 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/shanmugharajk/go-react-web-api/api/internal/common"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/modules/product"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/money"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/quantity"
 )
 
-// ProductBatch represents a batch of products in inventory.
+// ProductBatch represents a batch of products held at a location. Its
+// purchase and expiry times are stored in UTC, as sqlite compares them as
+// text and only times in the same zone compare in time order.
 type ProductBatch struct {
-	ID                uuid.UUID  `gorm:"type:char(36);primaryKey" json:"id"`
-	ProductID         uuid.UUID  `gorm:"type:char(36);index;not null" json:"productId"`
-	CostPrice         float64    `gorm:"not null" json:"costPrice"`
-	SellingPrice      float64    `gorm:"not null" json:"sellingPrice"`
-	QuantityAvailable int        `gorm:"not null;default:0" json:"quantityAvailable"`
-	PurchasedAt       time.Time  `gorm:"not null" json:"purchasedAt"`
-	ExpiresAt         *time.Time `json:"expiresAt"`
+	ID                  uuid.UUID         `gorm:"type:char(36);primaryKey" json:"id"`
+	ProductID           uuid.UUID         `gorm:"type:char(36);index;not null" json:"productId"`
+	LocationID          uuid.UUID         `gorm:"type:char(36);index" json:"locationId"`
+	CostPrice           money.Amount      `gorm:"not null" json:"costPrice"`
+	SellingPrice        money.Amount      `gorm:"not null" json:"sellingPrice"`
+	QuantityAvailable   quantity.Quantity `gorm:"not null;default:0" json:"quantityAvailable"`
+	QuantityQuarantined quantity.Quantity `gorm:"not null;default:0" json:"quantityQuarantined"`
+	PurchasedAt         time.Time         `gorm:"not null" json:"purchasedAt"`
+	ExpiresAt           *time.Time        `json:"expiresAt"`
 
 	common.AuditFields
 }
 
+// IsExpired checks if the batch has expired at t. Batches without an expiry
+// date never expire.
+func (b *ProductBatch) IsExpired(at time.Time) bool {
+	return b.ExpiresAt != nil && !b.ExpiresAt.After(at)
+}
+
+// MovementType classifies a stock movement.
+type MovementType string
+
+// Stock movement types.
+const (
+	MovementReceipt    MovementType = "receipt"
+	MovementSale       MovementType = "sale"
+	MovementReturn     MovementType = "return"
+	MovementAdjustment MovementType = "adjustment"
+	MovementTransfer   MovementType = "transfer"
+	MovementQuarantine MovementType = "quarantine"
+)
+
+// StockBucket identifies which quantity of a batch a movement changes.
+// Quarantined stock, such as damaged returns, is held back from sale.
+type StockBucket string
+
+// Stock buckets.
+const (
+	BucketAvailable  StockBucket = "available"
+	BucketQuarantine StockBucket = "quarantine"
+)
+
+// StockMovement is an immutable record of a change to a batch's quantity.
+// Quantity is the signed change to the movement's bucket; the sum of a
+// batch's movements per bucket equals its QuantityAvailable and
+// QuantityQuarantined respectively.
+type StockMovement struct {
+	ID            uuid.UUID         `gorm:"type:char(36);primaryKey" json:"id"`
+	BatchID       uuid.UUID         `gorm:"type:char(36);index;not null" json:"batchId"`
+	ProductID     uuid.UUID         `gorm:"type:char(36);index;not null" json:"productId"`
+	Type          MovementType      `gorm:"not null" json:"type"`
+	Bucket        StockBucket       `gorm:"not null;default:available" json:"bucket"`
+	Quantity      quantity.Quantity `gorm:"not null" json:"quantity"`
+	BalanceAfter  quantity.Quantity `gorm:"not null" json:"balanceAfter"`
+	ReferenceType string            `json:"referenceType"`
+	ReferenceID   *uuid.UUID        `gorm:"type:char(36);index" json:"referenceId"`
+	Note          string            `json:"note"`
+	CreatedAt     time.Time         `gorm:"index" json:"createdAt"`
+	CreatedBy     uuid.UUID         `gorm:"type:char(36)" json:"createdBy"`
+}
+
+// BatchReconciliation compares a batch's stored quantity with its ledger.
+type BatchReconciliation struct {
+	BatchID           uuid.UUID         `json:"batchId"`
+	QuantityAvailable quantity.Quantity `json:"quantityAvailable"`
+	LedgerQuantity    quantity.Quantity `json:"ledgerQuantity"`
+	InSync            bool              `json:"inSync"`
+}
+
 // CreateProductBatchRequest represents a request to create a product batch.
+// Without a location the batch is held at the user's default location. A
+// batch may start empty; only an empty batch that never held stock can be
+// deleted.
 type CreateProductBatchRequest struct {
-	ProductID         uuid.UUID  `json:"productId" validate:"required"`
-	CostPrice         float64    `json:"costPrice" validate:"required,gte=0"`
-	SellingPrice      float64    `json:"sellingPrice" validate:"required,gte=0"`
-	QuantityAvailable int        `json:"quantityAvailable" validate:"required,gte=0"`
-	PurchasedAt       time.Time  `json:"purchasedAt" validate:"required"`
-	ExpiresAt         *time.Time `json:"expiresAt"`
+	ProductID         uuid.UUID         `json:"productId" validate:"required"`
+	LocationID        *uuid.UUID        `json:"locationId"`
+	CostPrice         money.Amount      `json:"costPrice" validate:"required,gte=0"`
+	SellingPrice      money.Amount      `json:"sellingPrice" validate:"required,gte=0"`
+	QuantityAvailable quantity.Quantity `json:"quantityAvailable" validate:"gte=0"`
+	PurchasedAt       time.Time         `json:"purchasedAt" validate:"required"`
+	ExpiresAt         *time.Time        `json:"expiresAt"`
 }
 
 // UpdateProductBatchRequest represents a request to update a product batch.
+// Quantities are changed through stock adjustments.
 type UpdateProductBatchRequest struct {
-	CostPrice         float64    `json:"costPrice" validate:"required,gte=0"`
-	SellingPrice      float64    `json:"sellingPrice" validate:"required,gte=0"`
-	QuantityAvailable int        `json:"quantityAvailable" validate:"required,gte=0"`
-	PurchasedAt       time.Time  `json:"purchasedAt" validate:"required"`
-	ExpiresAt         *time.Time `json:"expiresAt"`
+	CostPrice    money.Amount `json:"costPrice" validate:"required,gte=0"`
+	SellingPrice money.Amount `json:"sellingPrice" validate:"required,gte=0"`
+	PurchasedAt  time.Time    `json:"purchasedAt" validate:"required"`
+	ExpiresAt    *time.Time   `json:"expiresAt"`
+}
+
+// Allocation represents a quantity picked from a single batch.
+type Allocation struct {
+	BatchID      uuid.UUID         `json:"batchId"`
+	ProductID    uuid.UUID         `json:"productId"`
+	Quantity     quantity.Quantity `json:"quantity"`
+	CostPrice    money.Amount      `json:"costPrice"`
+	SellingPrice money.Amount      `json:"sellingPrice"`
+	PurchasedAt  time.Time         `json:"purchasedAt"`
+	ExpiresAt    *time.Time        `json:"expiresAt"`
+}
+
+// AllocationPreview represents a planned allocation that has not been
+// applied. Its quantities are in the product's base unit.
+type AllocationPreview struct {
+	ProductID   uuid.UUID         `json:"productId"`
+	Quantity    quantity.Quantity `json:"quantity"`
+	Allocations []Allocation      `json:"allocations"`
+	Total       money.Amount      `json:"total"`
+}
+
+// AllocationPreviewRequest represents a request to preview an allocation
+// of a quantity in Unit, which defaults to the product's sales unit.
+// Without a location, batches at every location are picked from.
+type AllocationPreviewRequest struct {
+	ProductID  uuid.UUID `validate:"required"`
+	LocationID *uuid.UUID
+	Unit       string            `validate:"max=20"`
+	Quantity   quantity.Quantity `validate:"required,gt=0"`
+}
+
+// AdjustmentReason is why stock was adjusted.
+type AdjustmentReason string
+
+// Stock adjustment reasons.
+const (
+	ReasonDamaged  AdjustmentReason = "damaged"
+	ReasonExpired  AdjustmentReason = "expired"
+	ReasonStolen   AdjustmentReason = "stolen"
+	ReasonMiscount AdjustmentReason = "miscount"
+	ReasonFound    AdjustmentReason = "found"
+	ReasonOther    AdjustmentReason = "other"
+)
+
+// AdjustmentStatus is where a stock adjustment is in its lifecycle.
+type AdjustmentStatus string
+
+// Stock adjustment statuses.
+const (
+	AdjustmentPending  AdjustmentStatus = "pending"
+	AdjustmentApplied  AdjustmentStatus = "applied"
+	AdjustmentRejected AdjustmentStatus = "rejected"
+	AdjustmentReversed AdjustmentStatus = "reversed"
+)
+
+// StockAdjustment is a document correcting the available or quarantined
+// quantity of one or more batches, for a reason.
+//
+// Value is the adjustment's total worth at cost, counting removals and
+// additions alike. An adjustment worth no more than the approval threshold
+// is applied when it is created; a larger one waits as pending until a user
+// other than its creator approves or rejects it. An applied adjustment can
+// be reversed, which records the opposite movements.
+type StockAdjustment struct {
+	ID         uuid.UUID        `gorm:"type:char(36);primaryKey" json:"id"`
+	Reason     AdjustmentReason `gorm:"not null;index" json:"reason"`
+	Status     AdjustmentStatus `gorm:"not null;index" json:"status"`
+	Note       string           `json:"note"`
+	Value      money.Amount     `gorm:"not null;default:0" json:"value"`
+	ApprovedBy *uuid.UUID       `gorm:"type:char(36)" json:"approvedBy"`
+	ApprovedAt *time.Time       `json:"approvedAt"`
+	RejectedBy *uuid.UUID       `gorm:"type:char(36)" json:"rejectedBy"`
+	RejectedAt *time.Time       `json:"rejectedAt"`
+	ReversedBy *uuid.UUID       `gorm:"type:char(36)" json:"reversedBy"`
+	ReversedAt *time.Time       `json:"reversedAt"`
+	Lines      []AdjustmentLine `gorm:"foreignKey:AdjustmentID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"lines"`
+
+	common.AuditFields
+}
+
+// AdjustmentLine is the signed change an adjustment makes to one bucket of
+// a batch.
+type AdjustmentLine struct {
+	ID           uuid.UUID         `gorm:"type:char(36);primaryKey" json:"id"`
+	AdjustmentID uuid.UUID         `gorm:"type:char(36);index;not null" json:"adjustmentId"`
+	BatchID      uuid.UUID         `gorm:"type:char(36);index;not null" json:"batchId"`
+	ProductID    uuid.UUID         `gorm:"type:char(36);index;not null" json:"productId"`
+	Bucket       StockBucket       `gorm:"not null;default:available" json:"bucket"`
+	Quantity     quantity.Quantity `gorm:"not null" json:"quantity"`
+	CostPrice    money.Amount      `gorm:"not null" json:"costPrice"`
+}
+
+// TableName specifies the table name for the AdjustmentLine model.
+func (AdjustmentLine) TableName() string {
+	return "stock_adjustment_lines"
+}
+
+// CreateAdjustmentRequest represents a request to adjust stock.
+type CreateAdjustmentRequest struct {
+	Reason AdjustmentReason        `json:"reason" validate:"required,oneof=damaged expired stolen miscount found other"`
+	Note   string                  `json:"note" validate:"max=1000"`
+	Lines  []AdjustmentLineRequest `json:"lines" validate:"required,min=1,dive"`
+}
+
+// AdjustmentLineRequest represents the change to one batch in a stock
+// adjustment. Negative quantities remove stock. Without a bucket the
+// available quantity is adjusted.
+type AdjustmentLineRequest struct {
+	BatchID  uuid.UUID         `json:"batchId" validate:"required"`
+	Bucket   StockBucket       `json:"bucket" validate:"omitempty,oneof=available quarantine"`
+	Quantity quantity.Quantity `json:"quantity" validate:"required,ne=0"`
+}
+
+// ReorderRule sets the stock level a product is reordered at, in the
+// product's base unit. A rule with a location applies to the stock held
+// there; one without applies to the stock held across every location. A
+// product is low on stock when its available quantity in unexpired batches
+// falls below MinimumQuantity, and ReorderQuantity is how much to order then.
+type ReorderRule struct {
+	ID              uuid.UUID         `gorm:"type:char(36);primaryKey" json:"id"`
+	ProductID       uuid.UUID         `gorm:"type:char(36);index;not null" json:"productId"`
+	LocationID      *uuid.UUID        `gorm:"type:char(36);index" json:"locationId"`
+	MinimumQuantity quantity.Quantity `gorm:"not null" json:"minimumQuantity"`
+	ReorderQuantity quantity.Quantity `gorm:"not null" json:"reorderQuantity"`
+
+	common.AuditFields
+}
+
+// CreateReorderRuleRequest represents a request to create a reorder rule.
+type CreateReorderRuleRequest struct {
+	ProductID       uuid.UUID         `json:"productId" validate:"required"`
+	LocationID      *uuid.UUID        `json:"locationId"`
+	MinimumQuantity quantity.Quantity `json:"minimumQuantity" validate:"gte=0"`
+	ReorderQuantity quantity.Quantity `json:"reorderQuantity" validate:"required,gt=0"`
+}
+
+// UpdateReorderRuleRequest represents a request to update a reorder rule.
+// The product and location a rule applies to are fixed.
+type UpdateReorderRuleRequest struct {
+	MinimumQuantity quantity.Quantity `json:"minimumQuantity" validate:"gte=0"`
+	ReorderQuantity quantity.Quantity `json:"reorderQuantity" validate:"required,gt=0"`
+}
+
+// LowStockItem is a reorder rule whose product is below its minimum.
+// Shortfall is how far below. Quantities are in Unit, the product's base
+// unit.
+type LowStockItem struct {
+	RuleID            uuid.UUID         `json:"ruleId"`
+	ProductID         uuid.UUID         `json:"productId"`
+	ProductName       string            `json:"productName"`
+	Unit              string            `json:"unit"`
+	LocationID        *uuid.UUID        `json:"locationId"`
+	MinimumQuantity   quantity.Quantity `json:"minimumQuantity"`
+	ReorderQuantity   quantity.Quantity `json:"reorderQuantity"`
+	QuantityAvailable quantity.Quantity `json:"quantityAvailable"`
+	Shortfall         quantity.Quantity `json:"shortfall"`
+}
+
+// VariantGrid is the stock of a product's variants, with a cell for every
+// combination of the values of its category's attributes, the first
+// attribute varying slowest. Quantities are the stock available that has
+// not expired, in Unit, the product's base unit.
+type VariantGrid struct {
+	ProductID         uuid.UUID           `json:"productId"`
+	LocationID        *uuid.UUID          `json:"locationId"`
+	Unit              string              `json:"unit"`
+	Attributes        []product.Attribute `json:"attributes"`
+	Cells             []VariantGridCell   `json:"cells"`
+	QuantityAvailable quantity.Quantity   `json:"quantityAvailable"`
+}
+
+// KitAvailability is how many of a kit can be made up from the stock of its
+// components that has not expired: the fewest any one component is enough
+// for, in whole kits unless the kit is fractional.
+type KitAvailability struct {
+	ProductID         uuid.UUID               `json:"productId"`
+	LocationID        *uuid.UUID              `json:"locationId"`
+	Components        []ComponentAvailability `json:"components"`
+	QuantityAvailable quantity.Quantity       `json:"quantityAvailable"`
+}
+
+// ComponentAvailability is the stock of one component of a kit, in its base
+// unit, and how many kits it is enough for. Quantity is what one kit takes.
+type ComponentAvailability struct {
+	ProductID         uuid.UUID         `json:"productId"`
+	Unit              string            `json:"unit"`
+	Quantity          quantity.Quantity `json:"quantity"`
+	QuantityAvailable quantity.Quantity `json:"quantityAvailable"`
+	Kits              quantity.Quantity `json:"kits"`
+}
+
+// CategoryStock is the available stock of the products of a category and
+// of the categories rolled up into it, valued at cost and at selling price.
+// Level is the category's depth in the tree, 0 at the top.
+type CategoryStock struct {
+	CategoryID  uuid.UUID    `json:"categoryId"`
+	Name        string       `json:"name"`
+	Level       int          `json:"level"`
+	Products    int          `json:"products"`
+	CostValue   money.Amount `json:"costValue"`
+	RetailValue money.Amount `json:"retailValue"`
+}
+
+// VariantGridCell is a combination of attribute values, keyed by attribute
+// name, and the stock of the variant that has them. VariantID is nil when
+// the product has no such variant.
+type VariantGridCell struct {
+	Values            map[string]string `json:"values"`
+	VariantID         *uuid.UUID        `json:"variantId"`
+	SKU               *string           `json:"sku"`
+	QuantityAvailable quantity.Quantity `json:"quantityAvailable"`
+}
+
+// AlertStatus is whether a stock alert still needs attention.
+type AlertStatus string
+
+// Stock alert statuses.
+const (
+	AlertOpen     AlertStatus = "open"
+	AlertResolved AlertStatus = "resolved"
+)
+
+// StockAlert records a product falling below its reorder rule's minimum.
+// It is raised once when the product crosses below the minimum and
+// resolved once the product is back at or above it, or the rule is removed.
+type StockAlert struct {
+	ID                uuid.UUID         `gorm:"type:char(36);primaryKey" json:"id"`
+	RuleID            uuid.UUID         `gorm:"type:char(36);index;not null" json:"ruleId"`
+	ProductID         uuid.UUID         `gorm:"type:char(36);index;not null" json:"productId"`
+	LocationID        *uuid.UUID        `gorm:"type:char(36);index" json:"locationId"`
+	Status            AlertStatus       `gorm:"not null;index" json:"status"`
+	MinimumQuantity   quantity.Quantity `gorm:"not null" json:"minimumQuantity"`
+	QuantityAvailable quantity.Quantity `gorm:"not null" json:"quantityAvailable"`
+	RaisedAt          time.Time         `gorm:"not null" json:"raisedAt"`
+	ResolvedAt        *time.Time        `json:"resolvedAt"`
+}
+
+// ExpiringBatch is a batch in an expiry report. MarkdownPrice is its
+// selling price after the expiry markdown, and equals SellingPrice while the
+// batch is outside the markdown window or markdowns are disabled.
+type ExpiringBatch struct {
+	ProductBatch
+
+	ProductName   string       `json:"productName"`
+	MarkdownPrice money.Amount `json:"markdownPrice"`
+}
+
+// ExpiredStockRequest represents a request to quarantine or write off the
+// stock of expired batches, at one location or at every location.
+type ExpiredStockRequest struct {
+	LocationID *uuid.UUID `json:"locationId"`
+	Note       string     `json:"note" validate:"max=1000"`
 }
@@ -0,0 +1,124 @@
+package inventory
+
+import (
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/db"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/logger"
+)
+
+// LowStockMonitor periodically compares stock levels against reorder rules,
+// raising an alert when a product falls below its minimum and resolving it
+// once the product is back at or above it.
+type LowStockMonitor struct {
+	db          *db.DB
+	interval    time.Duration
+	stopMonitor chan struct{}
+	wg          sync.WaitGroup
+}
+
+// NewLowStockMonitor creates a low-stock monitor and starts checking stock
+// levels in the background.
+func NewLowStockMonitor(database *db.DB, interval time.Duration) *LowStockMonitor {
+	monitor := &LowStockMonitor{
+		db:          database,
+		interval:    interval,
+		stopMonitor: make(chan struct{}),
+	}
+
+	// Start background check goroutine
+	monitor.start()
+
+	return monitor
+}
+
+// Check raises an alert for each reorder rule whose product has fallen
+// below its minimum and has no open alert, and resolves the open alerts of
+// products that are no longer low or whose rule has been removed.
+func (m *LowStockMonitor) Check() error {
+	now := time.Now()
+	return m.db.Transaction(func(tx *db.DB) error {
+		low, err := NewReorderRuleRepository(tx).FindLowStock(nil, now.UTC())
+		if err != nil {
+			return err
+		}
+
+		alerts := NewAlertRepository(tx)
+		open, err := alerts.FindAll(AlertOpen)
+		if err != nil {
+			return err
+		}
+
+		alerted := make(map[uuid.UUID]bool, len(open))
+		for _, alert := range open {
+			alerted[alert.RuleID] = true
+		}
+		stillLow := make(map[uuid.UUID]bool, len(low))
+		for _, item := range low {
+			stillLow[item.RuleID] = true
+			if alerted[item.RuleID] {
+				continue
+			}
+
+			alert := &StockAlert{
+				RuleID:            item.RuleID,
+				ProductID:         item.ProductID,
+				LocationID:        item.LocationID,
+				Status:            AlertOpen,
+				MinimumQuantity:   item.MinimumQuantity,
+				QuantityAvailable: item.QuantityAvailable,
+				RaisedAt:          now,
+			}
+			if err := alerts.Create(alert); err != nil {
+				return err
+			}
+			logger.Warn("product is low on stock", "alert_id", alert.ID, "product_id", item.ProductID,
+				"product_name", item.ProductName, "location_id", item.LocationID,
+				"quantity_available", item.QuantityAvailable, "minimum_quantity", item.MinimumQuantity,
+				"reorder_quantity", item.ReorderQuantity)
+		}
+
+		for _, alert := range open {
+			if stillLow[alert.RuleID] {
+				continue
+			}
+			if err := alerts.Resolve(alert.ID, now); err != nil {
+				return err
+			}
+			logger.Info("low-stock alert resolved", "alert_id", alert.ID, "product_id", alert.ProductID,
+				"location_id", alert.LocationID)
+		}
+		return nil
+	})
+}
+
+// start starts a background goroutine to periodically check stock levels.
+func (m *LowStockMonitor) start() {
+	m.wg.Add(1)
+
+	go func() {
+		defer m.wg.Done()
+
+		ticker := time.NewTicker(m.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				if err := m.Check(); err != nil {
+					logger.Error("low-stock check failed", "error", err)
+				}
+			case <-m.stopMonitor:
+				return
+			}
+		}
+	}()
+}
+
+// Stop stops the background check goroutine.
+func (m *LowStockMonitor) Stop() {
+	close(m.stopMonitor)
+	m.wg.Wait()
+}
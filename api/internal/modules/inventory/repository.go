@@ -1,11 +1,49 @@
 package inventory
 
 import (
+	"net/http"
+	"time"
+
 	"github.com/google/uuid"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/common"
 	"github.com/shanmugharajk/go-react-web-api/api/internal/db"
 	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/errors"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/money"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/quantity"
+	"gorm.io/gorm"
 )
 
+// ErrInsufficientStock is returned when a batch does not hold enough stock.
+var ErrInsufficientStock = errors.New(http.StatusConflict, errors.ErrConflict, "insufficient stock")
+
+// ErrBatchHasMovements is returned when deleting a batch that holds stock or
+// has stock movements, which the ledger would lose.
+var ErrBatchHasMovements = errors.New(http.StatusConflict, errors.ErrConflict, "product batch has stock movements; write its stock off with an adjustment instead")
+
+// ErrAdjustmentNotPending is returned when approving or rejecting a stock
+// adjustment that has already been decided.
+var ErrAdjustmentNotPending = errors.New(http.StatusConflict, errors.ErrConflict, "stock adjustment is not pending approval")
+
+// ErrAdjustmentNotApplied is returned when reversing a stock adjustment that
+// has not been applied or has already been reversed.
+var ErrAdjustmentNotApplied = errors.New(http.StatusConflict, errors.ErrConflict, "stock adjustment is not applied")
+
+// ErrReorderRuleExists is returned when creating a second reorder rule for
+// the same product and location.
+var ErrReorderRuleExists = errors.New(http.StatusConflict, errors.ErrConflict, "a reorder rule already exists for this product and location")
+
+// ErrNoExpiredStock is returned when writing off expired stock while no
+// expired batch holds any quarantined stock.
+var ErrNoExpiredStock = errors.New(http.StatusUnprocessableEntity, errors.ErrUnprocessable, "there is no quarantined expired stock to write off")
+
+// ErrGridOfVariant is returned when asking for the variant grid of a
+// product that is itself a variant.
+var ErrGridOfVariant = errors.New(http.StatusUnprocessableEntity, errors.ErrUnprocessable, "a variant has no variant grid; use its parent product")
+
+// ErrNotKit is returned when asking for the kit availability of a product
+// that is not a kit.
+var ErrNotKit = errors.New(http.StatusUnprocessableEntity, errors.ErrUnprocessable, "product is not a kit")
+
 // BatchRepository handles data access for product batches.
 type BatchRepository struct {
 	db *db.DB
@@ -16,10 +54,11 @@ func NewBatchRepository(database *db.DB) *BatchRepository {
 	return &BatchRepository{db: database}
 }
 
-// FindAll retrieves all product batches.
-func (r *BatchRepository) FindAll() ([]ProductBatch, error) {
+// FindAll retrieves all product batches, or those held at a location when
+// one is given.
+func (r *BatchRepository) FindAll(locationID *uuid.UUID) ([]ProductBatch, error) {
 	var batches []ProductBatch
-	if err := r.db.Find(&batches).Error; err != nil {
+	if err := atLocation(r.db.DB, locationID).Find(&batches).Error; err != nil {
 		return nil, err
 	}
 	return batches, nil
@@ -34,10 +73,81 @@ func (r *BatchRepository) FindByID(id uuid.UUID) (*ProductBatch, error) {
 	return &batch, nil
 }
 
-// FindByProductID retrieves all batches for a product.
-func (r *BatchRepository) FindByProductID(productID uuid.UUID) ([]ProductBatch, error) {
+// FindByProductID retrieves all batches for a product, or those held at a
+// location when one is given.
+func (r *BatchRepository) FindByProductID(productID uuid.UUID, locationID *uuid.UUID) ([]ProductBatch, error) {
+	var batches []ProductBatch
+	if err := atLocation(r.db.Where("product_id = ?", productID), locationID).Find(&batches).Error; err != nil {
+		return nil, err
+	}
+	return batches, nil
+}
+
+// SumAvailable returns the stock available in batches of the given products
+// that have not expired at the given time, by product. Only batches held at
+// the location are counted when one is given.
+func (r *BatchRepository) SumAvailable(productIDs []uuid.UUID, locationID *uuid.UUID, at time.Time) (map[uuid.UUID]quantity.Quantity, error) {
+	var rows []struct {
+		ProductID uuid.UUID
+		Quantity  quantity.Quantity
+	}
+	query := r.db.
+		Table("product_batches").
+		Select("product_batches.product_id, COALESCE(SUM(product_batches.quantity_available), 0) AS quantity").
+		Where("product_batches.product_id IN ?", productIDs).
+		Where("(product_batches.expires_at IS NULL OR product_batches.expires_at > ?)", at.UTC()).
+		Group("product_batches.product_id")
+	if err := atLocation(query, locationID).Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	available := make(map[uuid.UUID]quantity.Quantity, len(rows))
+	for _, row := range rows {
+		available[row.ProductID] = row.Quantity
+	}
+	return available, nil
+}
+
+// FindByCategoryIDs retrieves all batches of the products in the given
+// categories, or those held at a location when one is given.
+func (r *BatchRepository) FindByCategoryIDs(categoryIDs []uuid.UUID, locationID *uuid.UUID) ([]ProductBatch, error) {
 	var batches []ProductBatch
-	if err := r.db.Where("product_id = ?", productID).Find(&batches).Error; err != nil {
+	query := r.db.
+		Joins("JOIN products ON products.id = product_batches.product_id").
+		Where("products.category_id IN ?", categoryIDs)
+	err := atLocation(query, locationID).
+		Order("product_batches.product_id, product_batches.purchased_at").
+		Find(&batches).Error
+	if err != nil {
+		return nil, err
+	}
+	return batches, nil
+}
+
+// FindInStock retrieves the batches that hold available stock, or those
+// held at a location when one is given.
+func (r *BatchRepository) FindInStock(locationID *uuid.UUID) ([]ProductBatch, error) {
+	var batches []ProductBatch
+	query := r.db.Where("quantity_available > 0")
+	if err := atLocation(query, locationID).Find(&batches).Error; err != nil {
+		return nil, err
+	}
+	return batches, nil
+}
+
+// FindAllocatable retrieves the batches of a product that can be picked at
+// the given time, in picking order: earliest expiry first, batches without
+// an expiry date last, ties broken by purchase date (FIFO). Only batches
+// held at the location are returned when one is given.
+func (r *BatchRepository) FindAllocatable(productID uuid.UUID, locationID *uuid.UUID, at time.Time) ([]ProductBatch, error) {
+	var batches []ProductBatch
+	query := r.db.
+		Where("product_id = ? AND quantity_available > 0", productID).
+		Where("expires_at IS NULL OR expires_at > ?", at)
+	err := atLocation(query, locationID).
+		Order("expires_at IS NULL, expires_at ASC, purchased_at ASC, created_at ASC").
+		Find(&batches).Error
+	if err != nil {
 		return nil, err
 	}
 	return batches, nil
@@ -51,9 +161,55 @@ func (r *BatchRepository) Create(batch *ProductBatch) error {
 	return r.db.Create(batch).Error
 }
 
-// Update updates an existing product batch.
+// Update updates an existing product batch. The available quantity is
+// never written here; it only changes through ApplyMovement.
 func (r *BatchRepository) Update(batch *ProductBatch) error {
-	return r.db.Save(batch).Error
+	return r.db.Omit("quantity_available", "quantity_quarantined").Save(batch).Error
+}
+
+// ApplyMovement changes a batch's quantity in the movement's bucket by the
+// movement's quantity and records the movement. Decrements are checked and
+// applied in a single statement so concurrent writers cannot take the batch
+// below zero. The movement's BatchID, ProductID, Type and Quantity must be
+// set; ID, BalanceAfter and an empty Bucket (available) are filled in.
+func (r *BatchRepository) ApplyMovement(movement *StockMovement) error {
+	if movement.Bucket == "" {
+		movement.Bucket = BucketAvailable
+	}
+	column := "quantity_available"
+	if movement.Bucket == BucketQuarantine {
+		column = "quantity_quarantined"
+	}
+
+	return r.db.Transaction(func(tx *db.DB) error {
+		query := tx.Model(&ProductBatch{}).Where("id = ?", movement.BatchID)
+		if movement.Quantity < 0 {
+			query = query.Where(column+" >= ?", -movement.Quantity)
+		}
+
+		result := query.Update(column, gorm.Expr(column+" + ?", movement.Quantity))
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			if _, err := NewBatchRepository(tx).FindByID(movement.BatchID); err != nil {
+				return err
+			}
+			return ErrInsufficientStock
+		}
+
+		var balance quantity.Quantity
+		if err := tx.Model(&ProductBatch{}).Where("id = ?", movement.BatchID).
+			Select(column).Scan(&balance).Error; err != nil {
+			return err
+		}
+
+		if movement.ID == uuid.Nil {
+			movement.ID = uuid.New()
+		}
+		movement.BalanceAfter = balance
+		return tx.Create(movement).Error
+	})
 }
 
 // Delete deletes a product batch by ID.
@@ -67,3 +223,477 @@ func (r *BatchRepository) Delete(id uuid.UUID) error {
 	}
 	return nil
 }
+
+// FindExpiring retrieves the batches with stock available that expire after
+// at but no later than until, soonest first. Only batches held at the
+// location are returned when one is given.
+func (r *BatchRepository) FindExpiring(at, until time.Time, locationID *uuid.UUID) ([]ExpiringBatch, error) {
+	query := r.db.
+		Where("product_batches.quantity_available > 0").
+		Where("product_batches.expires_at > ? AND product_batches.expires_at <= ?", at, until)
+	return findExpiryReport(atLocation(query, locationID))
+}
+
+// FindExpired retrieves the batches that have expired at the given time and
+// still hold stock, available or quarantined, earliest expiry first. Only
+// batches held at the location are returned when one is given.
+func (r *BatchRepository) FindExpired(at time.Time, locationID *uuid.UUID) ([]ExpiringBatch, error) {
+	query := r.db.
+		Where("product_batches.quantity_available > 0 OR product_batches.quantity_quarantined > 0").
+		Where("product_batches.expires_at <= ?", at)
+	return findExpiryReport(atLocation(query, locationID))
+}
+
+// findExpiryReport runs a batch query for an expiry report, adding each
+// batch's product name.
+func findExpiryReport(query *gorm.DB) ([]ExpiringBatch, error) {
+	batches := []ExpiringBatch{}
+	err := query.
+		Table("product_batches").
+		Select("product_batches.*, products.name AS product_name").
+		Joins("JOIN products ON products.id = product_batches.product_id").
+		Order("product_batches.expires_at ASC, products.name ASC").
+		Scan(&batches).Error
+	if err != nil {
+		return nil, err
+	}
+	return batches, nil
+}
+
+// atLocation narrows a batch query to a location when one is given.
+func atLocation(query *gorm.DB, locationID *uuid.UUID) *gorm.DB {
+	if locationID == nil {
+		return query
+	}
+	return query.Where("product_batches.location_id = ?", *locationID)
+}
+
+// MovementRepository handles data access for stock movements.
+// Movements are append-only, so there is no update or delete.
+type MovementRepository struct {
+	db *db.DB
+}
+
+// NewMovementRepository creates a new stock movement repository.
+func NewMovementRepository(database *db.DB) *MovementRepository {
+	return &MovementRepository{db: database}
+}
+
+// FindByBatchID retrieves the movements of a batch in the order they happened.
+func (r *MovementRepository) FindByBatchID(batchID uuid.UUID) ([]StockMovement, error) {
+	var movements []StockMovement
+	if err := r.db.Where("batch_id = ?", batchID).Order("created_at ASC").Find(&movements).Error; err != nil {
+		return nil, err
+	}
+	return movements, nil
+}
+
+// FindByProductID retrieves the movements of all batches of a product in the
+// order they happened.
+func (r *MovementRepository) FindByProductID(productID uuid.UUID) ([]StockMovement, error) {
+	var movements []StockMovement
+	if err := r.db.Where("product_id = ?", productID).Order("created_at ASC").Find(&movements).Error; err != nil {
+		return nil, err
+	}
+	return movements, nil
+}
+
+// CountByBatchID returns the number of movements of a batch.
+func (r *MovementRepository) CountByBatchID(batchID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.Model(&StockMovement{}).Where("batch_id = ?", batchID).Count(&count).Error
+	return count, err
+}
+
+// SumExpiryQuarantine returns the quarantined quantity of each of the
+// batches that was moved there for expiry and not yet written off: their
+// expiry quarantine movements less their expired write-offs, net of
+// reversals. Batches without any are left out.
+func (r *MovementRepository) SumExpiryQuarantine(batchIDs []uuid.UUID) (map[uuid.UUID]quantity.Quantity, error) {
+	var rows []struct {
+		BatchID  uuid.UUID
+		Quantity quantity.Quantity
+	}
+	err := r.db.
+		Table("stock_movements").
+		Select("stock_movements.batch_id, SUM(stock_movements.quantity) AS quantity").
+		Joins("LEFT JOIN stock_adjustments ON stock_movements.reference_type = 'stock_adjustment' AND stock_adjustments.id = stock_movements.reference_id").
+		Where("stock_movements.batch_id IN ? AND stock_movements.bucket = ?", batchIDs, BucketQuarantine).
+		Where("stock_movements.type = ? OR (stock_movements.type = ? AND stock_adjustments.reason = ?)",
+			MovementQuarantine, MovementAdjustment, ReasonExpired).
+		Group("stock_movements.batch_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	sums := make(map[uuid.UUID]quantity.Quantity, len(rows))
+	for _, row := range rows {
+		sums[row.BatchID] = row.Quantity
+	}
+	return sums, nil
+}
+
+// SumByBatchID returns the net available quantity recorded in a batch's ledger.
+func (r *MovementRepository) SumByBatchID(batchID uuid.UUID) (quantity.Quantity, error) {
+	var total quantity.Quantity
+	err := r.db.Model(&StockMovement{}).Where("batch_id = ? AND bucket = ?", batchID, BucketAvailable).
+		Select("COALESCE(SUM(quantity), 0)").Scan(&total).Error
+	return total, err
+}
+
+// AdjustmentRepository handles data access for stock adjustments.
+type AdjustmentRepository struct {
+	db *db.DB
+}
+
+// NewAdjustmentRepository creates a new stock adjustment repository.
+func NewAdjustmentRepository(database *db.DB) *AdjustmentRepository {
+	return &AdjustmentRepository{db: database}
+}
+
+// FindAll retrieves stock adjustments, newest first, optionally only those
+// with the given status.
+func (r *AdjustmentRepository) FindAll(status AdjustmentStatus) ([]StockAdjustment, error) {
+	var adjustments []StockAdjustment
+	query := r.db.Preload("Lines")
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if err := query.Order("created_at DESC").Find(&adjustments).Error; err != nil {
+		return nil, err
+	}
+	return adjustments, nil
+}
+
+// FindByID retrieves a stock adjustment with its lines.
+func (r *AdjustmentRepository) FindByID(id uuid.UUID) (*StockAdjustment, error) {
+	var adjustment StockAdjustment
+	if err := r.db.Preload("Lines").First(&adjustment, id).Error; err != nil {
+		return nil, err
+	}
+	return &adjustment, nil
+}
+
+// SumPending returns the quantity that pending adjustments for a reason
+// would change a bucket of each of the batches by, which is negative for
+// removals. Batches without any are left out.
+func (r *AdjustmentRepository) SumPending(batchIDs []uuid.UUID, reason AdjustmentReason, bucket StockBucket) (map[uuid.UUID]quantity.Quantity, error) {
+	var rows []struct {
+		BatchID  uuid.UUID
+		Quantity quantity.Quantity
+	}
+	err := r.db.
+		Table("stock_adjustment_lines").
+		Select("stock_adjustment_lines.batch_id, SUM(stock_adjustment_lines.quantity) AS quantity").
+		Joins("JOIN stock_adjustments ON stock_adjustments.id = stock_adjustment_lines.adjustment_id").
+		Where("stock_adjustment_lines.batch_id IN ? AND stock_adjustment_lines.bucket = ?", batchIDs, bucket).
+		Where("stock_adjustments.status = ? AND stock_adjustments.reason = ?", AdjustmentPending, reason).
+		Group("stock_adjustment_lines.batch_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	sums := make(map[uuid.UUID]quantity.Quantity, len(rows))
+	for _, row := range rows {
+		sums[row.BatchID] = row.Quantity
+	}
+	return sums, nil
+}
+
+// Create creates a stock adjustment with its lines. It does not change any
+// stock.
+func (r *AdjustmentRepository) Create(adjustment *StockAdjustment) error {
+	if adjustment.ID == uuid.Nil {
+		adjustment.ID = uuid.New()
+	}
+	for i := range adjustment.Lines {
+		if adjustment.Lines[i].ID == uuid.Nil {
+			adjustment.Lines[i].ID = uuid.New()
+		}
+	}
+	return r.db.Create(adjustment).Error
+}
+
+// Approve marks a pending stock adjustment as applied by an approver.
+func (r *AdjustmentRepository) Approve(id uuid.UUID, userID uuid.UUID, at time.Time) error {
+	return r.transition(id, AdjustmentPending, AdjustmentApplied, ErrAdjustmentNotPending,
+		map[string]any{"approved_by": userID, "approved_at": at, "updated_by": userID})
+}
+
+// Reject marks a pending stock adjustment as rejected.
+func (r *AdjustmentRepository) Reject(id uuid.UUID, userID uuid.UUID, at time.Time) error {
+	return r.transition(id, AdjustmentPending, AdjustmentRejected, ErrAdjustmentNotPending,
+		map[string]any{"rejected_by": userID, "rejected_at": at, "updated_by": userID})
+}
+
+// Reverse marks an applied stock adjustment as reversed.
+func (r *AdjustmentRepository) Reverse(id uuid.UUID, userID uuid.UUID, at time.Time) error {
+	return r.transition(id, AdjustmentApplied, AdjustmentReversed, ErrAdjustmentNotApplied,
+		map[string]any{"reversed_by": userID, "reversed_at": at, "updated_by": userID})
+}
+
+// transition moves a stock adjustment from one status to another in a
+// single conditional update, so two users cannot decide the same
+// adjustment. It fails with notFrom if the adjustment is not in the from
+// status.
+func (r *AdjustmentRepository) transition(id uuid.UUID, from, to AdjustmentStatus, notFrom error, columns map[string]any) error {
+	columns["status"] = to
+	result := r.db.Model(&StockAdjustment{}).Where("id = ? AND status = ?", id, from).Updates(columns)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return notFrom
+	}
+	return nil
+}
+
+// ReorderRuleRepository handles data access for reorder rules.
+type ReorderRuleRepository struct {
+	db *db.DB
+}
+
+// NewReorderRuleRepository creates a new reorder rule repository.
+func NewReorderRuleRepository(database *db.DB) *ReorderRuleRepository {
+	return &ReorderRuleRepository{db: database}
+}
+
+// FindAll retrieves all reorder rules, or those for a location when one is
+// given.
+func (r *ReorderRuleRepository) FindAll(locationID *uuid.UUID) ([]ReorderRule, error) {
+	var rules []ReorderRule
+	query := r.db.Order("created_at ASC")
+	if locationID != nil {
+		query = query.Where("location_id = ?", *locationID)
+	}
+	if err := query.Find(&rules).Error; err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// FindByID retrieves a reorder rule by ID.
+func (r *ReorderRuleRepository) FindByID(id uuid.UUID) (*ReorderRule, error) {
+	var rule ReorderRule
+	if err := r.db.First(&rule, id).Error; err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+// FindByProductID retrieves the reorder rule for a product at a location, or
+// the rule covering every location when locationID is nil.
+func (r *ReorderRuleRepository) FindByProductID(productID uuid.UUID, locationID *uuid.UUID) (*ReorderRule, error) {
+	var rule ReorderRule
+	query := r.db.Where("product_id = ?", productID)
+	if locationID != nil {
+		query = query.Where("location_id = ?", *locationID)
+	} else {
+		query = query.Where("location_id IS NULL")
+	}
+	if err := query.First(&rule).Error; err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+// Create creates a new reorder rule.
+func (r *ReorderRuleRepository) Create(rule *ReorderRule) error {
+	if rule.ID == uuid.Nil {
+		rule.ID = uuid.New()
+	}
+	return r.db.Create(rule).Error
+}
+
+// Update updates an existing reorder rule.
+func (r *ReorderRuleRepository) Update(rule *ReorderRule) error {
+	return r.db.Save(rule).Error
+}
+
+// Delete deletes a reorder rule by ID.
+func (r *ReorderRuleRepository) Delete(id uuid.UUID) error {
+	result := r.db.Delete(&ReorderRule{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.ErrNotFound
+	}
+	return nil
+}
+
+// FindLowStock retrieves the reorder rules whose product has less stock
+// available than the rule's minimum, counting only batches that have not
+// expired at the given time. A rule with a location counts the batches held
+// there; one without counts every batch of the product. Only rules for a
+// location are returned when one is given.
+func (r *ReorderRuleRepository) FindLowStock(locationID *uuid.UUID, at time.Time) ([]LowStockItem, error) {
+	stock := r.db.
+		Table("reorder_rules").
+		Select(`reorder_rules.id AS rule_id, reorder_rules.product_id, products.name AS product_name,
+			products.base_unit AS unit, reorder_rules.location_id, reorder_rules.minimum_quantity, reorder_rules.reorder_quantity,
+			(SELECT COALESCE(SUM(product_batches.quantity_available), 0) FROM product_batches
+				WHERE product_batches.product_id = reorder_rules.product_id
+				AND (reorder_rules.location_id IS NULL OR product_batches.location_id = reorder_rules.location_id)
+				AND (product_batches.expires_at IS NULL OR product_batches.expires_at > ?)) AS quantity_available`, at).
+		Joins("JOIN products ON products.id = reorder_rules.product_id")
+	if locationID != nil {
+		stock = stock.Where("reorder_rules.location_id = ?", *locationID)
+	}
+
+	items := []LowStockItem{}
+	err := r.db.
+		Table("(?) AS stock", stock).
+		Select("*, minimum_quantity - quantity_available AS shortfall").
+		Where("quantity_available < minimum_quantity").
+		Order("product_name ASC").
+		Scan(&items).Error
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// AlertRepository handles data access for stock alerts.
+type AlertRepository struct {
+	db *db.DB
+}
+
+// NewAlertRepository creates a new stock alert repository.
+func NewAlertRepository(database *db.DB) *AlertRepository {
+	return &AlertRepository{db: database}
+}
+
+// FindAll retrieves stock alerts, newest first, optionally only those with
+// the given status.
+func (r *AlertRepository) FindAll(status AlertStatus) ([]StockAlert, error) {
+	var alerts []StockAlert
+	query := r.db.Order("raised_at DESC")
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if err := query.Find(&alerts).Error; err != nil {
+		return nil, err
+	}
+	return alerts, nil
+}
+
+// FindByID retrieves a stock alert by ID.
+func (r *AlertRepository) FindByID(id uuid.UUID) (*StockAlert, error) {
+	var alert StockAlert
+	if err := r.db.First(&alert, id).Error; err != nil {
+		return nil, err
+	}
+	return &alert, nil
+}
+
+// Create creates a new stock alert.
+func (r *AlertRepository) Create(alert *StockAlert) error {
+	if alert.ID == uuid.Nil {
+		alert.ID = uuid.New()
+	}
+	return r.db.Create(alert).Error
+}
+
+// Resolve marks an open stock alert as resolved.
+func (r *AlertRepository) Resolve(id uuid.UUID, at time.Time) error {
+	return r.db.Model(&StockAlert{}).Where("id = ? AND status = ?", id, AlertOpen).
+		Updates(map[string]any{"status": AlertResolved, "resolved_at": at}).Error
+}
+
+// MigrateMoneyToMinorUnits converts batch prices stored as decimals to exact
+// minor units.
+var MigrateMoneyToMinorUnits = db.Migration{
+	ID: "20261016_inventory_money_minor_units",
+	Up: func(tx *db.DB) error {
+		return money.MigrateColumns(tx.DB, "product_batches", "cost_price", "selling_price")
+	},
+}
+
+// MigrateQuantitiesToThousandths converts batch, movement, adjustment and
+// reorder quantities stored as whole units to thousandths.
+var MigrateQuantitiesToThousandths = db.Migration{
+	ID: "20261017_inventory_quantity_thousandths",
+	Up: func(tx *db.DB) error {
+		tables := []struct {
+			name    string
+			columns []string
+		}{
+			{"product_batches", []string{"quantity_available", "quantity_quarantined"}},
+			{"stock_movements", []string{"quantity", "balance_after"}},
+			{"stock_adjustment_lines", []string{"quantity"}},
+			{"reorder_rules", []string{"minimum_quantity", "reorder_quantity"}},
+			{"stock_alerts", []string{"minimum_quantity", "quantity_available"}},
+		}
+		for _, t := range tables {
+			if err := quantity.MigrateColumns(tx.DB, t.name, t.columns...); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+}
+
+// MigrateBatchTimesToUTC rewrites batch purchase and expiry times stored
+// with other UTC offsets in UTC, so that they compare in time order.
+var MigrateBatchTimesToUTC = db.Migration{
+	ID: "20261018_inventory_batch_times_utc",
+	Up: func(tx *db.DB) error {
+		var batches []ProductBatch
+		if err := tx.Select("id", "purchased_at", "expires_at").Find(&batches).Error; err != nil {
+			return err
+		}
+		for _, batch := range batches {
+			err := tx.Model(&ProductBatch{}).Where("id = ?", batch.ID).UpdateColumns(map[string]any{
+				"purchased_at": batch.PurchasedAt.UTC(),
+				"expires_at":   common.UTC(batch.ExpiresAt),
+			}).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+}
+
+// MigrateOpeningMovements records an opening adjustment for every batch whose
+// quantity predates the stock movement ledger, so that each batch's ledger
+// sums to its QuantityAvailable.
+var MigrateOpeningMovements = db.Migration{
+	ID: "20261016_inventory_opening_movements",
+	Up: func(tx *db.DB) error {
+		var batches []ProductBatch
+		if err := tx.Find(&batches).Error; err != nil {
+			return err
+		}
+
+		movements := NewMovementRepository(tx)
+		for _, batch := range batches {
+			recorded, err := movements.SumByBatchID(batch.ID)
+			if err != nil {
+				return err
+			}
+			if recorded == batch.QuantityAvailable {
+				continue
+			}
+
+			opening := &StockMovement{
+				ID:           uuid.New(),
+				BatchID:      batch.ID,
+				ProductID:    batch.ProductID,
+				Type:         MovementAdjustment,
+				Bucket:       BucketAvailable,
+				Quantity:     batch.QuantityAvailable - recorded,
+				BalanceAfter: batch.QuantityAvailable,
+				Note:         "opening balance",
+				CreatedBy:    batch.CreatedBy,
+			}
+			if err := tx.Create(opening).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+}
@@ -1,26 +1,42 @@
 package inventory
 
 import (
+	"cmp"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
 	"github.com/google/uuid"
 	"github.com/shanmugharajk/go-react-web-api/api/internal/common"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/config"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/db"
 	"github.com/shanmugharajk/go-react-web-api/api/internal/modules/auth"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/modules/location"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/modules/product"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/errors"
 	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/logger"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/money"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/quantity"
 	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/validator"
 )
 
 // BatchService handles business logic for product batches.
 type BatchService struct {
+	db   *db.DB
 	repo *BatchRepository
 }
 
 // NewBatchService creates a new batch service.
-func NewBatchService(repo *BatchRepository) *BatchService {
-	return &BatchService{repo: repo}
+func NewBatchService(database *db.DB, repo *BatchRepository) *BatchService {
+	return &BatchService{db: database, repo: repo}
 }
 
-// GetAll retrieves all product batches.
-func (s *BatchService) GetAll() ([]ProductBatch, error) {
-	return s.repo.FindAll()
+// GetAll retrieves all product batches, or those held at a location when
+// one is given.
+func (s *BatchService) GetAll(locationID *uuid.UUID) ([]ProductBatch, error) {
+	return s.repo.FindAll(locationID)
 }
 
 // GetByID retrieves a product batch by ID.
@@ -28,38 +44,301 @@ func (s *BatchService) GetByID(id uuid.UUID) (*ProductBatch, error) {
 	return s.repo.FindByID(id)
 }
 
-// GetByProductID retrieves all batches for a product.
-func (s *BatchService) GetByProductID(productID uuid.UUID) ([]ProductBatch, error) {
-	return s.repo.FindByProductID(productID)
+// GetByProductID retrieves all batches for a product, or those held at a
+// location when one is given.
+func (s *BatchService) GetByProductID(productID uuid.UUID, locationID *uuid.UUID) ([]ProductBatch, error) {
+	return s.repo.FindByProductID(productID, locationID)
+}
+
+// VariantGrid returns the stock of a product's variants for every
+// combination of its category's attribute values, held at a location when
+// one is given.
+func (s *BatchService) VariantGrid(productID uuid.UUID, locationID *uuid.UUID) (*VariantGrid, error) {
+	products := product.NewProductRepository(s.db)
+	p, err := products.FindByID(productID)
+	if err != nil {
+		return nil, err
+	}
+	if p.IsVariant() {
+		return nil, ErrGridOfVariant
+	}
+
+	attributes, err := product.NewCategoryRepository(s.db).FindAttributes(p.CategoryID)
+	if err != nil {
+		return nil, err
+	}
+	if len(attributes) == 0 {
+		return nil, product.ErrNoAttributes
+	}
+
+	variants, err := products.FindVariants(p.ID)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]uuid.UUID, len(variants))
+	byValues := make(map[string]*product.Product, len(variants))
+	for i := range variants {
+		ids[i] = variants[i].ID
+		byValues[valuesKey(attributes, variants[i].Attributes)] = &variants[i]
+	}
+
+	available, err := s.repo.SumAvailable(ids, locationID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	grid := &VariantGrid{
+		ProductID:  p.ID,
+		LocationID: locationID,
+		Unit:       p.BaseUnit,
+		Attributes: attributes,
+		Cells:      []VariantGridCell{},
+	}
+	for _, combination := range combinations(attributes) {
+		cell := VariantGridCell{Values: make(map[string]string, len(attributes))}
+		for _, v := range combination {
+			for _, a := range attributes {
+				if a.ID == v.AttributeID {
+					cell.Values[a.Name] = v.Value
+				}
+			}
+		}
+		if variant, ok := byValues[valuesKey(attributes, combination)]; ok {
+			cell.VariantID = &variant.ID
+			cell.SKU = variant.SKU
+			cell.QuantityAvailable = available[variant.ID]
+		}
+		grid.QuantityAvailable += cell.QuantityAvailable
+		grid.Cells = append(grid.Cells, cell)
+	}
+
+	return grid, nil
+}
+
+// KitAvailability returns how many of a kit the stock of its components
+// makes up, counting stock held at a location when one is given.
+func (s *BatchService) KitAvailability(productID uuid.UUID, locationID *uuid.UUID) (*KitAvailability, error) {
+	products := product.NewProductRepository(s.db)
+	kit, err := products.FindByID(productID)
+	if err != nil {
+		return nil, err
+	}
+	if !kit.IsKit() {
+		return nil, ErrNotKit
+	}
+
+	ids := make([]uuid.UUID, len(kit.Components))
+	for i, c := range kit.Components {
+		ids[i] = c.ComponentID
+	}
+	components, err := products.FindByIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+	available, err := s.repo.SumAvailable(ids, locationID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	availability := &KitAvailability{
+		ProductID:         kit.ID,
+		LocationID:        locationID,
+		Components:        make([]ComponentAvailability, 0, len(kit.Components)),
+		QuantityAvailable: kit.Kits(available),
+	}
+	for _, c := range kit.Components {
+		unit := ""
+		for _, p := range components {
+			if p.ID == c.ComponentID {
+				unit = p.BaseUnit
+			}
+		}
+		availability.Components = append(availability.Components, ComponentAvailability{
+			ProductID:         c.ComponentID,
+			Unit:              unit,
+			Quantity:          c.Quantity,
+			QuantityAvailable: available[c.ComponentID],
+			Kits:              kit.ComponentKits(c, available[c.ComponentID]),
+		})
+	}
+
+	return availability, nil
 }
 
-// Create creates a new product batch.
+// StockByCategory returns the available stock of every category holding
+// any, with the categories below a level rolled up into their ancestor at
+// that level, counting stock held at a location when one is given.
+func (s *BatchService) StockByCategory(level int, locationID *uuid.UUID) ([]CategoryStock, error) {
+	batches, err := s.repo.FindInStock(locationID)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]uuid.UUID, 0, len(batches))
+	for _, b := range batches {
+		ids = append(ids, b.ProductID)
+	}
+	products, err := product.NewProductRepository(s.db).FindByIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+	categories, err := product.NewCategoryRepository(s.db).FindAll()
+	if err != nil {
+		return nil, err
+	}
+
+	productCategories := make(map[uuid.UUID]uuid.UUID, len(products))
+	for _, p := range products {
+		productCategories[p.ID] = p.CategoryID
+	}
+	names := make(map[uuid.UUID]string, len(categories))
+	for _, c := range categories {
+		names[c.ID] = c.Name
+	}
+	hierarchy := product.NewHierarchy(categories)
+
+	stock := make(map[uuid.UUID]*CategoryStock)
+	counted := make(map[uuid.UUID]bool)
+	for _, b := range batches {
+		categoryID := hierarchy.Ancestor(productCategories[b.ProductID], level)
+		row, ok := stock[categoryID]
+		if !ok {
+			row = &CategoryStock{
+				CategoryID: categoryID,
+				Name:       names[categoryID],
+				Level:      len(hierarchy.Path(categoryID)) - 1,
+			}
+			stock[categoryID] = row
+		}
+		if !counted[b.ProductID] {
+			counted[b.ProductID] = true
+			row.Products++
+		}
+		row.CostValue += b.CostPrice.Mul(b.QuantityAvailable)
+		row.RetailValue += b.SellingPrice.Mul(b.QuantityAvailable)
+	}
+
+	rows := make([]CategoryStock, 0, len(stock))
+	for _, row := range stock {
+		rows = append(rows, *row)
+	}
+	slices.SortFunc(rows, func(a, b CategoryStock) int { return cmp.Compare(a.Name, b.Name) })
+	return rows, nil
+}
+
+// combinations returns every combination of the values of the attributes,
+// the first attribute varying slowest.
+func combinations(attributes []product.Attribute) [][]product.VariantAttribute {
+	combinations := [][]product.VariantAttribute{{}}
+	for _, a := range attributes {
+		next := make([][]product.VariantAttribute, 0, len(combinations)*len(a.Values))
+		for _, c := range combinations {
+			for _, v := range a.Values {
+				next = append(next, append(slices.Clone(c), product.VariantAttribute{AttributeID: a.ID, Value: v.Value}))
+			}
+		}
+		combinations = next
+	}
+	return combinations
+}
+
+// valuesKey returns a key identifying attribute values, taken in attribute
+// order.
+func valuesKey(attributes []product.Attribute, values []product.VariantAttribute) string {
+	var key strings.Builder
+	for _, a := range attributes {
+		for _, v := range values {
+			if v.AttributeID == a.ID {
+				key.WriteString(v.Value)
+			}
+		}
+		key.WriteByte(0)
+	}
+	return key.String()
+}
+
+// Create creates a new product batch at the requested location, or the
+// user's default location.
 func (s *BatchService) Create(req CreateProductBatchRequest, user *auth.User) (*ProductBatch, error) {
 	if err := validator.Struct(req); err != nil {
 		return nil, err
 	}
 
+	products := product.NewProductRepository(s.db)
+	p, err := products.FindByID(req.ProductID)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil, validator.ValidationErrors{{Field: "productId", Message: "product not found"}}
+		}
+		return nil, err
+	}
+	if err := p.CheckQuantity("quantityAvailable", req.QuantityAvailable); err != nil {
+		return nil, err
+	}
+	variants, err := products.CountVariants(p.ID)
+	if err != nil {
+		return nil, err
+	}
+	if variants > 0 {
+		return nil, validator.ValidationErrors{{Field: "productId", Message: "stock is held by the product's variants"}}
+	}
+	if p.IsKit() {
+		return nil, validator.ValidationErrors{{Field: "productId", Message: "stock is held by the kit's components"}}
+	}
+
+	locationID, err := location.NewResolver(s.db).Resolve(req.LocationID, user)
+	if err != nil {
+		return nil, err
+	}
+
 	batch := &ProductBatch{
-		ProductID:         req.ProductID,
-		CostPrice:         req.CostPrice,
-		SellingPrice:      req.SellingPrice,
-		QuantityAvailable: req.QuantityAvailable,
-		PurchasedAt:       req.PurchasedAt,
-		ExpiresAt:         req.ExpiresAt,
+		ProductID:    req.ProductID,
+		LocationID:   locationID,
+		CostPrice:    req.CostPrice,
+		SellingPrice: req.SellingPrice,
+		PurchasedAt:  req.PurchasedAt.UTC(),
+		ExpiresAt:    common.UTC(req.ExpiresAt),
 		AuditFields: common.AuditFields{
 			CreatedBy: user.ID,
 			UpdatedBy: user.ID,
 		},
 	}
 
-	if err := s.repo.Create(batch); err != nil {
+	// The batch starts empty and its opening quantity is recorded as a
+	// receipt, so the ledger always accounts for the full quantity.
+	err = s.db.Transaction(func(tx *db.DB) error {
+		batches := NewBatchRepository(tx)
+		if err := batches.Create(batch); err != nil {
+			return err
+		}
+		if req.QuantityAvailable == 0 {
+			return nil
+		}
+
+		movement := &StockMovement{
+			BatchID:       batch.ID,
+			ProductID:     batch.ProductID,
+			Type:          MovementReceipt,
+			Quantity:      req.QuantityAvailable,
+			ReferenceType: "product_batch",
+			ReferenceID:   &batch.ID,
+			CreatedBy:     user.ID,
+		}
+		if err := batches.ApplyMovement(movement); err != nil {
+			return err
+		}
+		batch.QuantityAvailable = movement.BalanceAfter
+		return nil
+	})
+	if err != nil {
 		return nil, err
 	}
 
 	return batch, nil
 }
 
-// Update updates an existing product batch.
+// Update updates an existing product batch. Its quantities are not
+// changed here; they only change through stock movements, such as those of
+// a stock adjustment.
 func (s *BatchService) Update(id uuid.UUID, req UpdateProductBatchRequest, user *auth.User) (*ProductBatch, error) {
 	if err := validator.Struct(req); err != nil {
 		return nil, err
@@ -72,9 +351,8 @@ func (s *BatchService) Update(id uuid.UUID, req UpdateProductBatchRequest, user
 
 	batch.CostPrice = req.CostPrice
 	batch.SellingPrice = req.SellingPrice
-	batch.QuantityAvailable = req.QuantityAvailable
-	batch.PurchasedAt = req.PurchasedAt
-	batch.ExpiresAt = req.ExpiresAt
+	batch.PurchasedAt = req.PurchasedAt.UTC()
+	batch.ExpiresAt = common.UTC(req.ExpiresAt)
 	batch.UpdatedBy = user.ID
 
 	if err := s.repo.Update(batch); err != nil {
@@ -84,12 +362,700 @@ func (s *BatchService) Update(id uuid.UUID, req UpdateProductBatchRequest, user
 	return batch, nil
 }
 
-// Delete deletes a product batch by ID.
+// Reconcile compares a batch's available quantity with the sum of its
+// stock movements.
+func (s *BatchService) Reconcile(id uuid.UUID) (*BatchReconciliation, error) {
+	batch, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	ledger, err := NewMovementRepository(s.db).SumByBatchID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	return &BatchReconciliation{
+		BatchID:           batch.ID,
+		QuantityAvailable: batch.QuantityAvailable,
+		LedgerQuantity:    ledger,
+		InSync:            batch.QuantityAvailable == ledger,
+	}, nil
+}
+
+// Delete deletes a product batch by ID. Only a batch that has never held
+// stock can be deleted, so that the movement ledger keeps accounting for
+// every quantity; stock is removed with an adjustment instead.
 func (s *BatchService) Delete(id uuid.UUID, user *auth.User) error {
-	if err := s.repo.Delete(id); err != nil {
+	err := s.db.Transaction(func(tx *db.DB) error {
+		batches := NewBatchRepository(tx)
+		batch, err := batches.FindByID(id)
+		if err != nil {
+			return err
+		}
+		movements, err := NewMovementRepository(tx).CountByBatchID(id)
+		if err != nil {
+			return err
+		}
+		if movements > 0 || batch.QuantityAvailable != 0 || batch.QuantityQuarantined != 0 {
+			return ErrBatchHasMovements
+		}
+		return batches.Delete(id)
+	})
+	if err != nil {
 		return err
 	}
 
 	logger.Info("product batch deleted", "batch_id", id, "deleted_by", user.ID)
 	return nil
 }
+
+// AllocationService decides which batches stock is picked from.
+// Batches are consumed first-expiry-first-out, falling back to FIFO by
+// purchase date; expired and empty batches are never picked.
+type AllocationService struct {
+	repo *BatchRepository
+}
+
+// NewAllocationService creates a new allocation service.
+func NewAllocationService(repo *BatchRepository) *AllocationService {
+	return &AllocationService{repo: repo}
+}
+
+// Plan computes how a quantity of a product would be split across the
+// batches held at a location, or at any location when none is given,
+// without changing any stock.
+func (s *AllocationService) Plan(productID uuid.UUID, locationID *uuid.UUID, requested quantity.Quantity, at time.Time) ([]Allocation, error) {
+	batches, err := s.repo.FindAllocatable(productID, locationID, at.UTC())
+	if err != nil {
+		return nil, err
+	}
+
+	var allocations []Allocation
+	remaining := requested
+	for _, batch := range batches {
+		if remaining == 0 {
+			break
+		}
+		picked := min(batch.QuantityAvailable, remaining)
+		allocations = append(allocations, Allocation{
+			BatchID:      batch.ID,
+			ProductID:    batch.ProductID,
+			Quantity:     picked,
+			CostPrice:    batch.CostPrice,
+			SellingPrice: batch.SellingPrice,
+			PurchasedAt:  batch.PurchasedAt,
+			ExpiresAt:    batch.ExpiresAt,
+		})
+		remaining -= picked
+	}
+
+	if remaining > 0 {
+		return nil, errors.Newf(http.StatusConflict, errors.ErrConflict,
+			"insufficient stock: requested %s, available %s", requested, requested-remaining)
+	}
+
+	return allocations, nil
+}
+
+// Allocate plans an allocation and takes the picked quantities out of their
+// batches. Each decrement is recorded as a stock movement built from entry,
+// which supplies the movement type, reference, note and user.
+// Callers that need the decrement to be atomic with other writes should
+// construct the service with a transaction-scoped repository.
+func (s *AllocationService) Allocate(productID uuid.UUID, locationID *uuid.UUID, requested quantity.Quantity, at time.Time, entry StockMovement) ([]Allocation, error) {
+	allocations, err := s.Plan(productID, locationID, requested, at)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, a := range allocations {
+		movement := entry
+		movement.BatchID = a.BatchID
+		movement.ProductID = a.ProductID
+		movement.Quantity = -a.Quantity
+		if err := s.repo.ApplyMovement(&movement); err != nil {
+			return nil, err
+		}
+	}
+
+	return allocations, nil
+}
+
+// Preview returns the planned allocation for a product and quantity.
+func (s *AllocationService) Preview(req AllocationPreviewRequest) (*AllocationPreview, error) {
+	if err := validator.Struct(req); err != nil {
+		return nil, err
+	}
+
+	p, err := product.NewProductRepository(s.repo.db).FindByID(req.ProductID)
+	if err != nil {
+		return nil, err
+	}
+	requested, err := p.ToBase(cmp.Or(req.Unit, p.SalesUnit), req.Quantity)
+	if err != nil {
+		return nil, err
+	}
+
+	allocations, err := s.Plan(req.ProductID, req.LocationID, requested, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	preview := &AllocationPreview{
+		ProductID:   req.ProductID,
+		Quantity:    requested,
+		Allocations: allocations,
+	}
+	for _, a := range allocations {
+		preview.Total += a.SellingPrice.Mul(a.Quantity)
+	}
+
+	return preview, nil
+}
+
+// MovementService handles business logic for stock movements.
+type MovementService struct {
+	repo *MovementRepository
+}
+
+// NewMovementService creates a new stock movement service.
+func NewMovementService(repo *MovementRepository) *MovementService {
+	return &MovementService{repo: repo}
+}
+
+// GetByBatchID retrieves the movement history of a batch.
+func (s *MovementService) GetByBatchID(batchID uuid.UUID) ([]StockMovement, error) {
+	return s.repo.FindByBatchID(batchID)
+}
+
+// GetByProductID retrieves the movement history of a product.
+func (s *MovementService) GetByProductID(productID uuid.UUID) ([]StockMovement, error) {
+	return s.repo.FindByProductID(productID)
+}
+
+// AdjustmentService handles business logic for stock adjustments.
+type AdjustmentService struct {
+	db           *db.DB
+	repo         *AdjustmentRepository
+	inventoryCfg config.InventoryConfig
+}
+
+// NewAdjustmentService creates a new stock adjustment service.
+func NewAdjustmentService(database *db.DB, repo *AdjustmentRepository, inventoryCfg config.InventoryConfig) *AdjustmentService {
+	return &AdjustmentService{db: database, repo: repo, inventoryCfg: inventoryCfg}
+}
+
+// GetAll retrieves stock adjustments, optionally only those with a status.
+func (s *AdjustmentService) GetAll(status AdjustmentStatus) ([]StockAdjustment, error) {
+	return s.repo.FindAll(status)
+}
+
+// GetByID retrieves a stock adjustment by ID.
+func (s *AdjustmentService) GetByID(id uuid.UUID) (*StockAdjustment, error) {
+	return s.repo.FindByID(id)
+}
+
+// Create records a stock adjustment. It is applied straight away when its
+// value at cost is within the approval threshold, and otherwise left
+// pending until another user approves it.
+func (s *AdjustmentService) Create(req CreateAdjustmentRequest, user *auth.User) (*StockAdjustment, error) {
+	return s.create(req, user, false)
+}
+
+// Post records and applies a stock adjustment whatever its value, for
+// adjustments that have been reviewed elsewhere, such as the variances of a
+// finalized stocktake.
+func (s *AdjustmentService) Post(req CreateAdjustmentRequest, user *auth.User) (*StockAdjustment, error) {
+	return s.create(req, user, true)
+}
+
+// create records a stock adjustment, applying it if it needs no approval.
+func (s *AdjustmentService) create(req CreateAdjustmentRequest, user *auth.User, approved bool) (*StockAdjustment, error) {
+	if err := validator.Struct(req); err != nil {
+		return nil, err
+	}
+
+	adjustment := &StockAdjustment{
+		Reason: req.Reason,
+		Note:   req.Note,
+		Lines:  make([]AdjustmentLine, len(req.Lines)),
+		AuditFields: common.AuditFields{
+			CreatedBy: user.ID,
+			UpdatedBy: user.ID,
+		},
+	}
+
+	err := s.db.Transaction(func(tx *db.DB) error {
+		batches := NewBatchRepository(tx)
+		products := product.NewProductRepository(tx)
+		for i, line := range req.Lines {
+			batch, err := batches.FindByID(line.BatchID)
+			if err != nil {
+				if errors.IsNotFound(err) {
+					return validator.ValidationErrors{{
+						Field:   fmt.Sprintf("lines[%d].batchId", i),
+						Message: "batch not found",
+					}}
+				}
+				return err
+			}
+			p, err := products.FindByID(batch.ProductID)
+			if err != nil {
+				return err
+			}
+			if err := p.CheckQuantity(fmt.Sprintf("lines[%d].quantity", i), line.Quantity); err != nil {
+				return err
+			}
+			bucket, held := BucketAvailable, batch.QuantityAvailable
+			if line.Bucket == BucketQuarantine {
+				bucket, held = BucketQuarantine, batch.QuantityQuarantined
+			}
+			if held+line.Quantity < 0 {
+				return ErrInsufficientStock
+			}
+			adjustment.Lines[i] = AdjustmentLine{
+				BatchID:   batch.ID,
+				ProductID: batch.ProductID,
+				Bucket:    bucket,
+				Quantity:  line.Quantity,
+				CostPrice: batch.CostPrice,
+			}
+			adjustment.Value += batch.CostPrice.Mul(abs(line.Quantity))
+		}
+
+		adjustment.Status = AdjustmentPending
+		if approved || adjustment.Value <= s.inventoryCfg.AdjustmentApprovalThreshold {
+			adjustment.Status = AdjustmentApplied
+		}
+		if err := NewAdjustmentRepository(tx).Create(adjustment); err != nil {
+			return err
+		}
+		if adjustment.Status == AdjustmentApplied {
+			return applyAdjustment(batches, adjustment, 1, user)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("stock adjustment created", "adjustment_id", adjustment.ID, "reason", adjustment.Reason,
+		"status", adjustment.Status, "value", adjustment.Value.String(), "created_by", user.ID)
+	return adjustment, nil
+}
+
+// Approve applies a pending stock adjustment. The user who created an
+// adjustment cannot approve it.
+func (s *AdjustmentService) Approve(id uuid.UUID, user *auth.User) (*StockAdjustment, error) {
+	var adjustment *StockAdjustment
+	err := s.db.Transaction(func(tx *db.DB) error {
+		adjustments := NewAdjustmentRepository(tx)
+
+		var err error
+		adjustment, err = adjustments.FindByID(id)
+		if err != nil {
+			return err
+		}
+		if adjustment.Status != AdjustmentPending {
+			return ErrAdjustmentNotPending
+		}
+		if adjustment.CreatedBy == user.ID {
+			return errors.New(http.StatusForbidden, errors.ErrForbidden, "stock adjustment must be approved by another user")
+		}
+
+		if err := adjustments.Approve(id, user.ID, time.Now()); err != nil {
+			return err
+		}
+		return applyAdjustment(NewBatchRepository(tx), adjustment, 1, user)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("stock adjustment approved", "adjustment_id", id, "approved_by", user.ID)
+	return s.repo.FindByID(id)
+}
+
+// Reject turns down a pending stock adjustment without changing any stock.
+func (s *AdjustmentService) Reject(id uuid.UUID, user *auth.User) (*StockAdjustment, error) {
+	if _, err := s.repo.FindByID(id); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Reject(id, user.ID, time.Now()); err != nil {
+		return nil, err
+	}
+
+	logger.Info("stock adjustment rejected", "adjustment_id", id, "rejected_by", user.ID)
+	return s.repo.FindByID(id)
+}
+
+// Reverse undoes an applied stock adjustment by recording the opposite
+// movements. It fails if stock the adjustment added has since been sold.
+func (s *AdjustmentService) Reverse(id uuid.UUID, user *auth.User) (*StockAdjustment, error) {
+	err := s.db.Transaction(func(tx *db.DB) error {
+		adjustments := NewAdjustmentRepository(tx)
+
+		adjustment, err := adjustments.FindByID(id)
+		if err != nil {
+			return err
+		}
+		if err := adjustments.Reverse(id, user.ID, time.Now()); err != nil {
+			return err
+		}
+		return applyAdjustment(NewBatchRepository(tx), adjustment, -1, user)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("stock adjustment reversed", "adjustment_id", id, "reversed_by", user.ID)
+	return s.repo.FindByID(id)
+}
+
+// ReorderRuleService handles business logic for reorder rules and the
+// low-stock report.
+type ReorderRuleService struct {
+	db   *db.DB
+	repo *ReorderRuleRepository
+}
+
+// NewReorderRuleService creates a new reorder rule service.
+func NewReorderRuleService(database *db.DB, repo *ReorderRuleRepository) *ReorderRuleService {
+	return &ReorderRuleService{db: database, repo: repo}
+}
+
+// GetAll retrieves all reorder rules, or those for a location when one is
+// given.
+func (s *ReorderRuleService) GetAll(locationID *uuid.UUID) ([]ReorderRule, error) {
+	return s.repo.FindAll(locationID)
+}
+
+// GetByID retrieves a reorder rule by ID.
+func (s *ReorderRuleService) GetByID(id uuid.UUID) (*ReorderRule, error) {
+	return s.repo.FindByID(id)
+}
+
+// GetLowStock retrieves the products that are below their reorder rule's
+// minimum, or only those under rules for a location when one is given.
+func (s *ReorderRuleService) GetLowStock(locationID *uuid.UUID) ([]LowStockItem, error) {
+	return s.repo.FindLowStock(locationID, time.Now().UTC())
+}
+
+// Create creates a reorder rule. A product has at most one rule per
+// location, and at most one covering every location.
+func (s *ReorderRuleService) Create(req CreateReorderRuleRequest, user *auth.User) (*ReorderRule, error) {
+	if err := validator.Struct(req); err != nil {
+		return nil, err
+	}
+
+	p, err := product.NewProductRepository(s.db).FindByID(req.ProductID)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil, validator.ValidationErrors{{Field: "productId", Message: "product not found"}}
+		}
+		return nil, err
+	}
+	if p.IsKit() {
+		return nil, validator.ValidationErrors{{Field: "productId", Message: "kits are restocked through their components"}}
+	}
+	if err := checkLocation(s.db, req.LocationID); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.repo.FindByProductID(req.ProductID, req.LocationID); err == nil {
+		return nil, ErrReorderRuleExists
+	} else if !errors.IsNotFound(err) {
+		return nil, err
+	}
+
+	rule := &ReorderRule{
+		ProductID:       req.ProductID,
+		LocationID:      req.LocationID,
+		MinimumQuantity: req.MinimumQuantity,
+		ReorderQuantity: req.ReorderQuantity,
+		AuditFields: common.AuditFields{
+			CreatedBy: user.ID,
+			UpdatedBy: user.ID,
+		},
+	}
+	if err := s.repo.Create(rule); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+// Update updates the quantities of a reorder rule.
+func (s *ReorderRuleService) Update(id uuid.UUID, req UpdateReorderRuleRequest, user *auth.User) (*ReorderRule, error) {
+	if err := validator.Struct(req); err != nil {
+		return nil, err
+	}
+
+	rule, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	rule.MinimumQuantity = req.MinimumQuantity
+	rule.ReorderQuantity = req.ReorderQuantity
+	rule.UpdatedBy = user.ID
+
+	if err := s.repo.Update(rule); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+// Delete deletes a reorder rule. Any open alert for it is resolved by the
+// next low-stock check.
+func (s *ReorderRuleService) Delete(id uuid.UUID) error {
+	return s.repo.Delete(id)
+}
+
+// AlertService handles business logic for stock alerts.
+type AlertService struct {
+	repo *AlertRepository
+}
+
+// NewAlertService creates a new stock alert service.
+func NewAlertService(repo *AlertRepository) *AlertService {
+	return &AlertService{repo: repo}
+}
+
+// GetAll retrieves stock alerts, optionally only those with a status.
+func (s *AlertService) GetAll(status AlertStatus) ([]StockAlert, error) {
+	return s.repo.FindAll(status)
+}
+
+// GetByID retrieves a stock alert by ID.
+func (s *AlertService) GetByID(id uuid.UUID) (*StockAlert, error) {
+	return s.repo.FindByID(id)
+}
+
+// ExpiryService handles business logic for expiring and expired stock.
+type ExpiryService struct {
+	db           *db.DB
+	repo         *BatchRepository
+	inventoryCfg config.InventoryConfig
+}
+
+// NewExpiryService creates a new expiry service.
+func NewExpiryService(database *db.DB, repo *BatchRepository, inventoryCfg config.InventoryConfig) *ExpiryService {
+	return &ExpiryService{db: database, repo: repo, inventoryCfg: inventoryCfg}
+}
+
+// GetExpiring retrieves the batches with stock available that expire within
+// the given duration, or only those held at a location when one is given.
+func (s *ExpiryService) GetExpiring(within time.Duration, locationID *uuid.UUID) ([]ExpiringBatch, error) {
+	now := time.Now().UTC()
+	batches, err := s.repo.FindExpiring(now, now.Add(within), locationID)
+	if err != nil {
+		return nil, err
+	}
+	s.markdown(batches, now)
+	return batches, nil
+}
+
+// GetExpired retrieves the expired batches that still hold stock, or only
+// those held at a location when one is given.
+func (s *ExpiryService) GetExpired(locationID *uuid.UUID) ([]ExpiringBatch, error) {
+	now := time.Now().UTC()
+	batches, err := s.repo.FindExpired(now, locationID)
+	if err != nil {
+		return nil, err
+	}
+	s.markdown(batches, now)
+	return batches, nil
+}
+
+// Quarantine moves the available stock of expired batches into quarantine,
+// ready to be written off, and returns the batches it moved stock from.
+func (s *ExpiryService) Quarantine(req ExpiredStockRequest, user *auth.User) ([]ExpiringBatch, error) {
+	if err := validator.Struct(req); err != nil {
+		return nil, err
+	}
+	if err := checkLocation(s.db, req.LocationID); err != nil {
+		return nil, err
+	}
+
+	quarantined := []ExpiringBatch{}
+	var total quantity.Quantity
+	err := s.db.Transaction(func(tx *db.DB) error {
+		batches := NewBatchRepository(tx)
+		expired, err := batches.FindExpired(time.Now().UTC(), req.LocationID)
+		if err != nil {
+			return err
+		}
+
+		for _, batch := range expired {
+			moved := batch.QuantityAvailable
+			if moved == 0 {
+				continue
+			}
+			for _, bucket := range []StockBucket{BucketAvailable, BucketQuarantine} {
+				movement := &StockMovement{
+					BatchID:   batch.ID,
+					ProductID: batch.ProductID,
+					Type:      MovementQuarantine,
+					Bucket:    bucket,
+					Quantity:  moved,
+					Note:      req.Note,
+					CreatedBy: user.ID,
+				}
+				if bucket == BucketAvailable {
+					movement.Quantity = -moved
+				}
+				if err := batches.ApplyMovement(movement); err != nil {
+					return err
+				}
+			}
+
+			batch.QuantityAvailable = 0
+			batch.QuantityQuarantined += moved
+			quarantined = append(quarantined, batch)
+			total += moved
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("expired stock quarantined", "batches", len(quarantined), "quantity", total,
+		"location_id", req.LocationID, "quarantined_by", user.ID)
+	return quarantined, nil
+}
+
+// WriteOff records a stock adjustment, with reason expired, removing the
+// stock of expired batches that Quarantine moved into quarantine. Stock
+// quarantined for other reasons, such as damaged returns, is left alone.
+// Like any adjustment it is left pending when it is worth more than the
+// approval threshold, and stock a pending write-off covers is not written
+// off again.
+func (s *ExpiryService) WriteOff(req ExpiredStockRequest, user *auth.User) (*StockAdjustment, error) {
+	if err := validator.Struct(req); err != nil {
+		return nil, err
+	}
+	if err := checkLocation(s.db, req.LocationID); err != nil {
+		return nil, err
+	}
+
+	var adjustment *StockAdjustment
+	err := s.db.Transaction(func(tx *db.DB) error {
+		expired, err := NewBatchRepository(tx).FindExpired(time.Now().UTC(), req.LocationID)
+		if err != nil {
+			return err
+		}
+		batchIDs := make([]uuid.UUID, len(expired))
+		for i, batch := range expired {
+			batchIDs[i] = batch.ID
+		}
+		quarantined, err := NewMovementRepository(tx).SumExpiryQuarantine(batchIDs)
+		if err != nil {
+			return err
+		}
+		// Write-offs awaiting approval have not moved any stock yet
+		pending, err := NewAdjustmentRepository(tx).SumPending(batchIDs, ReasonExpired, BucketQuarantine)
+		if err != nil {
+			return err
+		}
+
+		var lines []AdjustmentLineRequest
+		for _, batch := range expired {
+			// Other removals of quarantined stock may have taken some of it
+			qty := min(quarantined[batch.ID], batch.QuantityQuarantined) + pending[batch.ID]
+			if qty > 0 {
+				lines = append(lines, AdjustmentLineRequest{
+					BatchID:  batch.ID,
+					Bucket:   BucketQuarantine,
+					Quantity: -qty,
+				})
+			}
+		}
+		if len(lines) == 0 {
+			return ErrNoExpiredStock
+		}
+
+		adjustments := NewAdjustmentService(tx, NewAdjustmentRepository(tx), s.inventoryCfg)
+		adjustment, err = adjustments.Create(CreateAdjustmentRequest{
+			Reason: ReasonExpired,
+			Note:   req.Note,
+			Lines:  lines,
+		}, user)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return adjustment, nil
+}
+
+// markdown sets the markdown price of each batch in an expiry report.
+func (s *ExpiryService) markdown(batches []ExpiringBatch, at time.Time) {
+	for i := range batches {
+		batches[i].MarkdownPrice = MarkdownPrice(batches[i].SellingPrice, batches[i].ExpiresAt, at, s.inventoryCfg)
+	}
+}
+
+// MarkdownPrice returns the price a batch expiring at expiresAt sells at at
+// the given time: the price less the expiry markdown percentage once the
+// batch is within the markdown window, and the price unchanged otherwise.
+func MarkdownPrice(price money.Amount, expiresAt *time.Time, at time.Time, inventoryCfg config.InventoryConfig) money.Amount {
+	if inventoryCfg.ExpiryMarkdownDays == 0 || inventoryCfg.ExpiryMarkdownPercent == 0 || expiresAt == nil {
+		return price
+	}
+	window := time.Duration(inventoryCfg.ExpiryMarkdownDays) * 24 * time.Hour
+	if expiresAt.Sub(at) > window {
+		return price
+	}
+	return price.MulRat(int64(100-inventoryCfg.ExpiryMarkdownPercent), 100)
+}
+
+// checkLocation ensures a location exists when one is given.
+func checkLocation(database *db.DB, locationID *uuid.UUID) error {
+	if locationID == nil {
+		return nil
+	}
+	if _, err := location.NewLocationRepository(database).FindByID(*locationID); err != nil {
+		if errors.IsNotFound(err) {
+			return validator.ValidationErrors{{Field: "locationId", Message: "location not found"}}
+		}
+		return err
+	}
+	return nil
+}
+
+// applyAdjustment records a movement for each line of an adjustment, with
+// the line quantities multiplied by sign: 1 to apply it, -1 to reverse it.
+func applyAdjustment(batches *BatchRepository, adjustment *StockAdjustment, sign int, user *auth.User) error {
+	note := string(adjustment.Reason)
+	if sign < 0 {
+		note = "reversal: " + note
+	}
+
+	for _, line := range adjustment.Lines {
+		movement := &StockMovement{
+			BatchID:       line.BatchID,
+			ProductID:     line.ProductID,
+			Type:          MovementAdjustment,
+			Bucket:        line.Bucket,
+			Quantity:      quantity.Quantity(sign) * line.Quantity,
+			ReferenceType: "stock_adjustment",
+			ReferenceID:   &adjustment.ID,
+			Note:          note,
+			CreatedBy:     user.ID,
+		}
+		if err := batches.ApplyMovement(movement); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// abs returns the absolute value of a quantity.
+func abs(n quantity.Quantity) quantity.Quantity {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
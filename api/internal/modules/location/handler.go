@@ -0,0 +1,265 @@
+package location
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/db"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/modules/auth"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/errors"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/response"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/validator"
+)
+
+// Handler handles HTTP requests for locations.
+type Handler struct {
+	service *LocationService
+}
+
+// NewHandler creates a new location handler.
+func NewHandler(database *db.DB) *Handler {
+	repo := NewLocationRepository(database)
+	service := NewLocationService(database, repo)
+	return &Handler{service: service}
+}
+
+// Routes returns the location routes.
+func (h *Handler) Routes() chi.Router {
+	r := chi.NewRouter()
+	r.Get("/", h.GetAll)
+	r.Post("/", h.Create)
+	r.Get("/{id}", h.GetByID)
+	r.Put("/{id}", h.Update)
+	r.Delete("/{id}", h.Delete)
+	r.Get("/{id}/users", h.GetUsers)
+	r.Put("/{id}/users/{userId}", h.AssignUser)
+	r.Delete("/{id}/users/{userId}", h.UnassignUser)
+	return r
+}
+
+// GetAll handles retrieving all locations.
+func (h *Handler) GetAll(w http.ResponseWriter, r *http.Request) {
+	locations, err := h.service.GetAll()
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to retrieve locations")
+		return
+	}
+
+	response.Success(w, locations)
+}
+
+// GetByID handles retrieving a location by ID.
+func (h *Handler) GetByID(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid location ID")
+		return
+	}
+
+	location, err := h.service.GetByID(id)
+	if err != nil {
+		response.Error(w, http.StatusNotFound, "location not found")
+		return
+	}
+
+	response.Success(w, location)
+}
+
+// GetUsers handles retrieving the users assigned to a location.
+func (h *Handler) GetUsers(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid location ID")
+		return
+	}
+
+	users, err := h.service.GetUsers(id)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			response.Error(w, http.StatusNotFound, "location not found")
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, "failed to retrieve location users")
+		return
+	}
+
+	response.Success(w, users)
+}
+
+// Create handles creating a new location.
+func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
+	var req CreateLocationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	user, err := auth.GetUserFromContext(r.Context())
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to retrieve user")
+		return
+	}
+
+	location, err := h.service.Create(req, user)
+	if err != nil {
+		if validator.IsValidationError(err) {
+			response.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if errors.IsConflict(err) {
+			response.Error(w, http.StatusConflict, err.Error())
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, "failed to create location")
+		return
+	}
+
+	response.Created(w, location)
+}
+
+// Update handles updating a location.
+func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid location ID")
+		return
+	}
+
+	var req UpdateLocationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	user, err := auth.GetUserFromContext(r.Context())
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to retrieve user")
+		return
+	}
+
+	location, err := h.service.Update(id, req, user)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			response.Error(w, http.StatusNotFound, "location not found")
+			return
+		}
+		if validator.IsValidationError(err) {
+			response.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if errors.IsConflict(err) {
+			response.Error(w, http.StatusConflict, err.Error())
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, "failed to update location")
+		return
+	}
+
+	response.Success(w, location)
+}
+
+// Delete handles deactivating a location.
+func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid location ID")
+		return
+	}
+
+	user, err := auth.GetUserFromContext(r.Context())
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to retrieve user")
+		return
+	}
+
+	if err := h.service.Delete(id, user); err != nil {
+		if errors.IsNotFound(err) {
+			response.Error(w, http.StatusNotFound, "location not found")
+			return
+		}
+		if errors.IsConflict(err) {
+			response.Error(w, http.StatusConflict, err.Error())
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, "failed to delete location")
+		return
+	}
+
+	response.NoContent(w)
+}
+
+// AssignUser handles making a location a user's default location.
+func (h *Handler) AssignUser(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid location ID")
+		return
+	}
+
+	userID, err := uuid.Parse(chi.URLParam(r, "userId"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid user ID")
+		return
+	}
+
+	user, err := auth.GetUserFromContext(r.Context())
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to retrieve user")
+		return
+	}
+
+	if err := h.service.AssignUser(id, userID, user); err != nil {
+		if errors.IsNotFound(err) {
+			response.Error(w, http.StatusNotFound, "location or user not found")
+			return
+		}
+		if errors.IsConflict(err) {
+			response.Error(w, http.StatusConflict, err.Error())
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, "failed to assign user")
+		return
+	}
+
+	response.NoContent(w)
+}
+
+// UnassignUser handles clearing a user's default location.
+func (h *Handler) UnassignUser(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid location ID")
+		return
+	}
+
+	userID, err := uuid.Parse(chi.URLParam(r, "userId"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid user ID")
+		return
+	}
+
+	user, err := auth.GetUserFromContext(r.Context())
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to retrieve user")
+		return
+	}
+
+	if err := h.service.UnassignUser(id, userID, user); err != nil {
+		if errors.IsNotFound(err) {
+			response.Error(w, http.StatusNotFound, "user is not assigned to this location")
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, "failed to unassign user")
+		return
+	}
+
+	response.NoContent(w)
+}
@@ -0,0 +1,44 @@
+package location
+
+import (
+	"github.com/google/uuid"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/common"
+)
+
+// Type is the kind of place a location is.
+type Type string
+
+// Location types.
+const (
+	TypeStore     Type = "store"
+	TypeWarehouse Type = "warehouse"
+)
+
+// Location is a store or warehouse that holds stock. Every batch is held at
+// one location. Exactly one location is the default; stock handled without
+// a location, by a user who has no default location of their own, is held
+// there.
+type Location struct {
+	ID        uuid.UUID `gorm:"type:char(36);primaryKey" json:"id"`
+	Name      string    `gorm:"unique;not null" json:"name"`
+	Type      Type      `gorm:"not null;default:store" json:"type"`
+	Address   string    `json:"address"`
+	IsDefault bool      `gorm:"not null;default:false;index" json:"isDefault"`
+	Active    bool      `gorm:"not null;default:true" json:"active"`
+
+	common.AuditFields
+}
+
+// CreateLocationRequest represents a request to create a location.
+type CreateLocationRequest struct {
+	Name      string `json:"name" validate:"required,min=1,max=255"`
+	Type      Type   `json:"type" validate:"required,oneof=store warehouse"`
+	Address   string `json:"address" validate:"max=500"`
+	IsDefault bool   `json:"isDefault"`
+}
+
+// UpdateLocationRequest represents a request to update a location.
+type UpdateLocationRequest struct {
+	CreateLocationRequest
+	Active bool `json:"active"`
+}
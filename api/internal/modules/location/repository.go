@@ -0,0 +1,128 @@
+package location
+
+import (
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/db"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/errors"
+)
+
+// ErrDefaultLocation is returned when deactivating the default location.
+var ErrDefaultLocation = errors.New(http.StatusConflict, errors.ErrConflict, "the default location cannot be deactivated")
+
+// ErrNoLocation is returned when stock is handled without a location and
+// there is no default location to fall back to.
+var ErrNoLocation = errors.New(http.StatusUnprocessableEntity, errors.ErrUnprocessable, "no location given and no default location is set")
+
+// LocationRepository handles data access for locations.
+type LocationRepository struct {
+	db *db.DB
+}
+
+// NewLocationRepository creates a new location repository.
+func NewLocationRepository(database *db.DB) *LocationRepository {
+	return &LocationRepository{db: database}
+}
+
+// FindAll retrieves all locations.
+func (r *LocationRepository) FindAll() ([]Location, error) {
+	var locations []Location
+	if err := r.db.Order("name ASC").Find(&locations).Error; err != nil {
+		return nil, err
+	}
+	return locations, nil
+}
+
+// FindByID retrieves a location by ID.
+func (r *LocationRepository) FindByID(id uuid.UUID) (*Location, error) {
+	var location Location
+	if err := r.db.First(&location, id).Error; err != nil {
+		return nil, err
+	}
+	return &location, nil
+}
+
+// FindByName retrieves the location with a name.
+func (r *LocationRepository) FindByName(name string) (*Location, error) {
+	var location Location
+	if err := r.db.Where("name = ?", name).First(&location).Error; err != nil {
+		return nil, err
+	}
+	return &location, nil
+}
+
+// FindDefault retrieves the default location.
+func (r *LocationRepository) FindDefault() (*Location, error) {
+	var location Location
+	if err := r.db.Where("is_default = ?", true).First(&location).Error; err != nil {
+		return nil, err
+	}
+	return &location, nil
+}
+
+// Create creates a new location.
+func (r *LocationRepository) Create(location *Location) error {
+	if location.ID == uuid.Nil {
+		location.ID = uuid.New()
+	}
+	return r.db.Create(location).Error
+}
+
+// Update updates an existing location.
+func (r *LocationRepository) Update(location *Location) error {
+	return r.db.Save(location).Error
+}
+
+// ClearDefault takes the default flag from every location but the given one.
+func (r *LocationRepository) ClearDefault(exceptID uuid.UUID, userID uuid.UUID) error {
+	return r.db.Model(&Location{}).Where("is_default = ? AND id <> ?", true, exceptID).
+		Updates(map[string]any{"is_default": false, "updated_by": userID}).Error
+}
+
+// Deactivate stops a location from being used. Locations are kept for the
+// batches and documents that refer to them.
+func (r *LocationRepository) Deactivate(id uuid.UUID, userID uuid.UUID) error {
+	result := r.db.Model(&Location{}).Where("id = ?", id).
+		Updates(map[string]any{"active": false, "updated_by": userID})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.ErrNotFound
+	}
+	return nil
+}
+
+// MigrateDefaultLocation creates the default location the stock held before
+// locations existed is placed in, and places every batch, sale and purchase
+// order without a location there.
+var MigrateDefaultLocation = db.Migration{
+	ID: "20261016_location_default",
+	Up: func(tx *db.DB) error {
+		repo := NewLocationRepository(tx)
+		location, err := repo.FindDefault()
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		if location == nil {
+			location = &Location{
+				Name:      "Main",
+				Type:      TypeStore,
+				IsDefault: true,
+				Active:    true,
+			}
+			if err := repo.Create(location); err != nil {
+				return err
+			}
+		}
+
+		for _, table := range []string{"product_batches", "sales", "purchase_orders"} {
+			if err := tx.Table(table).Where("location_id IS NULL").
+				Update("location_id", location.ID).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+}
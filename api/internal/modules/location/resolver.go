@@ -0,0 +1,58 @@
+package location
+
+import (
+	"github.com/google/uuid"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/db"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/modules/auth"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/errors"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/validator"
+)
+
+// Resolver decides which location stock is handled at.
+type Resolver struct {
+	repo *LocationRepository
+}
+
+// NewResolver creates a new location resolver. Pass a transaction to
+// resolve within it.
+func NewResolver(database *db.DB) *Resolver {
+	return &Resolver{repo: NewLocationRepository(database)}
+}
+
+// Resolve returns the location stock is handled at: the given location,
+// which must exist and be active; otherwise the user's default location
+// when it is active; otherwise the default location.
+func (r *Resolver) Resolve(locationID *uuid.UUID, user *auth.User) (uuid.UUID, error) {
+	if locationID != nil {
+		location, err := r.repo.FindByID(*locationID)
+		if err != nil {
+			if errors.IsNotFound(err) {
+				return uuid.Nil, validator.ValidationErrors{{Field: "locationId", Message: "location not found"}}
+			}
+			return uuid.Nil, err
+		}
+		if !location.Active {
+			return uuid.Nil, validator.ValidationErrors{{Field: "locationId", Message: "location is not active"}}
+		}
+		return location.ID, nil
+	}
+
+	if user != nil && user.DefaultLocationID != nil {
+		location, err := r.repo.FindByID(*user.DefaultLocationID)
+		if err != nil && !errors.IsNotFound(err) {
+			return uuid.Nil, err
+		}
+		if location != nil && location.Active {
+			return location.ID, nil
+		}
+	}
+
+	location, err := r.repo.FindDefault()
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return uuid.Nil, ErrNoLocation
+		}
+		return uuid.Nil, err
+	}
+	return location.ID, nil
+}
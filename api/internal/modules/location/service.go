@@ -0,0 +1,187 @@
+package location
+
+import (
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/common"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/db"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/modules/auth"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/errors"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/logger"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/validator"
+)
+
+// LocationService handles business logic for locations.
+type LocationService struct {
+	db   *db.DB
+	repo *LocationRepository
+}
+
+// NewLocationService creates a new location service.
+func NewLocationService(database *db.DB, repo *LocationRepository) *LocationService {
+	return &LocationService{db: database, repo: repo}
+}
+
+// GetAll retrieves all locations.
+func (s *LocationService) GetAll() ([]Location, error) {
+	return s.repo.FindAll()
+}
+
+// GetByID retrieves a location by ID.
+func (s *LocationService) GetByID(id uuid.UUID) (*Location, error) {
+	return s.repo.FindByID(id)
+}
+
+// GetUsers retrieves the users assigned to a location.
+func (s *LocationService) GetUsers(id uuid.UUID) ([]auth.User, error) {
+	if _, err := s.repo.FindByID(id); err != nil {
+		return nil, err
+	}
+	return auth.NewRepository(s.db).FindByDefaultLocationID(id)
+}
+
+// Create creates a new location. A new default location takes the default
+// over from the current one.
+func (s *LocationService) Create(req CreateLocationRequest, user *auth.User) (*Location, error) {
+	if err := validator.Struct(req); err != nil {
+		return nil, err
+	}
+	if err := s.checkName(req.Name, uuid.Nil); err != nil {
+		return nil, err
+	}
+
+	location := &Location{
+		Name:      req.Name,
+		Type:      req.Type,
+		Address:   req.Address,
+		IsDefault: req.IsDefault,
+		Active:    true,
+		AuditFields: common.AuditFields{
+			CreatedBy: user.ID,
+			UpdatedBy: user.ID,
+		},
+	}
+
+	err := s.db.Transaction(func(tx *db.DB) error {
+		repo := NewLocationRepository(tx)
+		if err := repo.Create(location); err != nil {
+			return err
+		}
+		if location.IsDefault {
+			return repo.ClearDefault(location.ID, user.ID)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("location created", "location_id", location.ID, "type", location.Type, "created_by", user.ID)
+	return location, nil
+}
+
+// Update updates an existing location. The default can be moved to another
+// location but not taken away, and the default location cannot be
+// deactivated.
+func (s *LocationService) Update(id uuid.UUID, req UpdateLocationRequest, user *auth.User) (*Location, error) {
+	if err := validator.Struct(req); err != nil {
+		return nil, err
+	}
+
+	location, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkName(req.Name, location.ID); err != nil {
+		return nil, err
+	}
+	if location.IsDefault && !req.IsDefault {
+		return nil, validator.ValidationErrors{{Field: "isDefault", Message: "make another location the default instead"}}
+	}
+	if req.IsDefault && !req.Active {
+		return nil, ErrDefaultLocation
+	}
+
+	location.Name = req.Name
+	location.Type = req.Type
+	location.Address = req.Address
+	location.IsDefault = req.IsDefault
+	location.Active = req.Active
+	location.UpdatedBy = user.ID
+
+	err = s.db.Transaction(func(tx *db.DB) error {
+		repo := NewLocationRepository(tx)
+		if err := repo.Update(location); err != nil {
+			return err
+		}
+		if location.IsDefault {
+			return repo.ClearDefault(location.ID, user.ID)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return location, nil
+}
+
+// Delete deactivates a location. The default location cannot be
+// deactivated.
+func (s *LocationService) Delete(id uuid.UUID, user *auth.User) error {
+	location, err := s.repo.FindByID(id)
+	if err != nil {
+		return err
+	}
+	if location.IsDefault {
+		return ErrDefaultLocation
+	}
+
+	if err := s.repo.Deactivate(id, user.ID); err != nil {
+		return err
+	}
+
+	logger.Info("location deactivated", "location_id", id, "deleted_by", user.ID)
+	return nil
+}
+
+// AssignUser makes a location a user's default location.
+func (s *LocationService) AssignUser(id, userID uuid.UUID, user *auth.User) error {
+	location, err := s.repo.FindByID(id)
+	if err != nil {
+		return err
+	}
+	if !location.Active {
+		return errors.New(http.StatusConflict, errors.ErrConflict, "location is not active")
+	}
+
+	if err := auth.NewRepository(s.db).AssignDefaultLocation(userID, id); err != nil {
+		return err
+	}
+
+	logger.Info("user assigned to location", "location_id", id, "user_id", userID, "assigned_by", user.ID)
+	return nil
+}
+
+// UnassignUser clears a user's default location.
+func (s *LocationService) UnassignUser(id, userID uuid.UUID, user *auth.User) error {
+	if err := auth.NewRepository(s.db).UnassignDefaultLocation(userID, id); err != nil {
+		return err
+	}
+
+	logger.Info("user unassigned from location", "location_id", id, "user_id", userID, "unassigned_by", user.ID)
+	return nil
+}
+
+// checkName fails with a conflict if another location has the name.
+func (s *LocationService) checkName(name string, id uuid.UUID) error {
+	existing, err := s.repo.FindByName(name)
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	if existing != nil && existing.ID != id {
+		return errors.Newf(http.StatusConflict, errors.ErrConflict, "location %q already exists", name)
+	}
+	return nil
+}
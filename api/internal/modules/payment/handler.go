@@ -0,0 +1,47 @@
+package payment
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/db"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/response"
+)
+
+// Handler handles HTTP requests for payments.
+type Handler struct {
+	service *PaymentService
+}
+
+// NewHandler creates a new payment handler.
+func NewHandler(database *db.DB) *Handler {
+	repo := NewPaymentRepository(database)
+	service := NewPaymentService(database, repo)
+	return &Handler{service: service}
+}
+
+// Routes returns the payment routes, mounted under a sale.
+func (h *Handler) Routes() chi.Router {
+	r := chi.NewRouter()
+	r.Get("/", h.GetBySaleID)
+	return r
+}
+
+// GetBySaleID handles retrieving the payments and refunds of a sale.
+func (h *Handler) GetBySaleID(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid sale ID")
+		return
+	}
+
+	payments, err := h.service.GetBySaleID(id)
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to retrieve payments")
+		return
+	}
+
+	response.Success(w, payments)
+}
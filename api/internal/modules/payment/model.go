@@ -0,0 +1,54 @@
+package payment
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/money"
+)
+
+// Method is the way a tender is paid.
+type Method string
+
+// Payment methods.
+const (
+	MethodCash            Method = "cash"
+	MethodCard            Method = "card"
+	MethodStoreCredit     Method = "store_credit"
+	MethodGiftVoucher     Method = "gift_voucher"
+	MethodCustomerAccount Method = "customer_account"
+)
+
+// Payment records a tender applied to a sale, or a refund paid out against
+// a sale return. Amount is what counts towards the sale (negative for
+// refunds); for cash, Tendered is what was handed over and Change what was
+// given back.
+type Payment struct {
+	ID         uuid.UUID    `gorm:"type:char(36);primaryKey" json:"id"`
+	SaleID     uuid.UUID    `gorm:"type:char(36);index;not null" json:"saleId"`
+	ReturnID   *uuid.UUID   `gorm:"type:char(36);index" json:"returnId"`
+	ShiftID    *uuid.UUID   `gorm:"type:char(36);index" json:"shiftId"`
+	CustomerID *uuid.UUID   `gorm:"type:char(36);index" json:"customerId"`
+	Method     Method       `gorm:"not null" json:"method"`
+	Amount     money.Amount `gorm:"not null" json:"amount"`
+	Tendered   money.Amount `gorm:"not null;default:0" json:"tendered"`
+	Change     money.Amount `gorm:"not null;default:0" json:"change"`
+	Reference  string       `json:"reference"`
+	CreatedAt  time.Time    `json:"createdAt"`
+	CreatedBy  uuid.UUID    `gorm:"type:char(36)" json:"createdBy"`
+}
+
+// MethodTotal is the net amount taken through one payment method.
+type MethodTotal struct {
+	Method Method       `json:"method"`
+	Amount money.Amount `json:"amount"`
+}
+
+// TenderRequest represents a single tender offered for a sale.
+// Card and gift voucher tenders carry the approval code or voucher number
+// in Reference.
+type TenderRequest struct {
+	Method    Method       `json:"method" validate:"required,oneof=cash card store_credit gift_voucher customer_account"`
+	Amount    money.Amount `json:"amount" validate:"required,gt=0"`
+	Reference string       `json:"reference" validate:"required_if=Method gift_voucher,max=255"`
+}
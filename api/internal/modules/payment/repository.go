@@ -0,0 +1,72 @@
+package payment
+
+import (
+	"github.com/google/uuid"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/db"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/money"
+)
+
+// PaymentRepository handles data access for payments.
+type PaymentRepository struct {
+	db *db.DB
+}
+
+// NewPaymentRepository creates a new payment repository.
+func NewPaymentRepository(database *db.DB) *PaymentRepository {
+	return &PaymentRepository{db: database}
+}
+
+// FindBySaleID retrieves the payments and refunds of a sale in the order
+// they were recorded.
+func (r *PaymentRepository) FindBySaleID(saleID uuid.UUID) ([]Payment, error) {
+	var payments []Payment
+	if err := r.db.Where("sale_id = ?", saleID).Order("created_at ASC").Find(&payments).Error; err != nil {
+		return nil, err
+	}
+	return payments, nil
+}
+
+// TotalsByShiftID sums the payments and refunds taken in a shift per method.
+func (r *PaymentRepository) TotalsByShiftID(shiftID uuid.UUID) ([]MethodTotal, error) {
+	var totals []MethodTotal
+	err := r.db.Model(&Payment{}).
+		Select("method, SUM(amount) AS amount").
+		Where("shift_id = ?", shiftID).
+		Group("method").
+		Order("method").
+		Scan(&totals).Error
+	if err != nil {
+		return nil, err
+	}
+	return totals, nil
+}
+
+// CountByShiftID counts the sales paid and the returns refunded in a shift.
+func (r *PaymentRepository) CountByShiftID(shiftID uuid.UUID) (sales, returns int, err error) {
+	var counts struct {
+		Sales   int
+		Returns int
+	}
+	err = r.db.Model(&Payment{}).
+		Select("COUNT(DISTINCT CASE WHEN return_id IS NULL THEN sale_id END) AS sales, COUNT(DISTINCT return_id) AS returns").
+		Where("shift_id = ?", shiftID).
+		Scan(&counts).Error
+	return counts.Sales, counts.Returns, err
+}
+
+// Create records a payment.
+func (r *PaymentRepository) Create(payment *Payment) error {
+	if payment.ID == uuid.Nil {
+		payment.ID = uuid.New()
+	}
+	return r.db.Create(payment).Error
+}
+
+// MigrateMoneyToMinorUnits converts payment amounts stored as decimals to
+// exact minor units.
+var MigrateMoneyToMinorUnits = db.Migration{
+	ID: "20261016_payment_money_minor_units",
+	Up: func(tx *db.DB) error {
+		return money.MigrateColumns(tx.DB, "payments", "amount", "tendered", "change")
+	},
+}
@@ -0,0 +1,253 @@
+package payment
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/db"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/modules/auth"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/modules/customer"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/errors"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/money"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/validator"
+)
+
+// ErrNoOpenShift is returned when cash is taken or paid out without an open
+// shift to account for it.
+var ErrNoOpenShift = errors.New(http.StatusConflict, errors.ErrConflict, "cash requires an open shift")
+
+// PaymentService handles business logic for payments. It works against the
+// database it is created with, so callers settling a sale inside a
+// transaction create it from the transaction.
+type PaymentService struct {
+	db   *db.DB
+	repo *PaymentRepository
+}
+
+// NewPaymentService creates a new payment service.
+func NewPaymentService(database *db.DB, repo *PaymentRepository) *PaymentService {
+	return &PaymentService{db: database, repo: repo}
+}
+
+// GetBySaleID retrieves the payments and refunds of a sale.
+func (s *PaymentService) GetBySaleID(saleID uuid.UUID) ([]Payment, error) {
+	return s.repo.FindBySaleID(saleID)
+}
+
+// Settlement is the outcome of paying an amount due.
+type Settlement struct {
+	Payments []Payment    `json:"payments"`
+	Change   money.Amount `json:"change"`
+}
+
+// Settle records the tenders paying amount due on a sale. The tenders must
+// cover the amount due, and only cash may exceed it: the excess is returned
+// as change. Customer account and store credit tenders are posted to the
+// customer's ledger. Payments are tied to shiftID, which is required for
+// cash. The tenders are expected to be validated by the caller.
+func (s *PaymentService) Settle(saleID uuid.UUID, customerID, shiftID *uuid.UUID, due money.Amount, tenders []TenderRequest, user *auth.User) (*Settlement, error) {
+	var cash, other money.Amount
+	for _, t := range tenders {
+		if t.Method == MethodCash {
+			cash += t.Amount
+		} else {
+			other += t.Amount
+		}
+	}
+
+	if cash > 0 && shiftID == nil {
+		return nil, ErrNoOpenShift
+	}
+
+	if other > due {
+		return nil, errors.Newf(http.StatusUnprocessableEntity, errors.ErrUnprocessable,
+			"non-cash tenders of %s exceed the amount due of %s", other, due)
+	}
+	if cash+other < due {
+		return nil, errors.Newf(http.StatusUnprocessableEntity, errors.ErrUnprocessable,
+			"tenders of %s do not cover the amount due of %s", cash+other, due)
+	}
+
+	settlement := &Settlement{Change: cash + other - due}
+	cashDue := due - other
+
+	err := s.db.Transaction(func(tx *db.DB) error {
+		customers := customer.NewCustomerRepository(tx)
+		ledger := customer.NewLedgerRepository(tx)
+		payments := NewPaymentRepository(tx)
+
+		for i, t := range tenders {
+			p := Payment{
+				SaleID:     saleID,
+				CustomerID: customerID,
+				ShiftID:    shiftID,
+				Method:     t.Method,
+				Amount:     t.Amount,
+				Tendered:   t.Amount,
+				Reference:  t.Reference,
+				CreatedBy:  user.ID,
+			}
+
+			switch t.Method {
+			case MethodCash:
+				p.Amount = min(p.Tendered, cashDue)
+				p.Change = p.Tendered - p.Amount
+				cashDue -= p.Amount
+			case MethodCustomerAccount, MethodStoreCredit:
+				if err := chargeCustomer(customers, ledger, i, &p); err != nil {
+					return err
+				}
+			}
+
+			if err := payments.Create(&p); err != nil {
+				return err
+			}
+			settlement.Payments = append(settlement.Payments, p)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return settlement, nil
+}
+
+// chargeCustomer posts a customer account or store credit tender to the
+// customer's ledger. Account tenders are only accepted from active
+// customers; store credit is limited to the credit the customer holds.
+func chargeCustomer(customers *customer.CustomerRepository, ledger *customer.LedgerRepository, index int, p *Payment) error {
+	if p.CustomerID == nil {
+		return tenderError(index, "method", fmt.Sprintf("%s requires a sale with a customer", p.Method))
+	}
+
+	c, err := customers.FindByID(*p.CustomerID)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return tenderError(index, "method", "customer not found")
+		}
+		return err
+	}
+
+	kind := customer.KindCreditSale
+	if p.Method == MethodStoreCredit {
+		kind = customer.KindCreditRedemption
+		if credit := -c.Balance; p.Amount > credit {
+			return errors.Newf(http.StatusUnprocessableEntity, errors.ErrUnprocessable,
+				"tenders[%d]: store credit of %s is less than %s", index, max(credit, 0), p.Amount)
+		}
+	} else if !c.Active {
+		return tenderError(index, "method", "customer account tender requires an active customer")
+	}
+
+	return ledger.Post(&customer.LedgerEntry{
+		CustomerID:    c.ID,
+		Type:          customer.EntryDebit,
+		Kind:          kind,
+		Amount:        p.Amount,
+		ReferenceType: "sale",
+		ReferenceID:   &p.SaleID,
+		CreatedBy:     p.CreatedBy,
+	})
+}
+
+// Refund pays amount back against the tenders of a sale, most recent tender
+// first, recording each leg as a negative payment linked to the return and
+// to shiftID. Customer account and store credit legs are credited to the
+// customer's ledger; the rest are paid out through the original method, and
+// cash legs require an open shift.
+func (s *PaymentService) Refund(saleID, returnID uuid.UUID, shiftID *uuid.UUID, amount money.Amount, user *auth.User) ([]Payment, error) {
+	var refunds []Payment
+
+	err := s.db.Transaction(func(tx *db.DB) error {
+		payments := NewPaymentRepository(tx)
+		ledger := customer.NewLedgerRepository(tx)
+
+		existing, err := payments.FindBySaleID(saleID)
+		if err != nil {
+			return err
+		}
+
+		// What is left to refund per method and reference.
+		type tender struct {
+			method    Method
+			reference string
+		}
+		refundable := make(map[tender]money.Amount)
+		var order []Payment
+		for _, p := range existing {
+			key := tender{p.Method, p.Reference}
+			refundable[key] += p.Amount
+			if p.Amount > 0 {
+				order = append(order, p)
+			}
+		}
+
+		remaining := amount
+		for i := len(order) - 1; i >= 0 && remaining > 0; i-- {
+			p := order[i]
+			key := tender{p.Method, p.Reference}
+			leg := min(p.Amount, refundable[key], remaining)
+			if leg <= 0 {
+				continue
+			}
+			refundable[key] -= leg
+			remaining -= leg
+
+			if p.Method == MethodCash && shiftID == nil {
+				return ErrNoOpenShift
+			}
+
+			refund := Payment{
+				SaleID:     saleID,
+				ReturnID:   &returnID,
+				ShiftID:    shiftID,
+				CustomerID: p.CustomerID,
+				Method:     p.Method,
+				Amount:     -leg,
+				Reference:  p.Reference,
+				CreatedBy:  user.ID,
+			}
+
+			if p.Method == MethodCustomerAccount || p.Method == MethodStoreCredit {
+				entry := &customer.LedgerEntry{
+					CustomerID:    *p.CustomerID,
+					Type:          customer.EntryCredit,
+					Kind:          customer.KindRefund,
+					Amount:        leg,
+					ReferenceType: "sale_return",
+					ReferenceID:   &returnID,
+					CreatedBy:     user.ID,
+				}
+				if err := ledger.Post(entry); err != nil {
+					return err
+				}
+			}
+
+			if err := payments.Create(&refund); err != nil {
+				return err
+			}
+			refunds = append(refunds, refund)
+		}
+
+		if remaining > 0 {
+			return errors.Newf(http.StatusConflict, errors.ErrConflict,
+				"refund of %s exceeds the %s left to refund on the sale", amount, amount-remaining)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return refunds, nil
+}
+
+// tenderError builds a validation error for a field of a tender.
+func tenderError(index int, field, message string) error {
+	return validator.ValidationErrors{{
+		Field:   fmt.Sprintf("tenders[%d].%s", index, field),
+		Message: message,
+	}}
+}
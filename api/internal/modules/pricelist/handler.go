@@ -0,0 +1,313 @@
+package pricelist
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/db"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/modules/auth"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/errors"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/response"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/validator"
+)
+
+// Handler handles HTTP requests for price lists.
+type Handler struct {
+	service *PriceListService
+}
+
+// NewHandler creates a new price list handler.
+func NewHandler(database *db.DB) *Handler {
+	repo := NewPriceListRepository(database)
+	service := NewPriceListService(database, repo)
+	return &Handler{service: service}
+}
+
+// Routes returns the price list routes.
+func (h *Handler) Routes() chi.Router {
+	r := chi.NewRouter()
+	r.Get("/", h.GetAll)
+	r.Post("/", h.Create)
+	r.Get("/resolve", h.Resolve)
+	r.Get("/{id}", h.GetByID)
+	r.Put("/{id}", h.Update)
+	r.Post("/{id}/prices", h.AddPrice)
+	r.Get("/{id}/customers", h.GetCustomers)
+	r.Put("/{id}/customers/{customerId}", h.AssignCustomer)
+	r.Delete("/{id}/customers/{customerId}", h.UnassignCustomer)
+	return r
+}
+
+// GetAll handles retrieving all price lists.
+func (h *Handler) GetAll(w http.ResponseWriter, r *http.Request) {
+	lists, err := h.service.GetAll()
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to retrieve price lists")
+		return
+	}
+
+	response.Success(w, lists)
+}
+
+// GetByID handles retrieving a price list by ID.
+func (h *Handler) GetByID(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid price list ID")
+		return
+	}
+
+	list, err := h.service.GetByID(id)
+	if err != nil {
+		response.Error(w, http.StatusNotFound, "price list not found")
+		return
+	}
+
+	response.Success(w, list)
+}
+
+// GetCustomers handles retrieving the customers assigned to a price list.
+func (h *Handler) GetCustomers(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid price list ID")
+		return
+	}
+
+	customers, err := h.service.GetCustomers(id)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			response.Error(w, http.StatusNotFound, "price list not found")
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, "failed to retrieve customers")
+		return
+	}
+
+	response.Success(w, customers)
+}
+
+// Create handles creating a new price list.
+func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
+	var req CreatePriceListRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	user, err := auth.GetUserFromContext(r.Context())
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to retrieve user")
+		return
+	}
+
+	list, err := h.service.Create(req, user)
+	if err != nil {
+		if validator.IsValidationError(err) {
+			response.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, "failed to create price list")
+		return
+	}
+
+	response.Created(w, list)
+}
+
+// Update handles updating a price list.
+func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid price list ID")
+		return
+	}
+
+	var req UpdatePriceListRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	user, err := auth.GetUserFromContext(r.Context())
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to retrieve user")
+		return
+	}
+
+	list, err := h.service.Update(id, req, user)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			response.Error(w, http.StatusNotFound, "price list not found")
+			return
+		}
+		if validator.IsValidationError(err) {
+			response.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, "failed to update price list")
+		return
+	}
+
+	response.Success(w, list)
+}
+
+// AddPrice handles pricing a product on a price list.
+func (h *Handler) AddPrice(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid price list ID")
+		return
+	}
+
+	var req CreatePriceRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	user, err := auth.GetUserFromContext(r.Context())
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to retrieve user")
+		return
+	}
+
+	price, err := h.service.AddPrice(id, req, user)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			response.Error(w, http.StatusNotFound, "price list not found")
+			return
+		}
+		if validator.IsValidationError(err) {
+			response.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if errors.IsConflict(err) {
+			response.Error(w, http.StatusConflict, err.Error())
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, "failed to add price")
+		return
+	}
+
+	response.Created(w, price)
+}
+
+// AssignCustomer handles assigning a customer to a price list.
+func (h *Handler) AssignCustomer(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid price list ID")
+		return
+	}
+	customerID, err := uuid.Parse(chi.URLParam(r, "customerId"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid customer ID")
+		return
+	}
+
+	user, err := auth.GetUserFromContext(r.Context())
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to retrieve user")
+		return
+	}
+
+	if err := h.service.AssignCustomer(id, customerID, user); err != nil {
+		if errors.IsNotFound(err) {
+			response.Error(w, http.StatusNotFound, "price list not found")
+			return
+		}
+		if validator.IsValidationError(err) {
+			response.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, "failed to assign customer")
+		return
+	}
+
+	response.NoContent(w)
+}
+
+// UnassignCustomer handles taking a customer off a price list.
+func (h *Handler) UnassignCustomer(w http.ResponseWriter, r *http.Request) {
+	id, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid price list ID")
+		return
+	}
+	customerID, err := uuid.Parse(chi.URLParam(r, "customerId"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid customer ID")
+		return
+	}
+
+	user, err := auth.GetUserFromContext(r.Context())
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to retrieve user")
+		return
+	}
+
+	if err := h.service.UnassignCustomer(id, customerID, user); err != nil {
+		if errors.IsNotFound(err) {
+			response.Error(w, http.StatusNotFound, "customer is not on this price list")
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, "failed to unassign customer")
+		return
+	}
+
+	response.NoContent(w)
+}
+
+// Resolve handles deciding the unit price of a product for a customer.
+// The customer and time are optional and default to a walk-in sale now.
+func (h *Handler) Resolve(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	productID, err := uuid.Parse(query.Get("productId"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid product ID")
+		return
+	}
+
+	var customerID *uuid.UUID
+	if value := query.Get("customerId"); value != "" {
+		id, err := uuid.Parse(value)
+		if err != nil {
+			response.Error(w, http.StatusBadRequest, "invalid customer ID")
+			return
+		}
+		customerID = &id
+	}
+
+	at := time.Now()
+	if value := query.Get("at"); value != "" {
+		at, err = time.Parse(time.RFC3339, value)
+		if err != nil {
+			response.Error(w, http.StatusBadRequest, "invalid time, expected RFC 3339")
+			return
+		}
+	}
+
+	resolution, err := h.service.Resolve(productID, customerID, at)
+	if err != nil {
+		if validator.IsValidationError(err) {
+			response.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if errors.IsUnprocessable(err) {
+			response.Error(w, http.StatusUnprocessableEntity, err.Error())
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, "failed to resolve price")
+		return
+	}
+
+	response.Success(w, resolution)
+}
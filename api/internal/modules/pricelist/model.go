@@ -0,0 +1,100 @@
+package pricelist
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/common"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/money"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/period"
+)
+
+// PriceList is a named set of product prices, such as "Retail",
+// "Wholesale" or "Staff". Customers assigned to a list buy at its prices;
+// the default list prices sales to everyone else. Products a list does not
+// price sell at their batch's selling price.
+type PriceList struct {
+	ID          uuid.UUID `gorm:"type:char(36);primaryKey" json:"id"`
+	Name        string    `gorm:"unique;not null" json:"name"`
+	Description string    `json:"description"`
+	IsDefault   bool      `gorm:"not null;default:false;index" json:"isDefault"`
+	Active      bool      `gorm:"not null;default:true" json:"active"`
+
+	Prices []Price `gorm:"foreignKey:PriceListID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"prices"`
+
+	common.AuditFields
+}
+
+// Price is a product's price on a price list between two dates.
+// EffectiveTo is exclusive, and nil for a price still in effect.
+type Price struct {
+	ID            uuid.UUID    `gorm:"type:char(36);primaryKey" json:"id"`
+	PriceListID   uuid.UUID    `gorm:"type:char(36);index;not null" json:"priceListId"`
+	ProductID     uuid.UUID    `gorm:"type:char(36);index;not null" json:"productId"`
+	Price         money.Amount `gorm:"not null" json:"price"`
+	EffectiveFrom time.Time    `gorm:"not null;index" json:"effectiveFrom"`
+	EffectiveTo   *time.Time   `gorm:"index" json:"effectiveTo"`
+
+	common.AuditFields
+}
+
+// TableName specifies the table name for the Price model.
+func (Price) TableName() string {
+	return "price_list_prices"
+}
+
+// Period returns the period the price is in effect for.
+func (p Price) Period() period.Period {
+	return period.Period{From: p.EffectiveFrom, To: p.EffectiveTo}
+}
+
+// CreatePriceListRequest represents a request to create a price list.
+// Making a list the default takes the flag from the previous default.
+type CreatePriceListRequest struct {
+	Name        string `json:"name" validate:"required,min=1,max=255"`
+	Description string `json:"description" validate:"max=1000"`
+	IsDefault   bool   `json:"isDefault"`
+}
+
+// UpdatePriceListRequest represents a request to update a price list.
+type UpdatePriceListRequest struct {
+	Name        string `json:"name" validate:"required,min=1,max=255"`
+	Description string `json:"description" validate:"max=1000"`
+	IsDefault   bool   `json:"isDefault"`
+	Active      bool   `json:"active"`
+}
+
+// CreatePriceRequest represents a request to price a product on a list.
+// A price starting while an open-ended price of the product is in effect
+// supersedes it from EffectiveFrom.
+type CreatePriceRequest struct {
+	ProductID     uuid.UUID    `json:"productId" validate:"required"`
+	Price         money.Amount `json:"price" validate:"gte=0"`
+	EffectiveFrom time.Time    `json:"effectiveFrom" validate:"required"`
+	EffectiveTo   *time.Time   `json:"effectiveTo"`
+}
+
+// Source says where a resolved price came from.
+type Source string
+
+// Price sources, in the order they are tried.
+const (
+	SourceCustomerList Source = "customer_price_list"
+	SourceDefaultList  Source = "default_price_list"
+	SourceBatch        Source = "batch"
+	SourceComponents   Source = "components"
+)
+
+// Resolution is the unit price a product sells at for a customer at a
+// time, and where it came from. PriceListID is set for list prices and
+// BatchID for batch prices. A kit without a list price sells at what its
+// components do.
+type Resolution struct {
+	ProductID   uuid.UUID    `json:"productId"`
+	CustomerID  *uuid.UUID   `json:"customerId"`
+	At          time.Time    `json:"at"`
+	Price       money.Amount `json:"price"`
+	Source      Source       `json:"source"`
+	PriceListID *uuid.UUID   `json:"priceListId"`
+	BatchID     *uuid.UUID   `json:"batchId"`
+}
@@ -0,0 +1,119 @@
+package pricelist
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/db"
+	"gorm.io/gorm"
+)
+
+// PriceListRepository handles data access for price lists.
+type PriceListRepository struct {
+	db *db.DB
+}
+
+// NewPriceListRepository creates a new price list repository.
+func NewPriceListRepository(database *db.DB) *PriceListRepository {
+	return &PriceListRepository{db: database}
+}
+
+// FindAll retrieves all price lists with their prices.
+func (r *PriceListRepository) FindAll() ([]PriceList, error) {
+	var lists []PriceList
+	if err := r.db.Preload("Prices", orderByEffectiveFrom).Order("name ASC").Find(&lists).Error; err != nil {
+		return nil, err
+	}
+	return lists, nil
+}
+
+// FindByID retrieves a price list with its prices.
+func (r *PriceListRepository) FindByID(id uuid.UUID) (*PriceList, error) {
+	var list PriceList
+	if err := r.db.Preload("Prices", orderByEffectiveFrom).First(&list, id).Error; err != nil {
+		return nil, err
+	}
+	return &list, nil
+}
+
+// FindDefault retrieves the active default price list, without its prices.
+func (r *PriceListRepository) FindDefault() (*PriceList, error) {
+	var list PriceList
+	if err := r.db.Where("is_default = ? AND active = ?", true, true).First(&list).Error; err != nil {
+		return nil, err
+	}
+	return &list, nil
+}
+
+// Create creates a new price list.
+func (r *PriceListRepository) Create(list *PriceList) error {
+	if list.ID == uuid.Nil {
+		list.ID = uuid.New()
+	}
+	return r.db.Omit("Prices").Create(list).Error
+}
+
+// Update updates an existing price list. Its prices are left untouched.
+func (r *PriceListRepository) Update(list *PriceList) error {
+	return r.db.Omit("Prices").Save(list).Error
+}
+
+// ClearDefault takes the default flag from every list but the given one.
+func (r *PriceListRepository) ClearDefault(exceptID uuid.UUID, userID uuid.UUID) error {
+	return r.db.Model(&PriceList{}).Where("is_default = ? AND id <> ?", true, exceptID).
+		Updates(map[string]any{"is_default": false, "updated_by": userID}).Error
+}
+
+// PriceRepository handles data access for price list prices.
+type PriceRepository struct {
+	db *db.DB
+}
+
+// NewPriceRepository creates a new price repository.
+func NewPriceRepository(database *db.DB) *PriceRepository {
+	return &PriceRepository{db: database}
+}
+
+// FindByListIDAndProductID retrieves every period of a product's price on
+// a list.
+func (r *PriceRepository) FindByListIDAndProductID(listID, productID uuid.UUID) ([]Price, error) {
+	var prices []Price
+	if err := r.db.Where("price_list_id = ? AND product_id = ?", listID, productID).
+		Order("effective_from ASC").Find(&prices).Error; err != nil {
+		return nil, err
+	}
+	return prices, nil
+}
+
+// FindEffective retrieves a product's price on a list in effect at t. Prices
+// on inactive lists are never in effect.
+func (r *PriceRepository) FindEffective(listID, productID uuid.UUID, at time.Time) (*Price, error) {
+	var price Price
+	if err := r.db.Joins("JOIN price_lists ON price_lists.id = price_list_prices.price_list_id").
+		Where("price_lists.active = ?", true).
+		Where("price_list_prices.price_list_id = ? AND price_list_prices.product_id = ?", listID, productID).
+		Where("price_list_prices.effective_from <= ? AND (price_list_prices.effective_to IS NULL OR price_list_prices.effective_to > ?)", at, at).
+		First(&price).Error; err != nil {
+		return nil, err
+	}
+	return &price, nil
+}
+
+// Create creates a new price.
+func (r *PriceRepository) Create(price *Price) error {
+	if price.ID == uuid.Nil {
+		price.ID = uuid.New()
+	}
+	return r.db.Create(price).Error
+}
+
+// End sets the end of an open-ended price's period.
+func (r *PriceRepository) End(id uuid.UUID, at time.Time, userID uuid.UUID) error {
+	return r.db.Model(&Price{}).Where("id = ? AND effective_to IS NULL", id).
+		Updates(map[string]any{"effective_to": at, "updated_by": userID}).Error
+}
+
+// orderByEffectiveFrom orders preloaded prices oldest first.
+func orderByEffectiveFrom(tx *gorm.DB) *gorm.DB {
+	return tx.Order("effective_from ASC")
+}
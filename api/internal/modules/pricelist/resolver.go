@@ -0,0 +1,140 @@
+package pricelist
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/db"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/modules/customer"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/modules/inventory"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/modules/product"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/errors"
+)
+
+// ErrNoPrice is returned when a product has no list price and no stock to
+// take a selling price from.
+var ErrNoPrice = errors.New(http.StatusUnprocessableEntity, errors.ErrUnprocessable, "product has no price")
+
+// Resolver decides the unit price a product sells at.
+type Resolver struct {
+	db     *db.DB
+	lists  *PriceListRepository
+	prices *PriceRepository
+}
+
+// NewResolver creates a new price resolver.
+func NewResolver(database *db.DB) *Resolver {
+	return &Resolver{
+		db:     database,
+		lists:  NewPriceListRepository(database),
+		prices: NewPriceRepository(database),
+	}
+}
+
+// ListPrice returns the list price of a product for a customer at t: the
+// price on the customer's price list if it prices the product, otherwise
+// the price on the default list. Inactive lists are skipped. It returns nil
+// when neither list prices the product. customerID is nil for walk-in sales.
+// Only the price and its source are set on the result.
+func (r *Resolver) ListPrice(productID uuid.UUID, customerID *uuid.UUID, at time.Time) (*Resolution, error) {
+	if customerID != nil {
+		c, err := customer.NewCustomerRepository(r.db).FindByID(*customerID)
+		if err != nil {
+			return nil, err
+		}
+		if c.PriceListID != nil {
+			resolution, err := r.fromList(*c.PriceListID, productID, at, SourceCustomerList)
+			if err != nil || resolution != nil {
+				return resolution, err
+			}
+		}
+	}
+
+	list, err := r.lists.FindDefault()
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return r.fromList(list.ID, productID, at, SourceDefaultList)
+}
+
+// Resolve returns the unit price of a product for a customer at t. Without
+// a list price it is the selling price of the batch a sale would take stock
+// from first, and ErrNoPrice when there is none; for a kit it is the price
+// of the components one kit takes.
+func (r *Resolver) Resolve(productID uuid.UUID, customerID *uuid.UUID, at time.Time) (*Resolution, error) {
+	resolution, err := r.ListPrice(productID, customerID, at)
+	if err != nil {
+		return nil, err
+	}
+	if resolution == nil {
+		p, err := product.NewProductRepository(r.db).FindByID(productID)
+		if err != nil {
+			return nil, err
+		}
+		if p.IsKit() {
+			resolution, err = r.kitPrice(p, customerID, at)
+		} else {
+			resolution, err = r.batchPrice(productID, at)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	resolution.ProductID = productID
+	resolution.CustomerID = customerID
+	resolution.At = at
+	return resolution, nil
+}
+
+// batchPrice returns the selling price of the batch a sale of a product
+// would take stock from first, or ErrNoPrice when there is none.
+func (r *Resolver) batchPrice(productID uuid.UUID, at time.Time) (*Resolution, error) {
+	batches, err := inventory.NewBatchRepository(r.db).FindAllocatable(productID, nil, at.UTC())
+	if err != nil {
+		return nil, err
+	}
+	if len(batches) == 0 {
+		return nil, ErrNoPrice
+	}
+	return &Resolution{
+		Price:   batches[0].SellingPrice,
+		Source:  SourceBatch,
+		BatchID: &batches[0].ID,
+	}, nil
+}
+
+// kitPrice returns the price of the components one of a kit takes, each
+// resolved for the customer at t.
+func (r *Resolver) kitPrice(kit *product.Product, customerID *uuid.UUID, at time.Time) (*Resolution, error) {
+	resolution := &Resolution{Source: SourceComponents}
+	for _, c := range kit.Components {
+		component, err := r.Resolve(c.ComponentID, customerID, at)
+		if err != nil {
+			return nil, err
+		}
+		resolution.Price += component.Price.Mul(c.Quantity)
+	}
+	return resolution, nil
+}
+
+// fromList returns a product's price on a list at t, or nil if the list
+// does not price it then.
+func (r *Resolver) fromList(listID, productID uuid.UUID, at time.Time, source Source) (*Resolution, error) {
+	price, err := r.prices.FindEffective(listID, productID, at.UTC())
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &Resolution{
+		Price:       price.Price,
+		Source:      source,
+		PriceListID: &price.PriceListID,
+	}, nil
+}
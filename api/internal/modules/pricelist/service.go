@@ -0,0 +1,252 @@
+package pricelist
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/common"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/db"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/modules/auth"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/modules/customer"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/modules/product"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/errors"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/logger"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/period"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/validator"
+)
+
+// PriceListService handles business logic for price lists and their prices.
+type PriceListService struct {
+	db   *db.DB
+	repo *PriceListRepository
+}
+
+// NewPriceListService creates a new price list service.
+func NewPriceListService(database *db.DB, repo *PriceListRepository) *PriceListService {
+	return &PriceListService{db: database, repo: repo}
+}
+
+// GetAll retrieves all price lists.
+func (s *PriceListService) GetAll() ([]PriceList, error) {
+	return s.repo.FindAll()
+}
+
+// GetByID retrieves a price list by ID.
+func (s *PriceListService) GetByID(id uuid.UUID) (*PriceList, error) {
+	return s.repo.FindByID(id)
+}
+
+// GetCustomers retrieves the customers assigned to a price list.
+func (s *PriceListService) GetCustomers(id uuid.UUID) ([]customer.Customer, error) {
+	if _, err := s.repo.FindByID(id); err != nil {
+		return nil, err
+	}
+	return customer.NewCustomerRepository(s.db).FindByPriceListID(id)
+}
+
+// Create creates a new price list.
+func (s *PriceListService) Create(req CreatePriceListRequest, user *auth.User) (*PriceList, error) {
+	if err := validator.Struct(req); err != nil {
+		return nil, err
+	}
+
+	list := &PriceList{
+		Name:        req.Name,
+		Description: req.Description,
+		IsDefault:   req.IsDefault,
+		Active:      true,
+		Prices:      []Price{},
+		AuditFields: common.AuditFields{
+			CreatedBy: user.ID,
+			UpdatedBy: user.ID,
+		},
+	}
+
+	// A default list takes the flag from the list that had it.
+	err := s.db.Transaction(func(tx *db.DB) error {
+		repo := NewPriceListRepository(tx)
+		if err := repo.Create(list); err != nil {
+			return err
+		}
+		if list.IsDefault {
+			return repo.ClearDefault(list.ID, user.ID)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("price list created", "price_list_id", list.ID, "is_default", list.IsDefault, "created_by", user.ID)
+	return list, nil
+}
+
+// Update updates an existing price list.
+func (s *PriceListService) Update(id uuid.UUID, req UpdatePriceListRequest, user *auth.User) (*PriceList, error) {
+	if err := validator.Struct(req); err != nil {
+		return nil, err
+	}
+
+	var list *PriceList
+	err := s.db.Transaction(func(tx *db.DB) error {
+		repo := NewPriceListRepository(tx)
+
+		var err error
+		list, err = repo.FindByID(id)
+		if err != nil {
+			return err
+		}
+
+		list.Name = req.Name
+		list.Description = req.Description
+		list.IsDefault = req.IsDefault
+		list.Active = req.Active
+		list.UpdatedBy = user.ID
+
+		if err := repo.Update(list); err != nil {
+			return err
+		}
+		if list.IsDefault {
+			return repo.ClearDefault(list.ID, user.ID)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return list, nil
+}
+
+// AddPrice prices a product on a price list. Prices are never edited, so a
+// change of price is a new period, which ends the open-ended period of the
+// product it supersedes. A bounded period, such as a promotional price,
+// only interrupts the open-ended period, which resumes as a new period at
+// its end. Periods of a product may not otherwise overlap.
+func (s *PriceListService) AddPrice(listID uuid.UUID, req CreatePriceRequest, user *auth.User) (*Price, error) {
+	if err := validator.Struct(req); err != nil {
+		return nil, err
+	}
+	if req.EffectiveTo != nil && !req.EffectiveTo.After(req.EffectiveFrom) {
+		return nil, validator.ValidationErrors{{Field: "effectiveTo", Message: "must be after effectiveFrom"}}
+	}
+
+	price := &Price{
+		PriceListID:   listID,
+		ProductID:     req.ProductID,
+		Price:         req.Price,
+		EffectiveFrom: req.EffectiveFrom.UTC(),
+		EffectiveTo:   common.UTC(req.EffectiveTo),
+		AuditFields: common.AuditFields{
+			CreatedBy: user.ID,
+			UpdatedBy: user.ID,
+		},
+	}
+
+	err := s.db.Transaction(func(tx *db.DB) error {
+		if _, err := NewPriceListRepository(tx).FindByID(listID); err != nil {
+			return err
+		}
+		if _, err := product.NewProductRepository(tx).FindByID(req.ProductID); err != nil {
+			if errors.IsNotFound(err) {
+				return validator.ValidationErrors{{Field: "productId", Message: "product not found"}}
+			}
+			return err
+		}
+
+		prices := NewPriceRepository(tx)
+		periods, err := prices.FindByListIDAndProductID(listID, req.ProductID)
+		if err != nil {
+			return err
+		}
+
+		series := make([]period.Period, len(periods))
+		for i, p := range periods {
+			series[i] = p.Period()
+		}
+		change := period.Add(series, price.Period())
+		if change.Conflict >= 0 {
+			return errors.Newf(http.StatusConflict, errors.ErrConflict,
+				"price overlaps the product's period from %s", periods[change.Conflict].EffectiveFrom.Format("2006-01-02"))
+		}
+
+		if change.Superseded >= 0 {
+			superseded := periods[change.Superseded]
+			if err := prices.End(superseded.ID, price.EffectiveFrom, user.ID); err != nil {
+				return err
+			}
+			if change.Resumes != nil {
+				resumed := &Price{
+					PriceListID:   listID,
+					ProductID:     req.ProductID,
+					Price:         superseded.Price,
+					EffectiveFrom: *change.Resumes,
+					AuditFields: common.AuditFields{
+						CreatedBy: user.ID,
+						UpdatedBy: user.ID,
+					},
+				}
+				if err := prices.Create(resumed); err != nil {
+					return err
+				}
+			}
+		}
+
+		return prices.Create(price)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("price added", "price_id", price.ID, "price_list_id", listID, "product_id", req.ProductID, "price", price.Price.String(), "created_by", user.ID)
+	return price, nil
+}
+
+// AssignCustomer assigns a customer to a price list, taking the customer
+// off any list they were on.
+func (s *PriceListService) AssignCustomer(listID, customerID uuid.UUID, user *auth.User) error {
+	err := s.db.Transaction(func(tx *db.DB) error {
+		if _, err := NewPriceListRepository(tx).FindByID(listID); err != nil {
+			return err
+		}
+		if err := customer.NewCustomerRepository(tx).AssignPriceList(customerID, listID, user.ID); err != nil {
+			if errors.IsNotFound(err) {
+				return validator.ValidationErrors{{Field: "customerId", Message: "customer not found"}}
+			}
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	logger.Info("customer assigned to price list", "price_list_id", listID, "customer_id", customerID, "assigned_by", user.ID)
+	return nil
+}
+
+// UnassignCustomer takes a customer off a price list.
+func (s *PriceListService) UnassignCustomer(listID, customerID uuid.UUID, user *auth.User) error {
+	return customer.NewCustomerRepository(s.db).UnassignPriceList(customerID, listID, user.ID)
+}
+
+// Resolve decides the unit price of a product for a customer at t.
+func (s *PriceListService) Resolve(productID uuid.UUID, customerID *uuid.UUID, at time.Time) (*Resolution, error) {
+	if _, err := product.NewProductRepository(s.db).FindByID(productID); err != nil {
+		if errors.IsNotFound(err) {
+			return nil, validator.ValidationErrors{{Field: "productId", Message: "product not found"}}
+		}
+		return nil, err
+	}
+	if customerID != nil {
+		if _, err := customer.NewCustomerRepository(s.db).FindByID(*customerID); err != nil {
+			if errors.IsNotFound(err) {
+				return nil, validator.ValidationErrors{{Field: "customerId", Message: "customer not found"}}
+			}
+			return nil, err
+		}
+	}
+
+	return NewResolver(s.db).Resolve(productID, customerID, at)
+}
@@ -0,0 +1,92 @@
+package product
+
+import (
+	"strconv"
+	"strings"
+)
+
+// BarcodeType is the symbology of a product barcode.
+type BarcodeType string
+
+// Barcode types.
+const (
+	BarcodeEAN8  BarcodeType = "ean8"
+	BarcodeUPCA  BarcodeType = "upca"
+	BarcodeEAN13 BarcodeType = "ean13"
+)
+
+// EmbeddedValue is what a variable measure barcode carries in its value
+// digits.
+type EmbeddedValue string
+
+// Embedded values.
+const (
+	EmbeddedPrice  EmbeddedValue = "price"
+	EmbeddedWeight EmbeddedValue = "weight"
+)
+
+// gtinLength is the length barcodes are padded to so that the same item
+// code read as UPC-A or EAN-13 compares equal.
+const gtinLength = 14
+
+// parseBarcode returns the symbology of a code and its GTIN-14 form. ok is
+// false unless the code is an EAN-8, UPC-A or EAN-13 code with a valid check
+// digit.
+func parseBarcode(code string) (barcodeType BarcodeType, gtin string, ok bool) {
+	for _, c := range code {
+		if c < '0' || c > '9' {
+			return "", "", false
+		}
+	}
+
+	switch len(code) {
+	case 8:
+		barcodeType = BarcodeEAN8
+	case 12:
+		barcodeType = BarcodeUPCA
+	case 13:
+		barcodeType = BarcodeEAN13
+	default:
+		return "", "", false
+	}
+	if checkDigit(code[:len(code)-1]) != code[len(code)-1] {
+		return "", "", false
+	}
+	return barcodeType, strings.Repeat("0", gtinLength-len(code)) + code, true
+}
+
+// checkDigit returns the GS1 check digit of a code without its check digit:
+// digits are weighted 3 and 1 alternately from the right.
+func checkDigit(digits string) byte {
+	sum := 0
+	for i := len(digits) - 1; i >= 0; i-- {
+		d := int(digits[i] - '0')
+		if (len(digits)-1-i)%2 == 0 {
+			d *= 3
+		}
+		sum += d
+	}
+	return byte('0' + (10-sum%10)%10)
+}
+
+// isVariableMeasure reports whether a code is a variable measure code: an
+// EAN-13 in the GS1 prefix range 20-29, reserved for in-store use. Its
+// layout is the prefix, a five digit item code, a five digit price or
+// weight and the check digit.
+func isVariableMeasure(barcodeType BarcodeType, code string) bool {
+	return barcodeType == BarcodeEAN13 && code[0] == '2'
+}
+
+// variableMeasureItem returns a variable measure code with its value digits
+// zeroed, which is the code its item is registered under.
+func variableMeasureItem(code string) string {
+	item := code[:7] + "00000"
+	return item + string(checkDigit(item))
+}
+
+// variableMeasureValue returns the price, in minor units, or the weight, in
+// grams, embedded in a variable measure code.
+func variableMeasureValue(code string) int {
+	value, _ := strconv.Atoi(code[7:12])
+	return value
+}
@@ -0,0 +1,91 @@
+package product
+
+import (
+	"cmp"
+	"net/http"
+	"slices"
+
+	"github.com/google/uuid"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/errors"
+)
+
+// ErrCategoryCycle is returned when moving a category under itself or one
+// of its descendants.
+var ErrCategoryCycle = errors.New(http.StatusUnprocessableEntity, errors.ErrUnprocessable, "a category cannot be moved under itself or one of its subcategories")
+
+// ErrCategoryHasChildren is returned when deleting a category that has
+// subcategories.
+var ErrCategoryHasChildren = errors.New(http.StatusConflict, errors.ErrConflict, "category has subcategories")
+
+// Hierarchy maps every category to its parent, which is nil for top-level
+// categories. Levels count down from 0 at the top of the tree.
+type Hierarchy map[uuid.UUID]*uuid.UUID
+
+// NewHierarchy returns the hierarchy of the categories.
+func NewHierarchy(categories []ProductCategory) Hierarchy {
+	h := make(Hierarchy, len(categories))
+	for _, c := range categories {
+		h[c.ID] = c.ParentID
+	}
+	return h
+}
+
+// Path returns the categories from the top of the tree down to a category.
+func (h Hierarchy) Path(id uuid.UUID) []uuid.UUID {
+	path := []uuid.UUID{id}
+	// A path is never longer than the tree, even if a cycle slipped in.
+	for parent := h[id]; parent != nil && len(path) <= len(h); parent = h[*parent] {
+		path = append(path, *parent)
+	}
+	slices.Reverse(path)
+	return path
+}
+
+// Ancestor returns the category at a level above a category, or the
+// category itself when it is at that level or above it. It is what reports
+// roll a category up into.
+func (h Hierarchy) Ancestor(id uuid.UUID, level int) uuid.UUID {
+	path := h.Path(id)
+	return path[min(level, len(path)-1)]
+}
+
+// Subtree returns a category and all of its descendants.
+func (h Hierarchy) Subtree(id uuid.UUID) []uuid.UUID {
+	children := make(map[uuid.UUID][]uuid.UUID)
+	for child, parent := range h {
+		if parent != nil {
+			children[*parent] = append(children[*parent], child)
+		}
+	}
+
+	subtree := []uuid.UUID{id}
+	for i := 0; i < len(subtree) && len(subtree) <= len(h); i++ {
+		subtree = append(subtree, children[subtree[i]]...)
+	}
+	return subtree
+}
+
+// buildTree arranges categories into the trees under the top-level
+// categories, each level ordered by name.
+func buildTree(categories []ProductCategory) []CategoryNode {
+	children := make(map[uuid.UUID][]ProductCategory)
+	var roots []ProductCategory
+	for _, c := range categories {
+		if c.ParentID == nil {
+			roots = append(roots, c)
+		} else {
+			children[*c.ParentID] = append(children[*c.ParentID], c)
+		}
+	}
+
+	var build func(level []ProductCategory) []CategoryNode
+	build = func(level []ProductCategory) []CategoryNode {
+		slices.SortFunc(level, func(a, b ProductCategory) int { return cmp.Compare(a.Name, b.Name) })
+		nodes := make([]CategoryNode, len(level))
+		for i, c := range level {
+			nodes[i] = CategoryNode{ProductCategory: c, Children: build(children[c.ID])}
+		}
+		return nodes
+	}
+	return build(roots)
+}
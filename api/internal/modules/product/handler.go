@@ -2,12 +2,17 @@ package product
 
 import (
 	"encoding/json"
+	"io"
 	"net/http"
+	"strings"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/google/uuid"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/config"
 	"github.com/shanmugharajk/go-react-web-api/api/internal/db"
 	"github.com/shanmugharajk/go-react-web-api/api/internal/modules/auth"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/modules/tax"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/blob"
 	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/errors"
 	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/response"
 	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/validator"
@@ -19,9 +24,9 @@ type Handler struct {
 }
 
 // NewHandler creates a new product handler.
-func NewHandler(database *db.DB) *Handler {
+func NewHandler(database *db.DB, blobs blob.Store) *Handler {
 	repo := NewProductRepository(database)
-	service := NewProductService(repo)
+	service := NewProductService(repo, NewCategoryRepository(database), tax.NewClassRepository(database), blobs)
 	return &Handler{service: service}
 }
 
@@ -30,16 +35,35 @@ func (h *Handler) Routes() chi.Router {
 	r := chi.NewRouter()
 	r.Get("/", h.GetAll)
 	r.Post("/", h.Create)
+	r.Get("/lookup", h.Lookup)
 	r.Get("/{id}", h.GetByID)
 	r.Put("/{id}", h.Update)
 	r.Delete("/{id}", h.Delete)
+	r.Get("/{id}/variants", h.GetVariants)
+	r.Post("/{id}/variants", h.CreateVariant)
+	r.Put("/{id}/variants/{variantId}", h.UpdateVariant)
 	return r
 }
 
-// GetAll handles retrieving all products.
+// GetAll handles retrieving all products, optionally only those in the
+// category given by the categoryId query parameter or its subcategories.
 func (h *Handler) GetAll(w http.ResponseWriter, r *http.Request) {
-	products, err := h.service.GetAll()
+	var categoryID *uuid.UUID
+	if categoryIDStr := r.URL.Query().Get("categoryId"); categoryIDStr != "" {
+		id, err := uuid.Parse(categoryIDStr)
+		if err != nil {
+			response.Error(w, http.StatusBadRequest, "invalid category ID")
+			return
+		}
+		categoryID = &id
+	}
+
+	products, err := h.service.GetAll(categoryID)
 	if err != nil {
+		if validator.IsValidationError(err) {
+			response.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
 		response.Error(w, http.StatusInternalServerError, "failed to retrieve products")
 		return
 	}
@@ -65,6 +89,39 @@ func (h *Handler) GetByID(w http.ResponseWriter, r *http.Request) {
 	response.Success(w, product)
 }
 
+// Lookup handles finding a product by the barcode query parameter, as
+// scanned at the till, or by the sku query parameter.
+func (h *Handler) Lookup(w http.ResponseWriter, r *http.Request) {
+	barcode := r.URL.Query().Get("barcode")
+	sku := r.URL.Query().Get("sku")
+
+	var result any
+	var err error
+	switch {
+	case barcode != "":
+		result, err = h.service.Lookup(barcode)
+	case sku != "":
+		result, err = h.service.GetBySKU(sku)
+	default:
+		response.Error(w, http.StatusBadRequest, "barcode or sku is required")
+		return
+	}
+	if err != nil {
+		if errors.IsNotFound(err) {
+			response.Error(w, http.StatusNotFound, "product not found")
+			return
+		}
+		if validator.IsValidationError(err) {
+			response.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, "failed to look up product")
+		return
+	}
+
+	response.Success(w, result)
+}
+
 // Create handles creating a new product.
 func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
 	var req CreateProductRequest
@@ -85,6 +142,10 @@ func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
 			response.Error(w, http.StatusBadRequest, err.Error())
 			return
 		}
+		if errors.IsConflict(err) {
+			response.Error(w, http.StatusConflict, err.Error())
+			return
+		}
 		response.Error(w, http.StatusInternalServerError, "failed to create product")
 		return
 	}
@@ -123,6 +184,14 @@ func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
 			response.Error(w, http.StatusBadRequest, err.Error())
 			return
 		}
+		if errors.IsConflict(err) {
+			response.Error(w, http.StatusConflict, err.Error())
+			return
+		}
+		if errors.IsUnprocessable(err) {
+			response.Error(w, http.StatusUnprocessableEntity, err.Error())
+			return
+		}
 		response.Error(w, http.StatusInternalServerError, "failed to update product")
 		return
 	}
@@ -150,6 +219,10 @@ func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) {
 			response.Error(w, http.StatusNotFound, "product not found")
 			return
 		}
+		if errors.IsConflict(err) {
+			response.Error(w, http.StatusConflict, err.Error())
+			return
+		}
 		response.Error(w, http.StatusInternalServerError, "failed to delete product")
 		return
 	}
@@ -157,6 +230,355 @@ func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) {
 	response.NoContent(w)
 }
 
+// GetVariants handles retrieving the variants of a product.
+func (h *Handler) GetVariants(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid product ID")
+		return
+	}
+
+	variants, err := h.service.GetVariants(id)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			response.Error(w, http.StatusNotFound, "product not found")
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, "failed to retrieve variants")
+		return
+	}
+
+	response.Success(w, variants)
+}
+
+// CreateVariant handles adding a variant to a product.
+func (h *Handler) CreateVariant(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid product ID")
+		return
+	}
+
+	var req CreateVariantRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	user, err := auth.GetUserFromContext(r.Context())
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to retrieve user")
+		return
+	}
+
+	variant, err := h.service.CreateVariant(id, req, user)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			response.Error(w, http.StatusNotFound, "product not found")
+			return
+		}
+		if validator.IsValidationError(err) {
+			response.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if errors.IsConflict(err) {
+			response.Error(w, http.StatusConflict, err.Error())
+			return
+		}
+		if errors.IsUnprocessable(err) {
+			response.Error(w, http.StatusUnprocessableEntity, err.Error())
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, "failed to create variant")
+		return
+	}
+
+	response.Created(w, variant)
+}
+
+// UpdateVariant handles updating a variant of a product.
+func (h *Handler) UpdateVariant(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid product ID")
+		return
+	}
+
+	variantIDStr := chi.URLParam(r, "variantId")
+	variantID, err := uuid.Parse(variantIDStr)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid variant ID")
+		return
+	}
+
+	var req UpdateVariantRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	user, err := auth.GetUserFromContext(r.Context())
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to retrieve user")
+		return
+	}
+
+	variant, err := h.service.UpdateVariant(id, variantID, req, user)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			response.Error(w, http.StatusNotFound, "variant not found")
+			return
+		}
+		if validator.IsValidationError(err) {
+			response.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if errors.IsConflict(err) {
+			response.Error(w, http.StatusConflict, err.Error())
+			return
+		}
+		if errors.IsUnprocessable(err) {
+			response.Error(w, http.StatusUnprocessableEntity, err.Error())
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, "failed to update variant")
+		return
+	}
+
+	response.Success(w, variant)
+}
+
+// imageCacheControl lets clients keep images for a year without asking
+// again: an image never changes under its URL. Images are only served to
+// signed-in users, so shared caches must not keep them.
+const imageCacheControl = "private, max-age=31536000, immutable"
+
+// ImageHandler handles HTTP requests for product images.
+type ImageHandler struct {
+	service  *ImageService
+	maxBytes int
+}
+
+// NewImageHandler creates a new product image handler.
+func NewImageHandler(database *db.DB, blobs blob.Store, cfg config.ImageConfig) *ImageHandler {
+	service := NewImageService(NewImageRepository(database), NewProductRepository(database), blobs, cfg)
+	return &ImageHandler{service: service, maxBytes: cfg.MaxBytes}
+}
+
+// Routes returns the product image routes, relative to a product.
+func (h *ImageHandler) Routes() chi.Router {
+	r := chi.NewRouter()
+	r.Get("/", h.GetByProductID)
+	r.Post("/", h.Upload)
+	r.Put("/order", h.Reorder)
+	r.Get("/{imageId}", h.Serve)
+	r.Get("/{imageId}/thumbnail", h.ServeThumbnail)
+	r.Delete("/{imageId}", h.Delete)
+	return r
+}
+
+// GetByProductID handles retrieving the images of a product in order.
+func (h *ImageHandler) GetByProductID(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid product ID")
+		return
+	}
+
+	images, err := h.service.GetByProductID(id)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			response.Error(w, http.StatusNotFound, "product not found")
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, "failed to retrieve product images")
+		return
+	}
+
+	response.Success(w, images)
+}
+
+// Upload handles uploading an image of a product, sent as the image field
+// of a multipart form.
+func (h *ImageHandler) Upload(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid product ID")
+		return
+	}
+
+	// Leave room over the image limit for the rest of the form.
+	limit := int64(h.maxBytes) + 64<<10
+	if r.ContentLength > limit {
+		response.Error(w, http.StatusRequestEntityTooLarge, "image is too large")
+		return
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, limit)
+	file, _, err := r.FormFile("image")
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid image upload")
+		return
+	}
+	defer file.Close()
+	data, err := io.ReadAll(io.LimitReader(file, int64(h.maxBytes)+1))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid image upload")
+		return
+	}
+	if len(data) > h.maxBytes {
+		response.Error(w, http.StatusRequestEntityTooLarge, "image is too large")
+		return
+	}
+
+	user, err := auth.GetUserFromContext(r.Context())
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to retrieve user")
+		return
+	}
+
+	image, err := h.service.Upload(id, data, user)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			response.Error(w, http.StatusNotFound, "product not found")
+			return
+		}
+		if validator.IsValidationError(err) {
+			response.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, "failed to upload product image")
+		return
+	}
+
+	response.Created(w, image)
+}
+
+// Reorder handles putting a product's images in a new order.
+func (h *ImageHandler) Reorder(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid product ID")
+		return
+	}
+
+	var req ReorderImagesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	user, err := auth.GetUserFromContext(r.Context())
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to retrieve user")
+		return
+	}
+
+	images, err := h.service.Reorder(id, req, user)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			response.Error(w, http.StatusNotFound, "product not found")
+			return
+		}
+		if validator.IsValidationError(err) {
+			response.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, "failed to reorder product images")
+		return
+	}
+
+	response.Success(w, images)
+}
+
+// Serve handles downloading an image file.
+func (h *ImageHandler) Serve(w http.ResponseWriter, r *http.Request) {
+	h.serve(w, r, false)
+}
+
+// ServeThumbnail handles downloading the thumbnail of an image.
+func (h *ImageHandler) ServeThumbnail(w http.ResponseWriter, r *http.Request) {
+	h.serve(w, r, true)
+}
+
+// serve writes an image file or its thumbnail with headers that let
+// clients cache it, answering a request for a copy the client already has
+// with 304 Not Modified.
+func (h *ImageHandler) serve(w http.ResponseWriter, r *http.Request, thumbnail bool) {
+	productID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid product ID")
+		return
+	}
+	id, err := uuid.Parse(chi.URLParam(r, "imageId"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid image ID")
+		return
+	}
+
+	image, data, err := h.service.Read(productID, id, thumbnail)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			response.Error(w, http.StatusNotFound, "image not found")
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, "failed to retrieve image")
+		return
+	}
+
+	etag := image.ETag(thumbnail)
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Cache-Control", imageCacheControl)
+	for _, match := range strings.Split(r.Header.Get("If-None-Match"), ",") {
+		if match = strings.TrimSpace(match); match == etag || match == "*" {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+	}
+
+	contentType := image.ContentType
+	if thumbnail {
+		contentType = image.ThumbnailContentType
+	}
+	response.Raw(w, http.StatusOK, contentType, data)
+}
+
+// Delete handles deleting an image of a product.
+func (h *ImageHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	productID, err := uuid.Parse(chi.URLParam(r, "id"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid product ID")
+		return
+	}
+	id, err := uuid.Parse(chi.URLParam(r, "imageId"))
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid image ID")
+		return
+	}
+
+	user, err := auth.GetUserFromContext(r.Context())
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to retrieve user")
+		return
+	}
+
+	if err := h.service.Delete(productID, id, user); err != nil {
+		if errors.IsNotFound(err) {
+			response.Error(w, http.StatusNotFound, "image not found")
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, "failed to delete product image")
+		return
+	}
+
+	response.NoContent(w)
+}
+
 // CategoryHandler handles HTTP requests for product categories.
 type CategoryHandler struct {
 	service *CategoryService
@@ -165,7 +587,7 @@ type CategoryHandler struct {
 // NewCategoryHandler creates a new product category handler.
 func NewCategoryHandler(database *db.DB) *CategoryHandler {
 	repo := NewCategoryRepository(database)
-	service := NewCategoryService(repo)
+	service := NewCategoryService(database, repo, tax.NewClassRepository(database))
 	return &CategoryHandler{service: service}
 }
 
@@ -174,9 +596,14 @@ func (h *CategoryHandler) Routes() chi.Router {
 	r := chi.NewRouter()
 	r.Get("/", h.GetAll)
 	r.Post("/", h.Create)
+	r.Get("/tree", h.GetTree)
 	r.Get("/{id}", h.GetByID)
 	r.Put("/{id}", h.Update)
 	r.Delete("/{id}", h.Delete)
+	r.Post("/{id}/move", h.Move)
+	r.Post("/{id}/attributes", h.CreateAttribute)
+	r.Put("/{id}/attributes/{attributeId}", h.UpdateAttribute)
+	r.Delete("/{id}/attributes/{attributeId}", h.DeleteAttribute)
 	return r
 }
 
@@ -191,6 +618,17 @@ func (h *CategoryHandler) GetAll(w http.ResponseWriter, r *http.Request) {
 	response.Success(w, categories)
 }
 
+// GetTree handles retrieving the product categories as a tree.
+func (h *CategoryHandler) GetTree(w http.ResponseWriter, r *http.Request) {
+	tree, err := h.service.GetTree()
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to retrieve product category tree")
+		return
+	}
+
+	response.Success(w, tree)
+}
+
 // GetByID handles retrieving a product category by ID.
 func (h *CategoryHandler) GetByID(w http.ResponseWriter, r *http.Request) {
 	idStr := chi.URLParam(r, "id")
@@ -274,6 +712,48 @@ func (h *CategoryHandler) Update(w http.ResponseWriter, r *http.Request) {
 	response.Success(w, category)
 }
 
+// Move handles moving a product category, with its subcategories.
+func (h *CategoryHandler) Move(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid category ID")
+		return
+	}
+
+	var req MoveCategoryRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	user, err := auth.GetUserFromContext(r.Context())
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to retrieve user")
+		return
+	}
+
+	category, err := h.service.Move(id, req, user)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			response.Error(w, http.StatusNotFound, "product category not found")
+			return
+		}
+		if validator.IsValidationError(err) {
+			response.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if errors.IsUnprocessable(err) {
+			response.Error(w, http.StatusUnprocessableEntity, err.Error())
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, "failed to move product category")
+		return
+	}
+
+	response.Success(w, category)
+}
+
 // Delete handles deleting a product category.
 func (h *CategoryHandler) Delete(w http.ResponseWriter, r *http.Request) {
 	idStr := chi.URLParam(r, "id")
@@ -294,9 +774,130 @@ func (h *CategoryHandler) Delete(w http.ResponseWriter, r *http.Request) {
 			response.Error(w, http.StatusNotFound, "product category not found")
 			return
 		}
+		if errors.IsConflict(err) {
+			response.Error(w, http.StatusConflict, err.Error())
+			return
+		}
 		response.Error(w, http.StatusInternalServerError, "failed to delete product category")
 		return
 	}
 
 	response.NoContent(w)
 }
+
+// CreateAttribute handles adding an attribute to a product category.
+func (h *CategoryHandler) CreateAttribute(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid category ID")
+		return
+	}
+
+	var req CreateAttributeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	attribute, err := h.service.CreateAttribute(id, req)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			response.Error(w, http.StatusNotFound, "product category not found")
+			return
+		}
+		if validator.IsValidationError(err) {
+			response.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if errors.IsConflict(err) {
+			response.Error(w, http.StatusConflict, err.Error())
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, "failed to create attribute")
+		return
+	}
+
+	response.Created(w, attribute)
+}
+
+// UpdateAttribute handles updating an attribute of a product category.
+func (h *CategoryHandler) UpdateAttribute(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid category ID")
+		return
+	}
+
+	attributeIDStr := chi.URLParam(r, "attributeId")
+	attributeID, err := uuid.Parse(attributeIDStr)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid attribute ID")
+		return
+	}
+
+	var req UpdateAttributeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	attribute, err := h.service.UpdateAttribute(id, attributeID, req)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			response.Error(w, http.StatusNotFound, "attribute not found")
+			return
+		}
+		if validator.IsValidationError(err) {
+			response.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if errors.IsConflict(err) {
+			response.Error(w, http.StatusConflict, err.Error())
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, "failed to update attribute")
+		return
+	}
+
+	response.Success(w, attribute)
+}
+
+// DeleteAttribute handles deleting an attribute of a product category.
+func (h *CategoryHandler) DeleteAttribute(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid category ID")
+		return
+	}
+
+	attributeIDStr := chi.URLParam(r, "attributeId")
+	attributeID, err := uuid.Parse(attributeIDStr)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid attribute ID")
+		return
+	}
+
+	user, err := auth.GetUserFromContext(r.Context())
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to retrieve user")
+		return
+	}
+
+	if err := h.service.DeleteAttribute(id, attributeID, user); err != nil {
+		if errors.IsNotFound(err) {
+			response.Error(w, http.StatusNotFound, "attribute not found")
+			return
+		}
+		if errors.IsConflict(err) {
+			response.Error(w, http.StatusConflict, err.Error())
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, "failed to delete attribute")
+		return
+	}
+
+	response.NoContent(w)
+}
@@ -0,0 +1,117 @@
+package product
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"image"
+	_ "image/gif" // registers GIF with image.Decode
+	"image/jpeg"
+	"image/png"
+	"slices"
+
+	"github.com/gabriel-vasile/mimetype"
+	"github.com/google/uuid"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/config"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/thumbnail"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/validator"
+	"gorm.io/gorm"
+)
+
+// imageTypes are the MIME types of the image formats accepted. The format
+// is sniffed from the file's content rather than taken from the upload.
+var imageTypes = []string{"image/jpeg", "image/png", "image/gif"}
+
+// imageURLPrefix is where the image routes are mounted.
+const imageURLPrefix = "/api/v1/products/"
+
+// AfterFind fills in the URLs of an image read from the database.
+func (i *Image) AfterFind(*gorm.DB) error {
+	i.setURLs()
+	return nil
+}
+
+// setURLs fills in the URLs the image and its thumbnail are served from.
+func (i *Image) setURLs() {
+	i.URL = fmt.Sprintf("%s%s/images/%s", imageURLPrefix, i.ProductID, i.ID)
+	i.ThumbnailURL = i.URL + "/thumbnail"
+}
+
+// ETag returns the entity tag of the image, or of its thumbnail.
+func (i *Image) ETag(thumbnail bool) string {
+	if thumbnail {
+		return `"` + i.Checksum + `-thumbnail"`
+	}
+	return `"` + i.Checksum + `"`
+}
+
+// imageKey returns the blob key of an image's file.
+func imageKey(productID, imageID uuid.UUID) string {
+	return fmt.Sprintf("products/%s/images/%s", productID, imageID)
+}
+
+// thumbnailKey returns the blob key of an image's thumbnail.
+func thumbnailKey(productID, imageID uuid.UUID) string {
+	return imageKey(productID, imageID) + "-thumbnail"
+}
+
+// processImage checks an uploaded file is an image of an accepted format
+// within the configured limits, and makes its thumbnail. Thumbnails of
+// JPEG images are JPEG; the rest are PNG, keeping their transparency.
+func processImage(data []byte, cfg config.ImageConfig) (*Image, []byte, error) {
+	if len(data) > cfg.MaxBytes {
+		return nil, nil, validator.ValidationErrors{{
+			Field:   "image",
+			Message: fmt.Sprintf("must be at most %d bytes", cfg.MaxBytes),
+		}}
+	}
+	contentType := mimetype.Detect(data).String()
+	if !slices.Contains(imageTypes, contentType) {
+		return nil, nil, validator.ValidationErrors{{Field: "image", Message: "must be a JPEG, PNG or GIF image"}}
+	}
+
+	// Check the dimensions from the header before decoding, so an image
+	// that would take too much memory to decode is never decoded.
+	header, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, nil, validator.ValidationErrors{{Field: "image", Message: "could not be read"}}
+	}
+	if header.Width > cfg.MaxDimension || header.Height > cfg.MaxDimension {
+		return nil, nil, validator.ValidationErrors{{
+			Field:   "image",
+			Message: fmt.Sprintf("must be at most %dx%d pixels", cfg.MaxDimension, cfg.MaxDimension),
+		}}
+	}
+	if header.Width == 0 || header.Height == 0 {
+		return nil, nil, validator.ValidationErrors{{Field: "image", Message: "must not be empty"}}
+	}
+	decoded, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, nil, validator.ValidationErrors{{Field: "image", Message: "could not be read"}}
+	}
+
+	thumb := thumbnail.Fit(decoded, cfg.ThumbnailSize)
+	var buf bytes.Buffer
+	thumbType := "image/png"
+	if contentType == "image/jpeg" {
+		thumbType = "image/jpeg"
+		err = jpeg.Encode(&buf, thumb, &jpeg.Options{Quality: 85})
+	} else {
+		err = png.Encode(&buf, thumb)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sum := sha256.Sum256(data)
+	img := &Image{
+		ContentType:          contentType,
+		Size:                 int64(len(data)),
+		Width:                header.Width,
+		Height:               header.Height,
+		Checksum:             hex.EncodeToString(sum[:]),
+		ThumbnailContentType: thumbType,
+	}
+	return img, buf.Bytes(), nil
+}
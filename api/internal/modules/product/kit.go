@@ -0,0 +1,101 @@
+package product
+
+import (
+	"cmp"
+	"fmt"
+	"net/http"
+	"slices"
+
+	"github.com/google/uuid"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/errors"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/quantity"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/validator"
+)
+
+// ErrKitVariants is returned when adding a variant to a kit.
+var ErrKitVariants = errors.New(http.StatusUnprocessableEntity, errors.ErrUnprocessable, "kits cannot have variants")
+
+// ErrKitComponent is returned when deleting a product that is a component
+// of a kit.
+var ErrKitComponent = errors.New(http.StatusConflict, errors.ErrConflict, "product is a component of a kit")
+
+// ErrComponentVariants is returned when adding a variant to a product that
+// is a component of a kit, as its stock would move to the variants.
+var ErrComponentVariants = errors.New(http.StatusConflict, errors.ErrConflict, "a component of a kit cannot have variants")
+
+// IsKit reports whether the product is a kit of other products.
+func (p *Product) IsKit() bool {
+	return p.Type == TypeKit
+}
+
+// Kits returns how many of a kit the available quantities of its
+// components, keyed by product ID, make up.
+func (p *Product) Kits(available map[uuid.UUID]quantity.Quantity) quantity.Quantity {
+	var kits quantity.Quantity
+	for i, c := range p.Components {
+		if n := p.ComponentKits(c, available[c.ComponentID]); i == 0 || n < kits {
+			kits = n
+		}
+	}
+	return kits
+}
+
+// ComponentKits returns how many of a kit an available quantity of one of
+// its components is enough for. Kits that are not fractional are only made
+// up whole.
+func (p *Product) ComponentKits(c KitComponent, available quantity.Quantity) quantity.Quantity {
+	kits := max(available, 0).Div(c.Quantity)
+	if !p.Fractional {
+		kits = quantity.FromInt(kits.Floor())
+	}
+	return kits
+}
+
+// buildComponents validates the components of a kit request, converting
+// their quantities to the components' base units. Only kits have
+// components, and a kit needs at least one. A component is a product that
+// holds stock: neither a kit nor a product with variants.
+func buildComponents(repo *ProductRepository, productType ProductType, reqs []ComponentRequest) ([]KitComponent, error) {
+	if productType != TypeKit {
+		if len(reqs) > 0 {
+			return nil, validator.ValidationErrors{{Field: "components", Message: "only kits have components"}}
+		}
+		return nil, nil
+	}
+	if len(reqs) == 0 {
+		return nil, validator.ValidationErrors{{Field: "components", Message: "a kit needs at least one component"}}
+	}
+
+	built := make([]KitComponent, 0, len(reqs))
+	for i, req := range reqs {
+		field := fmt.Sprintf("components[%d]", i)
+		if slices.ContainsFunc(built, func(c KitComponent) bool { return c.ComponentID == req.ProductID }) {
+			return nil, validator.ValidationErrors{{Field: field + ".productId", Message: "duplicate component"}}
+		}
+
+		component, err := repo.FindByID(req.ProductID)
+		if err != nil {
+			if errors.IsNotFound(err) {
+				return nil, validator.ValidationErrors{{Field: field + ".productId", Message: "product not found"}}
+			}
+			return nil, err
+		}
+		if component.IsKit() {
+			return nil, validator.ValidationErrors{{Field: field + ".productId", Message: "a kit cannot be a component"}}
+		}
+		variants, err := repo.CountVariants(component.ID)
+		if err != nil {
+			return nil, err
+		}
+		if variants > 0 {
+			return nil, validator.ValidationErrors{{Field: field + ".productId", Message: "use one of the product's variants"}}
+		}
+
+		q, err := component.ToBase(cmp.Or(req.Unit, component.BaseUnit), req.Quantity)
+		if err != nil {
+			return nil, validator.Nest(field, err)
+		}
+		built = append(built, KitComponent{ComponentID: component.ID, Quantity: q})
+	}
+	return built, nil
+}
@@ -3,53 +3,363 @@ package product
 import (
 	"github.com/google/uuid"
 	"github.com/shanmugharajk/go-react-web-api/api/internal/common"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/money"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/quantity"
 )
 
 // Product represents a product in the system.
+// TaxClassID overrides the tax class of the product's category when set.
+// It is checked against the tax classes when written rather than by a
+// foreign key, as SQLite can only add one by rebuilding the table.
+// SKU and barcodes are optional, and unique across products.
+//
+// Stock is held and priced in the product's base unit. Units are the larger
+// or smaller packs it is also bought or sold in; PurchaseUnit and SalesUnit
+// name the one purchase orders and sales use when a line names none, and
+// default to the base unit. Quantities of a product that is not Fractional
+// must come to whole base units; fractional products, such as goods sold by
+// weight, can be counted to a thousandth of a base unit.
+//
+// A product that comes in sizes, colours and the like is a parent whose
+// variants are products of their own, with ParentID set and a value for
+// each of the category's attributes. Variants have their own SKU, barcodes
+// and batches, and take their category, tax class, description and units
+// from the parent. A variant is named after its parent and its values, so
+// the names of a product's variants share its name. Like TaxClassID,
+// ParentID has no foreign key.
+//
+// A kit, such as a gift hamper or a combo pack, holds no stock of its own.
+// Its components are other products, each with the quantity of its base
+// unit one kit takes; selling a kit takes them from their batches, and how
+// many kits are available follows from their stock. A kit's type is set
+// when it is created.
+//
+// Images are pictures of the product in display order, the first being its
+// primary image.
 type Product struct {
-	ID          uuid.UUID `gorm:"type:char(36);primaryKey" json:"id"`
-	Name        string    `gorm:"unique;not null" json:"name"`
-	Description string    `json:"description"`
-	IsActive    bool      `gorm:"not null;default:true" json:"isActive"`
+	ID           uuid.UUID   `gorm:"type:char(36);primaryKey" json:"id"`
+	Name         string      `gorm:"unique;not null" json:"name"`
+	SKU          *string     `gorm:"uniqueIndex" json:"sku"`
+	Description  string      `json:"description"`
+	IsActive     bool        `gorm:"not null;default:true" json:"isActive"`
+	Type         ProductType `gorm:"not null;default:standard" json:"type"`
+	BaseUnit     string      `gorm:"not null;default:each" json:"baseUnit"`
+	Fractional   bool        `gorm:"not null;default:false" json:"fractional"`
+	PurchaseUnit string      `gorm:"not null;default:''" json:"purchaseUnit"`
+	SalesUnit    string      `gorm:"not null;default:''" json:"salesUnit"`
 
 	CategoryID uuid.UUID       `gorm:"type:char(36);index;not null" json:"categoryId"`
 	Category   ProductCategory `gorm:"foreignKey:CategoryID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:RESTRICT;" json:"-"`
 
+	TaxClassID *uuid.UUID `gorm:"type:char(36);index" json:"taxClassId"`
+	ParentID   *uuid.UUID `gorm:"type:char(36);index" json:"parentId"`
+
+	Barcodes   []Barcode          `gorm:"foreignKey:ProductID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"barcodes,omitempty"`
+	Units      []Unit             `gorm:"foreignKey:ProductID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"units,omitempty"`
+	Attributes []VariantAttribute `gorm:"foreignKey:ProductID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"attributes,omitempty"`
+	Components []KitComponent     `gorm:"foreignKey:KitID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"components,omitempty"`
+	Images     []Image            `gorm:"foreignKey:ProductID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"images,omitempty"`
+
 	common.AuditFields
 }
 
-// CreateProductRequest represents a request to create a product.
+// ProductType is whether a product holds stock or is a kit of others.
+type ProductType string
+
+// Product types.
+const (
+	TypeStandard ProductType = "standard"
+	TypeKit      ProductType = "kit"
+)
+
+// Barcode is a code that identifies a product when scanned. GTIN is the
+// code padded to 14 digits, so a UPC-A code and the same code read as
+// EAN-13 are one barcode.
+//
+// A variable measure barcode is registered under its item code with the
+// value digits zeroed; scanned codes carry the price or weight of the item
+// in those digits, as Embedded says.
+type Barcode struct {
+	ID        uuid.UUID     `gorm:"type:char(36);primaryKey" json:"id"`
+	ProductID uuid.UUID     `gorm:"type:char(36);index;not null" json:"productId"`
+	Code      string        `gorm:"not null" json:"code"`
+	GTIN      string        `gorm:"uniqueIndex;not null" json:"-"`
+	Type      BarcodeType   `gorm:"not null" json:"type"`
+	Embedded  EmbeddedValue `json:"embedded"`
+}
+
+// TableName specifies the table name for the Barcode model.
+func (Barcode) TableName() string {
+	return "product_barcodes"
+}
+
+// Unit is a pack of a product other than its base unit. Factor is how many
+// base units one of it holds, such as 24 for a case of 24 or 0.001 for a
+// gram of a product kept in kilograms.
+type Unit struct {
+	ID        uuid.UUID         `gorm:"type:char(36);primaryKey" json:"id"`
+	ProductID uuid.UUID         `gorm:"type:char(36);uniqueIndex:idx_product_unit;not null" json:"productId"`
+	Name      string            `gorm:"uniqueIndex:idx_product_unit;not null" json:"name"`
+	Factor    quantity.Quantity `gorm:"not null" json:"factor"`
+}
+
+// TableName specifies the table name for the Unit model.
+func (Unit) TableName() string {
+	return "product_units"
+}
+
+// VariantAttribute is the value a variant takes for one of its category's
+// attributes.
+type VariantAttribute struct {
+	ID          uuid.UUID `gorm:"type:char(36);primaryKey" json:"id"`
+	ProductID   uuid.UUID `gorm:"type:char(36);uniqueIndex:idx_variant_attribute;not null" json:"productId"`
+	AttributeID uuid.UUID `gorm:"type:char(36);uniqueIndex:idx_variant_attribute;index;not null" json:"attributeId"`
+	Value       string    `gorm:"not null" json:"value"`
+}
+
+// TableName specifies the table name for the VariantAttribute model.
+func (VariantAttribute) TableName() string {
+	return "product_variant_attributes"
+}
+
+// KitComponent is a product a kit is made of. Quantity is how much of the
+// component's base unit one kit takes.
+type KitComponent struct {
+	ID          uuid.UUID         `gorm:"type:char(36);primaryKey" json:"id"`
+	KitID       uuid.UUID         `gorm:"type:char(36);uniqueIndex:idx_kit_component;not null" json:"kitId"`
+	ComponentID uuid.UUID         `gorm:"type:char(36);uniqueIndex:idx_kit_component;index;not null" json:"componentId"`
+	Quantity    quantity.Quantity `gorm:"not null" json:"quantity"`
+
+	Component Product `gorm:"foreignKey:ComponentID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:RESTRICT;" json:"-"`
+}
+
+// TableName specifies the table name for the KitComponent model.
+func (KitComponent) TableName() string {
+	return "product_kit_components"
+}
+
+// Image is a picture of a product. The uploaded file and a thumbnail of it
+// are kept in blob storage; Checksum is the SHA-256 of the file. Images are
+// ordered by Position, from 0, and never change once uploaded, so their
+// URLs can be cached for good. URL and ThumbnailURL are filled in when the
+// image is read.
+type Image struct {
+	ID                   uuid.UUID `gorm:"type:char(36);primaryKey" json:"id"`
+	ProductID            uuid.UUID `gorm:"type:char(36);index;not null" json:"productId"`
+	Position             int       `gorm:"not null" json:"position"`
+	ContentType          string    `gorm:"not null" json:"contentType"`
+	Size                 int64     `gorm:"not null" json:"size"`
+	Width                int       `gorm:"not null" json:"width"`
+	Height               int       `gorm:"not null" json:"height"`
+	Checksum             string    `gorm:"not null" json:"checksum"`
+	ThumbnailContentType string    `gorm:"not null" json:"-"`
+	URL                  string    `gorm:"-" json:"url"`
+	ThumbnailURL         string    `gorm:"-" json:"thumbnailUrl"`
+
+	common.AuditFields
+}
+
+// TableName specifies the table name for the Image model.
+func (Image) TableName() string {
+	return "product_images"
+}
+
+// CreateProductRequest represents a request to create a product. Without a
+// base unit, the product is counted in each; without a type, it is a
+// standard product. Only kits have components.
 type CreateProductRequest struct {
-	Name        string    `json:"name" validate:"required,min=1,max=255"`
-	Description string    `json:"description" validate:"max=1000"`
-	IsActive    bool      `json:"isActive"`
-	CategoryID  uuid.UUID `json:"categoryId" validate:"required"`
+	Name         string             `json:"name" validate:"required,min=1,max=255"`
+	SKU          *string            `json:"sku" validate:"omitempty,min=1,max=64"`
+	Description  string             `json:"description" validate:"max=1000"`
+	IsActive     bool               `json:"isActive"`
+	Type         ProductType        `json:"type" validate:"omitempty,oneof=standard kit"`
+	CategoryID   uuid.UUID          `json:"categoryId" validate:"required"`
+	TaxClassID   *uuid.UUID         `json:"taxClassId"`
+	Barcodes     []BarcodeRequest   `json:"barcodes" validate:"dive"`
+	BaseUnit     string             `json:"baseUnit" validate:"max=20"`
+	Fractional   bool               `json:"fractional"`
+	PurchaseUnit string             `json:"purchaseUnit" validate:"max=20"`
+	SalesUnit    string             `json:"salesUnit" validate:"max=20"`
+	Units        []UnitRequest      `json:"units" validate:"dive"`
+	Components   []ComponentRequest `json:"components" validate:"dive"`
 }
 
-// UpdateProductRequest represents a request to update a product.
+// UpdateProductRequest represents a request to update a product. The
+// barcodes, units and components given replace the product's. The
+// product's variants take its new name, description, tax class and units.
 type UpdateProductRequest struct {
-	Name        string    `json:"name" validate:"required,min=1,max=255"`
-	Description string    `json:"description" validate:"max=1000"`
-	IsActive    bool      `json:"isActive"`
-	CategoryID  uuid.UUID `json:"categoryId" validate:"required"`
+	Name         string             `json:"name" validate:"required,min=1,max=255"`
+	SKU          *string            `json:"sku" validate:"omitempty,min=1,max=64"`
+	Description  string             `json:"description" validate:"max=1000"`
+	IsActive     bool               `json:"isActive"`
+	CategoryID   uuid.UUID          `json:"categoryId" validate:"required"`
+	TaxClassID   *uuid.UUID         `json:"taxClassId"`
+	Barcodes     []BarcodeRequest   `json:"barcodes" validate:"dive"`
+	BaseUnit     string             `json:"baseUnit" validate:"max=20"`
+	Fractional   bool               `json:"fractional"`
+	PurchaseUnit string             `json:"purchaseUnit" validate:"max=20"`
+	SalesUnit    string             `json:"salesUnit" validate:"max=20"`
+	Units        []UnitRequest      `json:"units" validate:"dive"`
+	Components   []ComponentRequest `json:"components" validate:"dive"`
+}
+
+// UnitRequest represents a unit of a product.
+type UnitRequest struct {
+	Name   string            `json:"name" validate:"required,max=20"`
+	Factor quantity.Quantity `json:"factor" validate:"required,gt=0"`
+}
+
+// ComponentRequest represents a component of a kit and the quantity one
+// kit takes, in Unit, which defaults to the component's base unit.
+type ComponentRequest struct {
+	ProductID uuid.UUID         `json:"productId" validate:"required"`
+	Unit      string            `json:"unit" validate:"max=20"`
+	Quantity  quantity.Quantity `json:"quantity" validate:"required,gt=0"`
+}
+
+// CreateVariantRequest represents a request to add a variant to a product.
+// Attributes maps the name of each attribute of the product's category to
+// the variant's value.
+type CreateVariantRequest struct {
+	SKU        *string           `json:"sku" validate:"omitempty,min=1,max=64"`
+	IsActive   bool              `json:"isActive"`
+	Barcodes   []BarcodeRequest  `json:"barcodes" validate:"dive"`
+	Attributes map[string]string `json:"attributes" validate:"required,min=1"`
+}
+
+// UpdateVariantRequest represents a request to update a variant. The
+// barcodes given replace the variant's barcodes.
+type UpdateVariantRequest struct {
+	SKU        *string           `json:"sku" validate:"omitempty,min=1,max=64"`
+	IsActive   bool              `json:"isActive"`
+	Barcodes   []BarcodeRequest  `json:"barcodes" validate:"dive"`
+	Attributes map[string]string `json:"attributes" validate:"required,min=1"`
+}
+
+// BarcodeRequest represents a barcode of a product. Embedded is set for a
+// variable measure barcode.
+type BarcodeRequest struct {
+	Code     string        `json:"code" validate:"required"`
+	Embedded EmbeddedValue `json:"embedded" validate:"omitempty,oneof=price weight"`
+}
+
+// ReorderImagesRequest represents the new order of a product's images,
+// which must list each of them once. The first becomes the primary image.
+type ReorderImagesRequest struct {
+	ImageIDs []uuid.UUID `json:"imageIds" validate:"required,min=1"`
+}
+
+// BarcodeMatch is a product found by one of its barcodes.
+type BarcodeMatch struct {
+	Product
+
+	BarcodeGTIN     string
+	BarcodeEmbedded EmbeddedValue
+}
+
+// BarcodeLookup is the product a scanned code identifies. For a variable
+// measure code, Price (in the store currency) or WeightGrams is the value it
+// carries.
+type BarcodeLookup struct {
+	Code        string        `json:"code"`
+	Product     *Product      `json:"product"`
+	Embedded    EmbeddedValue `json:"embedded"`
+	Price       *money.Amount `json:"price"`
+	WeightGrams *int          `json:"weightGrams"`
 }
 
 // ProductCategory represents a product category in the system.
+// Products in the category are taxed under its tax class; a category
+// without one is untaxed. Its attributes are the ways the variants of its
+// products differ.
+//
+// Categories form a tree: ParentID is the category a subcategory sits
+// under, and is nil for a top-level category. Moving a category moves its
+// subcategories with it. Like the product's, ParentID has no foreign key.
 type ProductCategory struct {
-	ID          uuid.UUID `gorm:"type:char(36);primarykey" json:"id"`
-	Name        string    `gorm:"unique;not null" json:"name"`
-	Description string    `json:"description"`
+	ID          uuid.UUID   `gorm:"type:char(36);primarykey" json:"id"`
+	Name        string      `gorm:"unique;not null" json:"name"`
+	Description string      `json:"description"`
+	TaxClassID  *uuid.UUID  `gorm:"type:char(36);index" json:"taxClassId"`
+	ParentID    *uuid.UUID  `gorm:"type:char(36);index" json:"parentId"`
+	Attributes  []Attribute `gorm:"foreignKey:CategoryID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"attributes,omitempty"`
 	common.AuditFields
 }
 
-// CreateProductCategoryRequest represents a request to create a product category.
+// CategoryNode is a category in the category tree, with its
+// subcategories.
+type CategoryNode struct {
+	ProductCategory
+
+	Children []CategoryNode `json:"children"`
+}
+
+// Attribute is a way the variants of a category's products differ, such as
+// size or colour. Each variant takes one of its values. Attributes are
+// listed by Position, and values in the order they were given.
+type Attribute struct {
+	ID         uuid.UUID        `gorm:"type:char(36);primaryKey" json:"id"`
+	CategoryID uuid.UUID        `gorm:"type:char(36);uniqueIndex:idx_category_attribute;not null" json:"categoryId"`
+	Name       string           `gorm:"uniqueIndex:idx_category_attribute;not null" json:"name"`
+	Position   int              `gorm:"not null;default:0" json:"position"`
+	Values     []AttributeValue `gorm:"foreignKey:AttributeID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"values"`
+}
+
+// TableName specifies the table name for the Attribute model.
+func (Attribute) TableName() string {
+	return "product_attributes"
+}
+
+// AttributeValue is one of the values of an attribute.
+type AttributeValue struct {
+	ID          uuid.UUID `gorm:"type:char(36);primaryKey" json:"id"`
+	AttributeID uuid.UUID `gorm:"type:char(36);uniqueIndex:idx_attribute_value;not null" json:"attributeId"`
+	Value       string    `gorm:"uniqueIndex:idx_attribute_value;not null" json:"value"`
+	Position    int       `gorm:"not null;default:0" json:"position"`
+}
+
+// TableName specifies the table name for the AttributeValue model.
+func (AttributeValue) TableName() string {
+	return "product_attribute_values"
+}
+
+// CreateProductCategoryRequest represents a request to create a product
+// category, under ParentID when it is a subcategory.
 type CreateProductCategoryRequest struct {
-	Name        string `json:"name" validate:"required,min=1,max=255"`
-	Description string `json:"description" validate:"max=1000"`
+	Name        string     `json:"name" validate:"required,min=1,max=255"`
+	Description string     `json:"description" validate:"max=1000"`
+	TaxClassID  *uuid.UUID `json:"taxClassId"`
+	ParentID    *uuid.UUID `json:"parentId"`
 }
 
-// UpdateProductCategoryRequest represents a request to update a product category.
+// UpdateProductCategoryRequest represents a request to update a product
+// category. Categories are moved with MoveCategoryRequest.
 type UpdateProductCategoryRequest struct {
-	Name        string `json:"name" validate:"required,min=1,max=255"`
-	Description string `json:"description" validate:"max=1000"`
+	Name        string     `json:"name" validate:"required,min=1,max=255"`
+	Description string     `json:"description" validate:"max=1000"`
+	TaxClassID  *uuid.UUID `json:"taxClassId"`
+}
+
+// MoveCategoryRequest represents a request to move a category, with its
+// subcategories, under another category, or to the top of the tree when
+// ParentID is nil.
+type MoveCategoryRequest struct {
+	ParentID *uuid.UUID `json:"parentId"`
+}
+
+// CreateAttributeRequest represents a request to add an attribute to a
+// product category.
+type CreateAttributeRequest struct {
+	Name     string   `json:"name" validate:"required,max=50"`
+	Position int      `json:"position" validate:"gte=0"`
+	Values   []string `json:"values" validate:"required,min=1,dive,required,max=50"`
+}
+
+// UpdateAttributeRequest represents a request to update an attribute. The
+// values given replace the attribute's values; values variants have cannot
+// be removed.
+type UpdateAttributeRequest struct {
+	Name     string   `json:"name" validate:"required,max=50"`
+	Position int      `json:"position" validate:"gte=0"`
+	Values   []string `json:"values" validate:"required,min=1,dive,required,max=50"`
 }
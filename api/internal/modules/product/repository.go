@@ -4,6 +4,8 @@ import (
 	"github.com/google/uuid"
 	"github.com/shanmugharajk/go-react-web-api/api/internal/db"
 	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // ProductRepository handles data access for products.
@@ -16,49 +18,351 @@ func NewProductRepository(database *db.DB) *ProductRepository {
 	return &ProductRepository{db: database}
 }
 
-// FindAll retrieves all products.
-func (r *ProductRepository) FindAll() ([]Product, error) {
+// FindAll retrieves all products with their barcodes, units, attribute
+// values, components and images, or only those in the given categories
+// when any are given.
+func (r *ProductRepository) FindAll(categoryIDs []uuid.UUID) ([]Product, error) {
 	var products []Product
-	if err := r.db.Find(&products).Error; err != nil {
+	query := r.db.Preload("Barcodes").Preload("Units").Preload("Attributes").Preload("Components").Preload("Images", byPosition)
+	if len(categoryIDs) > 0 {
+		query = query.Where("category_id IN ?", categoryIDs)
+	}
+	if err := query.Find(&products).Error; err != nil {
 		return nil, err
 	}
 	return products, nil
 }
 
-// FindByID retrieves a product by ID.
+// FindByID retrieves a product by ID with its barcodes, units, attribute
+// values, components and images.
 func (r *ProductRepository) FindByID(id uuid.UUID) (*Product, error) {
 	var product Product
-	if err := r.db.First(&product, id).Error; err != nil {
+	if err := r.db.Preload("Barcodes").Preload("Units").Preload("Attributes").Preload("Components").Preload("Images", byPosition).First(&product, id).Error; err != nil {
+		return nil, err
+	}
+	return &product, nil
+}
+
+// FindBySKU retrieves a product by SKU.
+func (r *ProductRepository) FindBySKU(sku string) (*Product, error) {
+	var product Product
+	if err := r.db.Preload("Barcodes").Preload("Units").Preload("Attributes").Preload("Components").Preload("Images", byPosition).Where("sku = ?", sku).First(&product).Error; err != nil {
 		return nil, err
 	}
 	return &product, nil
 }
 
-// Create creates a new product.
+// FindVariants retrieves the variants of a product by name, with their
+// barcodes, units, attribute values, components and images.
+func (r *ProductRepository) FindVariants(parentID uuid.UUID) ([]Product, error) {
+	variants := []Product{}
+	err := r.db.
+		Preload("Barcodes").Preload("Units").Preload("Attributes").Preload("Components").Preload("Images", byPosition).
+		Where("parent_id = ?", parentID).
+		Order("name ASC").
+		Find(&variants).Error
+	if err != nil {
+		return nil, err
+	}
+	return variants, nil
+}
+
+// CountVariants returns how many variants a product has.
+func (r *ProductRepository) CountVariants(parentID uuid.UUID) (int64, error) {
+	var count int64
+	if err := r.db.Model(&Product{}).Where("parent_id = ?", parentID).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// CountKits returns how many kits a product is a component of.
+func (r *ProductRepository) CountKits(componentID uuid.UUID) (int64, error) {
+	var count int64
+	if err := r.db.Model(&KitComponent{}).Where("component_id = ?", componentID).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// FindBarcodes retrieves the barcodes with the given GTINs.
+func (r *ProductRepository) FindBarcodes(gtins []string) ([]Barcode, error) {
+	var barcodes []Barcode
+	if err := r.db.Where("gtin IN ?", gtins).Find(&barcodes).Error; err != nil {
+		return nil, err
+	}
+	return barcodes, nil
+}
+
+// FindByBarcode retrieves the products whose barcodes have any of the given
+// GTINs, with the barcode each was found by, in a single query on the GTIN
+// index.
+func (r *ProductRepository) FindByBarcode(gtins []string) ([]BarcodeMatch, error) {
+	var matches []BarcodeMatch
+	err := r.db.
+		Table("products").
+		Select("products.*, product_barcodes.gtin AS barcode_gtin, product_barcodes.embedded AS barcode_embedded").
+		Joins("JOIN product_barcodes ON product_barcodes.product_id = products.id").
+		Where("product_barcodes.gtin IN ?", gtins).
+		Scan(&matches).Error
+	if err != nil {
+		return nil, err
+	}
+	return matches, nil
+}
+
+// FindByIDs retrieves the products with the given IDs.
+func (r *ProductRepository) FindByIDs(ids []uuid.UUID) ([]Product, error) {
+	var products []Product
+	if err := r.db.Where("id IN ?", ids).Find(&products).Error; err != nil {
+		return nil, err
+	}
+	return products, nil
+}
+
+// TaxClassIDOf returns the tax class a product is sold under: its own if it
+// overrides its category's, otherwise its category's. It is nil for untaxed
+// products.
+func (r *ProductRepository) TaxClassIDOf(product *Product) (*uuid.UUID, error) {
+	if product.TaxClassID != nil {
+		return product.TaxClassID, nil
+	}
+	var category ProductCategory
+	if err := r.db.Select("tax_class_id").First(&category, product.CategoryID).Error; err != nil {
+		return nil, err
+	}
+	return category.TaxClassID, nil
+}
+
+// Create creates a new product with its barcodes, units, attribute values
+// and components.
 func (r *ProductRepository) Create(product *Product) error {
 	if product.ID == uuid.Nil {
 		product.ID = uuid.New()
 	}
+	for i := range product.Barcodes {
+		if product.Barcodes[i].ID == uuid.Nil {
+			product.Barcodes[i].ID = uuid.New()
+		}
+	}
+	for i := range product.Units {
+		if product.Units[i].ID == uuid.Nil {
+			product.Units[i].ID = uuid.New()
+		}
+	}
+	for i := range product.Attributes {
+		if product.Attributes[i].ID == uuid.Nil {
+			product.Attributes[i].ID = uuid.New()
+		}
+	}
+	for i := range product.Components {
+		if product.Components[i].ID == uuid.Nil {
+			product.Components[i].ID = uuid.New()
+		}
+	}
 	return r.db.Create(product).Error
 }
 
-// Update updates an existing product.
-func (r *ProductRepository) Update(product *Product) error {
-	return r.db.Save(product).Error
+// Update updates an existing product and replaces its barcodes, units,
+// attribute values and components, along with those of the variants given.
+func (r *ProductRepository) Update(product *Product, variants []Product) error {
+	return r.db.Transaction(func(tx *db.DB) error {
+		if err := save(tx, product); err != nil {
+			return err
+		}
+		for i := range variants {
+			if err := save(tx, &variants[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
 }
 
-// Delete deletes a product by ID.
-func (r *ProductRepository) Delete(id uuid.UUID) error {
-	result := r.db.Delete(&Product{}, id)
-	if result.Error != nil {
-		return result.Error
+// save saves a product and replaces its barcodes, units, attribute values
+// and components.
+func save(tx *db.DB, product *Product) error {
+	if err := tx.Omit(clause.Associations).Save(product).Error; err != nil {
+		return err
 	}
-	if result.RowsAffected == 0 {
-		return errors.ErrNotFound
+	if err := tx.Where("product_id = ?", product.ID).Delete(&Barcode{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("product_id = ?", product.ID).Delete(&Unit{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("product_id = ?", product.ID).Delete(&VariantAttribute{}).Error; err != nil {
+		return err
+	}
+	if err := tx.Where("kit_id = ?", product.ID).Delete(&KitComponent{}).Error; err != nil {
+		return err
+	}
+	if len(product.Barcodes) > 0 {
+		for i := range product.Barcodes {
+			product.Barcodes[i].ID = uuid.New()
+			product.Barcodes[i].ProductID = product.ID
+		}
+		if err := tx.Create(&product.Barcodes).Error; err != nil {
+			return err
+		}
+	}
+	if len(product.Units) > 0 {
+		for i := range product.Units {
+			product.Units[i].ID = uuid.New()
+			product.Units[i].ProductID = product.ID
+		}
+		if err := tx.Create(&product.Units).Error; err != nil {
+			return err
+		}
+	}
+	if len(product.Attributes) > 0 {
+		for i := range product.Attributes {
+			product.Attributes[i].ID = uuid.New()
+			product.Attributes[i].ProductID = product.ID
+		}
+		if err := tx.Create(&product.Attributes).Error; err != nil {
+			return err
+		}
+	}
+	if len(product.Components) > 0 {
+		for i := range product.Components {
+			product.Components[i].ID = uuid.New()
+			product.Components[i].KitID = product.ID
+		}
+		if err := tx.Create(&product.Components).Error; err != nil {
+			return err
+		}
 	}
 	return nil
 }
 
+// Delete deletes a product and its barcodes, units, attribute values,
+// components and images by ID, leaving the image files to the caller. A
+// product with variants, or that is a component of a kit, cannot be
+// deleted.
+func (r *ProductRepository) Delete(id uuid.UUID) error {
+	return r.db.Transaction(func(tx *db.DB) error {
+		products := NewProductRepository(tx)
+		variants, err := products.CountVariants(id)
+		if err != nil {
+			return err
+		}
+		if variants > 0 {
+			return ErrHasVariants
+		}
+		kits, err := products.CountKits(id)
+		if err != nil {
+			return err
+		}
+		if kits > 0 {
+			return ErrKitComponent
+		}
+		if err := tx.Where("product_id = ?", id).Delete(&Barcode{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("product_id = ?", id).Delete(&Unit{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("product_id = ?", id).Delete(&VariantAttribute{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("kit_id = ?", id).Delete(&KitComponent{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("product_id = ?", id).Delete(&Image{}).Error; err != nil {
+			return err
+		}
+		result := tx.Delete(&Product{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.ErrNotFound
+		}
+		return nil
+	})
+}
+
+// ImageRepository handles data access for product images. The image files
+// themselves are kept in blob storage.
+type ImageRepository struct {
+	db *db.DB
+}
+
+// NewImageRepository creates a new product image repository.
+func NewImageRepository(database *db.DB) *ImageRepository {
+	return &ImageRepository{db: database}
+}
+
+// FindByProductID retrieves the images of a product in order.
+func (r *ImageRepository) FindByProductID(productID uuid.UUID) ([]Image, error) {
+	images := []Image{}
+	if err := r.db.Where("product_id = ?", productID).Order("position ASC").Find(&images).Error; err != nil {
+		return nil, err
+	}
+	return images, nil
+}
+
+// FindByID retrieves an image of a product by ID.
+func (r *ImageRepository) FindByID(productID, id uuid.UUID) (*Image, error) {
+	var image Image
+	if err := r.db.Where("product_id = ?", productID).First(&image, id).Error; err != nil {
+		return nil, err
+	}
+	return &image, nil
+}
+
+// Create adds an image after the product's other images.
+func (r *ImageRepository) Create(image *Image) error {
+	if image.ID == uuid.Nil {
+		image.ID = uuid.New()
+	}
+	return r.db.Transaction(func(tx *db.DB) error {
+		var count int64
+		if err := tx.Model(&Image{}).Where("product_id = ?", image.ProductID).Count(&count).Error; err != nil {
+			return err
+		}
+		image.Position = int(count)
+		if err := tx.Create(image).Error; err != nil {
+			return err
+		}
+		image.setURLs()
+		return nil
+	})
+}
+
+// Reorder sets the positions of a product's images to their order in ids.
+func (r *ImageRepository) Reorder(productID uuid.UUID, ids []uuid.UUID, userID uuid.UUID) error {
+	return r.db.Transaction(func(tx *db.DB) error {
+		for i, id := range ids {
+			err := tx.Model(&Image{}).
+				Where("id = ? AND product_id = ?", id, productID).
+				Updates(map[string]any{"position": i, "updated_by": userID}).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Delete deletes an image of a product and closes the gap it leaves in the
+// order of the rest.
+func (r *ImageRepository) Delete(productID, id uuid.UUID) error {
+	return r.db.Transaction(func(tx *db.DB) error {
+		var image Image
+		if err := tx.Where("product_id = ?", productID).First(&image, id).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&image).Error; err != nil {
+			return err
+		}
+		return tx.Model(&Image{}).
+			Where("product_id = ? AND position > ?", productID, image.Position).
+			Update("position", gorm.Expr("position - 1")).Error
+	})
+}
+
 // CategoryRepository handles data access for product categories.
 type CategoryRepository struct {
 	db *db.DB
@@ -69,24 +373,33 @@ func NewCategoryRepository(database *db.DB) *CategoryRepository {
 	return &CategoryRepository{db: database}
 }
 
-// FindAll retrieves all product categories.
+// FindAll retrieves all product categories with their attributes.
 func (r *CategoryRepository) FindAll() ([]ProductCategory, error) {
 	var categories []ProductCategory
-	if err := r.db.Find(&categories).Error; err != nil {
+	if err := preloadAttributes(r.db.DB, "Attributes").Find(&categories).Error; err != nil {
 		return nil, err
 	}
 	return categories, nil
 }
 
-// FindByID retrieves a product category by ID.
+// FindByID retrieves a product category by ID with its attributes.
 func (r *CategoryRepository) FindByID(id uuid.UUID) (*ProductCategory, error) {
 	var category ProductCategory
-	if err := r.db.First(&category, id).Error; err != nil {
+	if err := preloadAttributes(r.db.DB, "Attributes").First(&category, id).Error; err != nil {
 		return nil, err
 	}
 	return &category, nil
 }
 
+// FindHierarchy retrieves the parent of every category.
+func (r *CategoryRepository) FindHierarchy() (Hierarchy, error) {
+	var categories []ProductCategory
+	if err := r.db.Select("id", "parent_id").Find(&categories).Error; err != nil {
+		return nil, err
+	}
+	return NewHierarchy(categories), nil
+}
+
 // Create creates a new product category.
 func (r *CategoryRepository) Create(category *ProductCategory) error {
 	if category.ID == uuid.Nil {
@@ -97,12 +410,16 @@ func (r *CategoryRepository) Create(category *ProductCategory) error {
 
 // Update updates an existing product category.
 func (r *CategoryRepository) Update(category *ProductCategory) error {
-	return r.db.Save(category).Error
+	return r.db.Omit(clause.Associations).Save(category).Error
 }
 
-// Delete deletes a product category by ID.
-func (r *CategoryRepository) Delete(id uuid.UUID) error {
-	result := r.db.Delete(&ProductCategory{}, id)
+// Move moves a product category, and with it its subcategories, under
+// another category, or to the top of the tree when parentID is nil.
+func (r *CategoryRepository) Move(id uuid.UUID, parentID *uuid.UUID, userID uuid.UUID) error {
+	result := r.db.Model(&ProductCategory{}).Where("id = ?", id).Updates(map[string]any{
+		"parent_id":  parentID,
+		"updated_by": userID,
+	})
 	if result.Error != nil {
 		return result.Error
 	}
@@ -111,3 +428,149 @@ func (r *CategoryRepository) Delete(id uuid.UUID) error {
 	}
 	return nil
 }
+
+// Delete deletes a product category and its attributes by ID. A category
+// with subcategories cannot be deleted.
+func (r *CategoryRepository) Delete(id uuid.UUID) error {
+	return r.db.Transaction(func(tx *db.DB) error {
+		var children int64
+		if err := tx.Model(&ProductCategory{}).Where("parent_id = ?", id).Count(&children).Error; err != nil {
+			return err
+		}
+		if children > 0 {
+			return ErrCategoryHasChildren
+		}
+		attributes := tx.Model(&Attribute{}).Select("id").Where("category_id = ?", id)
+		if err := tx.Where("attribute_id IN (?)", attributes).Delete(&AttributeValue{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("category_id = ?", id).Delete(&Attribute{}).Error; err != nil {
+			return err
+		}
+		result := tx.Delete(&ProductCategory{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.ErrNotFound
+		}
+		return nil
+	})
+}
+
+// FindAttributes retrieves the attributes of a category, in order, with
+// their values.
+func (r *CategoryRepository) FindAttributes(categoryID uuid.UUID) ([]Attribute, error) {
+	attributes := []Attribute{}
+	err := preloadAttributes(r.db.DB, "").
+		Where("category_id = ?", categoryID).
+		Order("position ASC, name ASC").
+		Find(&attributes).Error
+	if err != nil {
+		return nil, err
+	}
+	return attributes, nil
+}
+
+// FindAttribute retrieves an attribute of a category by ID with its values.
+func (r *CategoryRepository) FindAttribute(categoryID, id uuid.UUID) (*Attribute, error) {
+	var attribute Attribute
+	err := preloadAttributes(r.db.DB, "").
+		Where("category_id = ?", categoryID).
+		First(&attribute, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &attribute, nil
+}
+
+// CreateAttribute creates a new attribute with its values.
+func (r *CategoryRepository) CreateAttribute(attribute *Attribute) error {
+	if attribute.ID == uuid.Nil {
+		attribute.ID = uuid.New()
+	}
+	for i := range attribute.Values {
+		attribute.Values[i].ID = uuid.New()
+	}
+	return r.db.Create(attribute).Error
+}
+
+// UpdateAttribute updates an existing attribute and replaces its values.
+func (r *CategoryRepository) UpdateAttribute(attribute *Attribute) error {
+	return r.db.Transaction(func(tx *db.DB) error {
+		if err := tx.Omit(clause.Associations).Save(attribute).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("attribute_id = ?", attribute.ID).Delete(&AttributeValue{}).Error; err != nil {
+			return err
+		}
+		for i := range attribute.Values {
+			attribute.Values[i].ID = uuid.New()
+			attribute.Values[i].AttributeID = attribute.ID
+		}
+		return tx.Create(&attribute.Values).Error
+	})
+}
+
+// DeleteAttribute deletes an attribute and its values by ID.
+func (r *CategoryRepository) DeleteAttribute(id uuid.UUID) error {
+	return r.db.Transaction(func(tx *db.DB) error {
+		if err := tx.Where("attribute_id = ?", id).Delete(&AttributeValue{}).Error; err != nil {
+			return err
+		}
+		result := tx.Delete(&Attribute{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errors.ErrNotFound
+		}
+		return nil
+	})
+}
+
+// UsedValues returns the values of an attribute that variants have.
+func (r *CategoryRepository) UsedValues(attributeID uuid.UUID) ([]string, error) {
+	var values []string
+	err := r.db.Model(&VariantAttribute{}).
+		Distinct("value").
+		Where("attribute_id = ?", attributeID).
+		Pluck("value", &values).Error
+	if err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+// HasVariants reports whether any product in a category has variants.
+func (r *CategoryRepository) HasVariants(categoryID uuid.UUID) (bool, error) {
+	var count int64
+	err := r.db.Model(&Product{}).
+		Where("category_id = ? AND parent_id IS NOT NULL", categoryID).
+		Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// byPosition orders preloaded rows by their position.
+func byPosition(db *gorm.DB) *gorm.DB {
+	return db.Order("position ASC")
+}
+
+// preloadAttributes preloads attributes in order with their values in
+// order. The attributes are the association at path, or the model queried
+// when path is empty.
+func preloadAttributes(query *gorm.DB, path string) *gorm.DB {
+	values := "Values"
+	if path != "" {
+		query = query.Preload(path, func(db *gorm.DB) *gorm.DB {
+			return db.Order("position ASC, name ASC")
+		})
+		values = path + ".Values"
+	}
+	return query.Preload(values, func(db *gorm.DB) *gorm.DB {
+		return db.Order("position ASC")
+	})
+}
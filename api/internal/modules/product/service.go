@@ -1,26 +1,58 @@
 package product
 
 import (
+	"bytes"
+	"cmp"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+
 	"github.com/google/uuid"
 	"github.com/shanmugharajk/go-react-web-api/api/internal/common"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/config"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/db"
 	"github.com/shanmugharajk/go-react-web-api/api/internal/modules/auth"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/modules/tax"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/blob"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/errors"
 	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/logger"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/money"
 	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/validator"
 )
 
 // ProductService handles business logic for products.
 type ProductService struct {
-	repo *ProductRepository
+	repo       *ProductRepository
+	categories *CategoryRepository
+	taxClasses *tax.ClassRepository
+	blobs      blob.Store
 }
 
-// NewProductService creates a new product service.
-func NewProductService(repo *ProductRepository) *ProductService {
-	return &ProductService{repo: repo}
+// NewProductService creates a new product service. Image files of deleted
+// products are removed from blobs.
+func NewProductService(repo *ProductRepository, categories *CategoryRepository, taxClasses *tax.ClassRepository, blobs blob.Store) *ProductService {
+	return &ProductService{repo: repo, categories: categories, taxClasses: taxClasses, blobs: blobs}
 }
 
-// GetAll retrieves all products.
-func (s *ProductService) GetAll() ([]Product, error) {
-	return s.repo.FindAll()
+// GetAll retrieves all products, or those in a category and its
+// subcategories when one is given.
+func (s *ProductService) GetAll(categoryID *uuid.UUID) ([]Product, error) {
+	if categoryID == nil {
+		return s.repo.FindAll(nil)
+	}
+
+	if _, err := s.categories.FindByID(*categoryID); err != nil {
+		if errors.IsNotFound(err) {
+			return nil, validator.ValidationErrors{{Field: "categoryId", Message: "category not found"}}
+		}
+		return nil, err
+	}
+	hierarchy, err := s.categories.FindHierarchy()
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.FindAll(hierarchy.Subtree(*categoryID))
 }
 
 // GetByID retrieves a product by ID.
@@ -28,22 +60,100 @@ func (s *ProductService) GetByID(id uuid.UUID) (*Product, error) {
 	return s.repo.FindByID(id)
 }
 
-// Create creates a new product.
+// GetBySKU retrieves a product by SKU.
+func (s *ProductService) GetBySKU(sku string) (*Product, error) {
+	return s.repo.FindBySKU(sku)
+}
+
+// Lookup finds the product a scanned barcode identifies. A variable measure
+// code that is not itself registered is matched by its item code, and the
+// price or weight it carries is returned with the product.
+func (s *ProductService) Lookup(code string) (*BarcodeLookup, error) {
+	barcodeType, gtin, ok := parseBarcode(code)
+	if !ok {
+		return nil, validator.ValidationErrors{{Field: "barcode", Message: invalidBarcodeMessage}}
+	}
+
+	gtins := []string{gtin}
+	if isVariableMeasure(barcodeType, code) {
+		_, itemGTIN, _ := parseBarcode(variableMeasureItem(code))
+		gtins = append(gtins, itemGTIN)
+	}
+
+	matches, err := s.repo.FindByBarcode(gtins)
+	if err != nil {
+		return nil, err
+	}
+
+	// A registered code wins over a variable measure item code.
+	var found *BarcodeMatch
+	for i, m := range matches {
+		if m.BarcodeGTIN == gtin {
+			found = &matches[i]
+			break
+		}
+		if m.BarcodeEmbedded != "" {
+			found = &matches[i]
+		}
+	}
+	if found == nil {
+		return nil, errors.ErrNotFound
+	}
+
+	lookup := &BarcodeLookup{Code: code, Product: &found.Product, Embedded: found.BarcodeEmbedded}
+	if found.BarcodeGTIN != gtin {
+		value := variableMeasureValue(code)
+		switch found.BarcodeEmbedded {
+		case EmbeddedPrice:
+			price := money.Amount(value)
+			lookup.Price = &price
+		case EmbeddedWeight:
+			lookup.WeightGrams = &value
+		}
+	}
+	return lookup, nil
+}
+
+// Create creates a new product, or a kit of the components given.
 func (s *ProductService) Create(req CreateProductRequest, user *auth.User) (*Product, error) {
 	if err := validator.Struct(req); err != nil {
 		return nil, err
 	}
+	if err := checkTaxClass(s.taxClasses, req.TaxClassID); err != nil {
+		return nil, err
+	}
+
+	barcodes, err := buildBarcodes(req.Barcodes)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkUnique(uuid.Nil, req.SKU, barcodes); err != nil {
+		return nil, err
+	}
+	productType := cmp.Or(req.Type, TypeStandard)
+	components, err := buildComponents(s.repo, productType, req.Components)
+	if err != nil {
+		return nil, err
+	}
 
 	product := &Product{
 		Name:        req.Name,
+		SKU:         req.SKU,
 		Description: req.Description,
 		IsActive:    req.IsActive,
+		Type:        productType,
 		CategoryID:  req.CategoryID,
+		TaxClassID:  req.TaxClassID,
+		Barcodes:    barcodes,
+		Components:  components,
 		AuditFields: common.AuditFields{
 			CreatedBy: user.ID,
 			UpdatedBy: user.ID,
 		},
 	}
+	if err := assignUnits(product, req.BaseUnit, req.Fractional, req.PurchaseUnit, req.SalesUnit, req.Units); err != nil {
+		return nil, err
+	}
 
 	if err := s.repo.Create(product); err != nil {
 		return nil, err
@@ -52,48 +162,407 @@ func (s *ProductService) Create(req CreateProductRequest, user *auth.User) (*Pro
 	return product, nil
 }
 
-// Update updates an existing product.
+// Update updates an existing product and passes what its variants take from
+// it on to them. Variants themselves are updated with UpdateVariant.
 func (s *ProductService) Update(id uuid.UUID, req UpdateProductRequest, user *auth.User) (*Product, error) {
 	if err := validator.Struct(req); err != nil {
 		return nil, err
 	}
+	if err := checkTaxClass(s.taxClasses, req.TaxClassID); err != nil {
+		return nil, err
+	}
+
+	barcodes, err := buildBarcodes(req.Barcodes)
+	if err != nil {
+		return nil, err
+	}
 
 	product, err := s.repo.FindByID(id)
 	if err != nil {
 		return nil, err
 	}
+	if product.IsVariant() {
+		return nil, ErrUpdateVariant
+	}
+	if err := s.checkUnique(product.ID, req.SKU, barcodes); err != nil {
+		return nil, err
+	}
+
+	variants, err := s.repo.FindVariants(product.ID)
+	if err != nil {
+		return nil, err
+	}
+	if len(variants) > 0 && req.CategoryID != product.CategoryID {
+		return nil, ErrVariantCategory
+	}
+	components, err := buildComponents(s.repo, product.Type, req.Components)
+	if err != nil {
+		return nil, err
+	}
 
 	product.Name = req.Name
+	product.SKU = req.SKU
 	product.Description = req.Description
 	product.IsActive = req.IsActive
 	product.CategoryID = req.CategoryID
+	product.TaxClassID = req.TaxClassID
+	product.Barcodes = barcodes
+	product.Components = components
 	product.UpdatedBy = user.ID
+	if err := assignUnits(product, req.BaseUnit, req.Fractional, req.PurchaseUnit, req.SalesUnit, req.Units); err != nil {
+		return nil, err
+	}
+
+	if len(variants) > 0 {
+		attributes, err := s.categories.FindAttributes(product.CategoryID)
+		if err != nil {
+			return nil, err
+		}
+		for i := range variants {
+			inherit(&variants[i], product, attributes)
+			variants[i].UpdatedBy = user.ID
+		}
+	}
 
-	if err := s.repo.Update(product); err != nil {
+	if err := s.repo.Update(product, variants); err != nil {
 		return nil, err
 	}
 
 	return product, nil
 }
 
+// GetVariants retrieves the variants of a product.
+func (s *ProductService) GetVariants(id uuid.UUID) ([]Product, error) {
+	if _, err := s.repo.FindByID(id); err != nil {
+		return nil, err
+	}
+	return s.repo.FindVariants(id)
+}
+
+// CreateVariant adds a variant to a product, with a value for each of the
+// attributes of the product's category. Kits and their components cannot
+// have variants.
+func (s *ProductService) CreateVariant(parentID uuid.UUID, req CreateVariantRequest, user *auth.User) (*Product, error) {
+	if err := validator.Struct(req); err != nil {
+		return nil, err
+	}
+
+	barcodes, err := buildBarcodes(req.Barcodes)
+	if err != nil {
+		return nil, err
+	}
+
+	parent, err := s.repo.FindByID(parentID)
+	if err != nil {
+		return nil, err
+	}
+	if parent.IsVariant() {
+		return nil, ErrVariantOfVariant
+	}
+	if parent.IsKit() {
+		return nil, ErrKitVariants
+	}
+	kits, err := s.repo.CountKits(parent.ID)
+	if err != nil {
+		return nil, err
+	}
+	if kits > 0 {
+		return nil, ErrComponentVariants
+	}
+
+	attributes, values, err := s.variantAttributes(parent, uuid.Nil, req.Attributes)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkUnique(uuid.Nil, req.SKU, barcodes); err != nil {
+		return nil, err
+	}
+
+	variant := &Product{
+		SKU:        req.SKU,
+		IsActive:   req.IsActive,
+		Type:       TypeStandard,
+		Barcodes:   barcodes,
+		Attributes: values,
+		AuditFields: common.AuditFields{
+			CreatedBy: user.ID,
+			UpdatedBy: user.ID,
+		},
+	}
+	inherit(variant, parent, attributes)
+
+	if err := s.repo.Create(variant); err != nil {
+		return nil, err
+	}
+
+	return variant, nil
+}
+
+// UpdateVariant updates a variant of a product.
+func (s *ProductService) UpdateVariant(parentID, id uuid.UUID, req UpdateVariantRequest, user *auth.User) (*Product, error) {
+	if err := validator.Struct(req); err != nil {
+		return nil, err
+	}
+
+	barcodes, err := buildBarcodes(req.Barcodes)
+	if err != nil {
+		return nil, err
+	}
+
+	variant, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if variant.ParentID == nil || *variant.ParentID != parentID {
+		return nil, errors.ErrNotFound
+	}
+	parent, err := s.repo.FindByID(parentID)
+	if err != nil {
+		return nil, err
+	}
+
+	attributes, values, err := s.variantAttributes(parent, variant.ID, req.Attributes)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkUnique(variant.ID, req.SKU, barcodes); err != nil {
+		return nil, err
+	}
+
+	variant.SKU = req.SKU
+	variant.IsActive = req.IsActive
+	variant.Barcodes = barcodes
+	variant.Attributes = values
+	variant.UpdatedBy = user.ID
+	inherit(variant, parent, attributes)
+
+	if err := s.repo.Update(variant, nil); err != nil {
+		return nil, err
+	}
+
+	return variant, nil
+}
+
+// variantAttributes validates the attribute values of a variant of parent
+// and checks no other variant than the one with the given ID, which is
+// uuid.Nil for a new variant, has the same values. It returns the
+// attributes of the parent's category with the values.
+func (s *ProductService) variantAttributes(parent *Product, id uuid.UUID, values map[string]string) ([]Attribute, []VariantAttribute, error) {
+	attributes, err := s.categories.FindAttributes(parent.CategoryID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(attributes) == 0 {
+		return nil, nil, ErrNoAttributes
+	}
+
+	built, err := buildVariantAttributes(attributes, values)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	siblings, err := s.repo.FindVariants(parent.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, sibling := range siblings {
+		if sibling.ID != id && sameAttributes(sibling.Attributes, built) {
+			return nil, nil, ErrVariantExists
+		}
+	}
+	return attributes, built, nil
+}
+
 // Delete deletes a product by ID.
 func (s *ProductService) Delete(id uuid.UUID, user *auth.User) error {
+	product, err := s.repo.FindByID(id)
+	if err != nil {
+		return err
+	}
 	if err := s.repo.Delete(id); err != nil {
 		return err
 	}
+	for _, image := range product.Images {
+		deleteImageFiles(s.blobs, &image)
+	}
 
 	logger.Info("product deleted", "product_id", id, "deleted_by", user.ID)
 	return nil
 }
 
+// checkUnique ensures no other product than the one with the given ID,
+// which is uuid.Nil for a new product, has the SKU or any of the barcodes.
+func (s *ProductService) checkUnique(id uuid.UUID, sku *string, barcodes []Barcode) error {
+	if sku != nil {
+		existing, err := s.repo.FindBySKU(*sku)
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		if err == nil && existing.ID != id {
+			return errors.Newf(http.StatusConflict, errors.ErrConflict, "sku %q already exists", *sku)
+		}
+	}
+
+	if len(barcodes) == 0 {
+		return nil
+	}
+	gtins := make([]string, len(barcodes))
+	for i, b := range barcodes {
+		gtins[i] = b.GTIN
+	}
+	existing, err := s.repo.FindBarcodes(gtins)
+	if err != nil {
+		return err
+	}
+	for _, b := range existing {
+		if b.ProductID != id {
+			return errors.Newf(http.StatusConflict, errors.ErrConflict,
+				"barcode %q is already assigned to another product", b.Code)
+		}
+	}
+	return nil
+}
+
+// ImageService handles business logic for product images.
+type ImageService struct {
+	repo     *ImageRepository
+	products *ProductRepository
+	blobs    blob.Store
+	cfg      config.ImageConfig
+}
+
+// NewImageService creates a new product image service.
+func NewImageService(repo *ImageRepository, products *ProductRepository, blobs blob.Store, cfg config.ImageConfig) *ImageService {
+	return &ImageService{repo: repo, products: products, blobs: blobs, cfg: cfg}
+}
+
+// GetByProductID retrieves the images of a product in order.
+func (s *ImageService) GetByProductID(productID uuid.UUID) ([]Image, error) {
+	if _, err := s.products.FindByID(productID); err != nil {
+		return nil, err
+	}
+	return s.repo.FindByProductID(productID)
+}
+
+// Read retrieves an image of a product with the content of its file, or
+// of its thumbnail.
+func (s *ImageService) Read(productID, id uuid.UUID, thumbnail bool) (*Image, []byte, error) {
+	image, err := s.repo.FindByID(productID, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	key := imageKey(productID, id)
+	if thumbnail {
+		key = thumbnailKey(productID, id)
+	}
+	file, err := s.blobs.Get(key)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer file.Close()
+	data, err := io.ReadAll(file)
+	if err != nil {
+		return nil, nil, err
+	}
+	return image, data, nil
+}
+
+// Upload adds an image to a product after its other images, so the first
+// image uploaded is its primary image. The file is checked and thumbnailed
+// before anything is stored.
+func (s *ImageService) Upload(productID uuid.UUID, data []byte, user *auth.User) (*Image, error) {
+	if _, err := s.products.FindByID(productID); err != nil {
+		return nil, err
+	}
+	image, thumb, err := processImage(data, s.cfg)
+	if err != nil {
+		return nil, err
+	}
+	image.ID = uuid.New()
+	image.ProductID = productID
+	image.AuditFields = common.AuditFields{CreatedBy: user.ID, UpdatedBy: user.ID}
+
+	if err := s.blobs.Put(imageKey(productID, image.ID), bytes.NewReader(data)); err != nil {
+		return nil, err
+	}
+	if err := s.blobs.Put(thumbnailKey(productID, image.ID), bytes.NewReader(thumb)); err != nil {
+		deleteImageFiles(s.blobs, image)
+		return nil, err
+	}
+	if err := s.repo.Create(image); err != nil {
+		deleteImageFiles(s.blobs, image)
+		return nil, err
+	}
+
+	logger.Info("product image uploaded", "image_id", image.ID, "product_id", productID, "size", image.Size, "uploaded_by", user.ID)
+	return image, nil
+}
+
+// Reorder puts a product's images in a new order, which must list each of
+// them once. The first becomes the product's primary image.
+func (s *ImageService) Reorder(productID uuid.UUID, req ReorderImagesRequest, user *auth.User) ([]Image, error) {
+	if err := validator.Struct(req); err != nil {
+		return nil, err
+	}
+	images, err := s.GetByProductID(productID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(req.ImageIDs) != len(images) {
+		return nil, validator.ValidationErrors{{Field: "imageIds", Message: "must list each of the product's images once"}}
+	}
+	for i, id := range req.ImageIDs {
+		if slices.Contains(req.ImageIDs[:i], id) || !slices.ContainsFunc(images, func(image Image) bool { return image.ID == id }) {
+			return nil, validator.ValidationErrors{{Field: "imageIds", Message: "must list each of the product's images once"}}
+		}
+	}
+
+	if err := s.repo.Reorder(productID, req.ImageIDs, user.ID); err != nil {
+		return nil, err
+	}
+
+	logger.Info("product images reordered", "product_id", productID, "updated_by", user.ID)
+	return s.repo.FindByProductID(productID)
+}
+
+// Delete deletes an image of a product and its files. The image after it,
+// if any, takes its place in the order.
+func (s *ImageService) Delete(productID, id uuid.UUID, user *auth.User) error {
+	image, err := s.repo.FindByID(productID, id)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.Delete(productID, id); err != nil {
+		return err
+	}
+	deleteImageFiles(s.blobs, image)
+
+	logger.Info("product image deleted", "image_id", id, "product_id", productID, "deleted_by", user.ID)
+	return nil
+}
+
+// deleteImageFiles removes an image's file and thumbnail from blob
+// storage. Failures are logged rather than returned, as the image is gone
+// either way; a file left behind only takes up space.
+func deleteImageFiles(blobs blob.Store, image *Image) {
+	for _, key := range []string{imageKey(image.ProductID, image.ID), thumbnailKey(image.ProductID, image.ID)} {
+		if err := blobs.Delete(key); err != nil {
+			logger.Error("failed to delete product image file", "image_id", image.ID, "key", key, "error", err)
+		}
+	}
+}
+
 // CategoryService handles business logic for product categories.
 type CategoryService struct {
-	repo *CategoryRepository
+	db         *db.DB
+	repo       *CategoryRepository
+	taxClasses *tax.ClassRepository
 }
 
 // NewCategoryService creates a new product category service.
-func NewCategoryService(repo *CategoryRepository) *CategoryService {
-	return &CategoryService{repo: repo}
+func NewCategoryService(database *db.DB, repo *CategoryRepository, taxClasses *tax.ClassRepository) *CategoryService {
+	return &CategoryService{db: database, repo: repo, taxClasses: taxClasses}
 }
 
 // GetAll retrieves all product categories.
@@ -101,6 +570,15 @@ func (s *CategoryService) GetAll() ([]ProductCategory, error) {
 	return s.repo.FindAll()
 }
 
+// GetTree retrieves the product categories arranged as a tree.
+func (s *CategoryService) GetTree() ([]CategoryNode, error) {
+	categories, err := s.repo.FindAll()
+	if err != nil {
+		return nil, err
+	}
+	return buildTree(categories), nil
+}
+
 // GetByID retrieves a product category by ID.
 func (s *CategoryService) GetByID(id uuid.UUID) (*ProductCategory, error) {
 	return s.repo.FindByID(id)
@@ -111,10 +589,18 @@ func (s *CategoryService) Create(req CreateProductCategoryRequest, user *auth.Us
 	if err := validator.Struct(req); err != nil {
 		return nil, err
 	}
+	if err := checkTaxClass(s.taxClasses, req.TaxClassID); err != nil {
+		return nil, err
+	}
+	if err := checkParent(s.repo, req.ParentID); err != nil {
+		return nil, err
+	}
 
 	category := &ProductCategory{
 		Name:        req.Name,
 		Description: req.Description,
+		TaxClassID:  req.TaxClassID,
+		ParentID:    req.ParentID,
 		AuditFields: common.AuditFields{
 			CreatedBy: user.ID,
 			UpdatedBy: user.ID,
@@ -133,6 +619,9 @@ func (s *CategoryService) Update(id uuid.UUID, req UpdateProductCategoryRequest,
 	if err := validator.Struct(req); err != nil {
 		return nil, err
 	}
+	if err := checkTaxClass(s.taxClasses, req.TaxClassID); err != nil {
+		return nil, err
+	}
 
 	category, err := s.repo.FindByID(id)
 	if err != nil {
@@ -141,6 +630,7 @@ func (s *CategoryService) Update(id uuid.UUID, req UpdateProductCategoryRequest,
 
 	category.Name = req.Name
 	category.Description = req.Description
+	category.TaxClassID = req.TaxClassID
 	category.UpdatedBy = user.ID
 
 	if err := s.repo.Update(category); err != nil {
@@ -150,6 +640,57 @@ func (s *CategoryService) Update(id uuid.UUID, req UpdateProductCategoryRequest,
 	return category, nil
 }
 
+// Move moves a product category, with its subcategories, under another
+// category or to the top of the tree. A category cannot be moved under
+// itself or one of its subcategories.
+func (s *CategoryService) Move(id uuid.UUID, req MoveCategoryRequest, user *auth.User) (*ProductCategory, error) {
+	if err := validator.Struct(req); err != nil {
+		return nil, err
+	}
+
+	// The cycle check reads the hierarchy the move writes, so concurrent
+	// moves must not interleave between the two.
+	err := s.db.Transaction(func(tx *db.DB) error {
+		repo := NewCategoryRepository(tx)
+		if _, err := repo.FindByID(id); err != nil {
+			return err
+		}
+		if err := checkParent(repo, req.ParentID); err != nil {
+			return err
+		}
+		if req.ParentID != nil {
+			hierarchy, err := repo.FindHierarchy()
+			if err != nil {
+				return err
+			}
+			if slices.Contains(hierarchy.Subtree(id), *req.ParentID) {
+				return ErrCategoryCycle
+			}
+		}
+		return repo.Move(id, req.ParentID, user.ID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("product category moved", "category_id", id, "parent_id", req.ParentID, "moved_by", user.ID)
+	return s.repo.FindByID(id)
+}
+
+// checkParent ensures the parent of a category, when it has one, exists.
+func checkParent(repo *CategoryRepository, parentID *uuid.UUID) error {
+	if parentID == nil {
+		return nil
+	}
+	if _, err := repo.FindByID(*parentID); err != nil {
+		if errors.IsNotFound(err) {
+			return validator.ValidationErrors{{Field: "parentId", Message: "category not found"}}
+		}
+		return err
+	}
+	return nil
+}
+
 // Delete deletes a product category by ID.
 func (s *CategoryService) Delete(id uuid.UUID, user *auth.User) error {
 	if err := s.repo.Delete(id); err != nil {
@@ -159,3 +700,186 @@ func (s *CategoryService) Delete(id uuid.UUID, user *auth.User) error {
 	logger.Info("product category deleted", "category_id", id, "deleted_by", user.ID)
 	return nil
 }
+
+// CreateAttribute adds an attribute to a product category. Attributes cannot
+// be added once the category's products have variants, which would have no
+// value for them.
+func (s *CategoryService) CreateAttribute(categoryID uuid.UUID, req CreateAttributeRequest) (*Attribute, error) {
+	if err := validator.Struct(req); err != nil {
+		return nil, err
+	}
+
+	values, err := buildAttributeValues(req.Values)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.repo.FindByID(categoryID); err != nil {
+		return nil, err
+	}
+	hasVariants, err := s.repo.HasVariants(categoryID)
+	if err != nil {
+		return nil, err
+	}
+	if hasVariants {
+		return nil, ErrCategoryHasVariants
+	}
+	if err := s.checkAttributeName(categoryID, uuid.Nil, req.Name); err != nil {
+		return nil, err
+	}
+
+	attribute := &Attribute{
+		CategoryID: categoryID,
+		Name:       req.Name,
+		Position:   req.Position,
+		Values:     values,
+	}
+
+	if err := s.repo.CreateAttribute(attribute); err != nil {
+		return nil, err
+	}
+
+	return attribute, nil
+}
+
+// UpdateAttribute updates an attribute of a product category. Values that
+// variants have must be kept.
+func (s *CategoryService) UpdateAttribute(categoryID, id uuid.UUID, req UpdateAttributeRequest) (*Attribute, error) {
+	if err := validator.Struct(req); err != nil {
+		return nil, err
+	}
+
+	values, err := buildAttributeValues(req.Values)
+	if err != nil {
+		return nil, err
+	}
+
+	attribute, err := s.repo.FindAttribute(categoryID, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkAttributeName(categoryID, attribute.ID, req.Name); err != nil {
+		return nil, err
+	}
+
+	used, err := s.repo.UsedValues(attribute.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, value := range used {
+		if !slices.Contains(req.Values, value) {
+			return nil, errors.Newf(http.StatusConflict, errors.ErrConflict,
+				"value %q is used by variants", value)
+		}
+	}
+
+	attribute.Name = req.Name
+	attribute.Position = req.Position
+	attribute.Values = values
+
+	if err := s.repo.UpdateAttribute(attribute); err != nil {
+		return nil, err
+	}
+
+	return attribute, nil
+}
+
+// DeleteAttribute deletes an attribute of a product category that no
+// variant has a value for.
+func (s *CategoryService) DeleteAttribute(categoryID, id uuid.UUID, user *auth.User) error {
+	attribute, err := s.repo.FindAttribute(categoryID, id)
+	if err != nil {
+		return err
+	}
+
+	used, err := s.repo.UsedValues(attribute.ID)
+	if err != nil {
+		return err
+	}
+	if len(used) > 0 {
+		return ErrAttributeInUse
+	}
+
+	if err := s.repo.DeleteAttribute(attribute.ID); err != nil {
+		return err
+	}
+
+	logger.Info("product attribute deleted", "attribute_id", id, "category_id", categoryID, "deleted_by", user.ID)
+	return nil
+}
+
+// checkAttributeName ensures no other attribute of the category than the
+// one with the given ID, which is uuid.Nil for a new attribute, has the
+// name.
+func (s *CategoryService) checkAttributeName(categoryID, id uuid.UUID, name string) error {
+	attributes, err := s.repo.FindAttributes(categoryID)
+	if err != nil {
+		return err
+	}
+	for _, a := range attributes {
+		if a.ID != id && a.Name == name {
+			return errors.Newf(http.StatusConflict, errors.ErrConflict, "attribute %q already exists", name)
+		}
+	}
+	return nil
+}
+
+// invalidBarcodeMessage explains which barcodes are accepted.
+const invalidBarcodeMessage = "must be an EAN-8, UPC-A or EAN-13 code with a valid check digit"
+
+// buildBarcodes validates the barcodes of a product request. A variable
+// measure barcode is stored under its item code, with its value digits
+// zeroed.
+func buildBarcodes(reqs []BarcodeRequest) ([]Barcode, error) {
+	barcodes := make([]Barcode, 0, len(reqs))
+	seen := make(map[string]bool, len(reqs))
+	for i, req := range reqs {
+		code := req.Code
+		barcodeType, gtin, ok := parseBarcode(code)
+		if !ok {
+			return nil, validator.ValidationErrors{{
+				Field:   fmt.Sprintf("barcodes[%d].code", i),
+				Message: invalidBarcodeMessage,
+			}}
+		}
+		if req.Embedded != "" {
+			if !isVariableMeasure(barcodeType, code) {
+				return nil, validator.ValidationErrors{{
+					Field:   fmt.Sprintf("barcodes[%d].embedded", i),
+					Message: "only EAN-13 codes starting with 2 can carry a price or weight",
+				}}
+			}
+			code = variableMeasureItem(code)
+			_, gtin, _ = parseBarcode(code)
+		}
+		if seen[gtin] {
+			return nil, validator.ValidationErrors{{
+				Field:   fmt.Sprintf("barcodes[%d].code", i),
+				Message: "duplicate barcode",
+			}}
+		}
+		seen[gtin] = true
+
+		barcodes = append(barcodes, Barcode{
+			Code:     code,
+			GTIN:     gtin,
+			Type:     barcodeType,
+			Embedded: req.Embedded,
+		})
+	}
+	return barcodes, nil
+}
+
+// checkTaxClass ensures the tax class, if one is given, exists.
+func checkTaxClass(repo *tax.ClassRepository, id *uuid.UUID) error {
+	if id == nil {
+		return nil
+	}
+	if _, err := repo.FindByID(*id); err != nil {
+		if errors.IsNotFound(err) {
+			return validator.ValidationErrors{{Field: "taxClassId", Message: "tax class not found"}}
+		}
+		return err
+	}
+	return nil
+}
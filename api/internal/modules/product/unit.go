@@ -0,0 +1,112 @@
+package product
+
+import (
+	"fmt"
+
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/quantity"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/validator"
+)
+
+// DefaultBaseUnit is the base unit of a product created without one.
+const DefaultBaseUnit = "each"
+
+// Factor returns how many base units one of the named unit holds. The base
+// unit, or an empty name, holds one.
+func (p *Product) Factor(unit string) (quantity.Quantity, bool) {
+	if unit == "" || unit == p.BaseUnit {
+		return quantity.FromInt(1), true
+	}
+	for _, u := range p.Units {
+		if u.Name == unit {
+			return u.Factor, true
+		}
+	}
+	return 0, false
+}
+
+// ToBase converts a quantity in one of the product's units to base units.
+// An empty unit is the base unit. It fails with a validation error on
+// "unit" when the product has no such unit, and on "quantity" when a
+// product that is not fractional would be left with part of a base unit.
+func (p *Product) ToBase(unit string, q quantity.Quantity) (quantity.Quantity, error) {
+	factor, ok := p.Factor(unit)
+	if !ok {
+		return 0, validator.ValidationErrors{{
+			Field:   "unit",
+			Message: fmt.Sprintf("%q is not a unit of this product", unit),
+		}}
+	}
+
+	base := q.Mul(factor)
+	if base.Div(factor) != q {
+		return 0, validator.ValidationErrors{{
+			Field:   "quantity",
+			Message: fmt.Sprintf("cannot be converted to %s exactly", p.BaseUnit),
+		}}
+	}
+	if err := p.CheckQuantity("quantity", base); err != nil {
+		return 0, err
+	}
+	return base, nil
+}
+
+// CheckQuantity fails with a validation error on field when a quantity of
+// base units is not whole and the product is not fractional.
+func (p *Product) CheckQuantity(field string, q quantity.Quantity) error {
+	if p.Fractional || q.IsWhole() {
+		return nil
+	}
+	return validator.ValidationErrors{{
+		Field:   field,
+		Message: fmt.Sprintf("must be a whole number of %s", p.BaseUnit),
+	}}
+}
+
+// assignUnits validates the units of a product request and copies them onto
+// the product. The purchase and sales units must be the base unit or one of
+// the units, and a product that is not fractional can only have units of
+// whole base units.
+func assignUnits(product *Product, baseUnit string, fractional bool, purchaseUnit, salesUnit string, reqs []UnitRequest) error {
+	if baseUnit == "" {
+		baseUnit = DefaultBaseUnit
+	}
+
+	units := make([]Unit, 0, len(reqs))
+	seen := map[string]bool{baseUnit: true}
+	for i, req := range reqs {
+		if seen[req.Name] {
+			return validator.ValidationErrors{{
+				Field:   fmt.Sprintf("units[%d].name", i),
+				Message: "duplicate unit",
+			}}
+		}
+		seen[req.Name] = true
+		if !fractional && !req.Factor.IsWhole() {
+			return validator.ValidationErrors{{
+				Field:   fmt.Sprintf("units[%d].factor", i),
+				Message: "must be a whole number unless the product is fractional",
+			}}
+		}
+		units = append(units, Unit{Name: req.Name, Factor: req.Factor})
+	}
+
+	defaults := []struct{ field, unit string }{
+		{"purchaseUnit", purchaseUnit},
+		{"salesUnit", salesUnit},
+	}
+	for _, d := range defaults {
+		if d.unit != "" && !seen[d.unit] {
+			return validator.ValidationErrors{{
+				Field:   d.field,
+				Message: "must be the base unit or one of the product's units",
+			}}
+		}
+	}
+
+	product.BaseUnit = baseUnit
+	product.Fractional = fractional
+	product.PurchaseUnit = purchaseUnit
+	product.SalesUnit = salesUnit
+	product.Units = units
+	return nil
+}
@@ -0,0 +1,139 @@
+package product
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/errors"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/validator"
+)
+
+// ErrVariantOfVariant is returned when adding a variant to a product that
+// is itself a variant.
+var ErrVariantOfVariant = errors.New(http.StatusUnprocessableEntity, errors.ErrUnprocessable, "a variant cannot have variants of its own")
+
+// ErrNoAttributes is returned when adding a variant to a product whose
+// category has no attributes.
+var ErrNoAttributes = errors.New(http.StatusUnprocessableEntity, errors.ErrUnprocessable, "the product's category has no attributes")
+
+// ErrUpdateVariant is returned when updating a variant as a product rather
+// than through its parent.
+var ErrUpdateVariant = errors.New(http.StatusUnprocessableEntity, errors.ErrUnprocessable, "variants are updated through their parent product")
+
+// ErrHasVariants is returned when deleting a product that has variants.
+var ErrHasVariants = errors.New(http.StatusConflict, errors.ErrConflict, "product has variants")
+
+// ErrVariantCategory is returned when moving a product with variants to
+// another category, whose attributes its variants do not have.
+var ErrVariantCategory = errors.New(http.StatusConflict, errors.ErrConflict, "the category of a product with variants cannot be changed")
+
+// ErrVariantExists is returned when a product already has a variant with
+// the same attribute values.
+var ErrVariantExists = errors.New(http.StatusConflict, errors.ErrConflict, "the product already has a variant with these attributes")
+
+// ErrCategoryHasVariants is returned when adding an attribute to a category
+// whose products have variants.
+var ErrCategoryHasVariants = errors.New(http.StatusConflict, errors.ErrConflict, "attributes cannot be added to a category whose products have variants")
+
+// ErrAttributeInUse is returned when deleting an attribute variants have a
+// value for.
+var ErrAttributeInUse = errors.New(http.StatusConflict, errors.ErrConflict, "attribute is used by variants")
+
+// IsVariant reports whether the product is a variant of another.
+func (p *Product) IsVariant() bool {
+	return p.ParentID != nil
+}
+
+// buildVariantAttributes validates the attribute values of a variant
+// request against the category's attributes and returns them in attribute
+// order. Every attribute needs one of its values.
+func buildVariantAttributes(attributes []Attribute, values map[string]string) ([]VariantAttribute, error) {
+	for name := range values {
+		if !slices.ContainsFunc(attributes, func(a Attribute) bool { return a.Name == name }) {
+			return nil, validator.ValidationErrors{{
+				Field:   "attributes." + name,
+				Message: "not an attribute of the product's category",
+			}}
+		}
+	}
+
+	built := make([]VariantAttribute, 0, len(attributes))
+	for _, a := range attributes {
+		value, ok := values[a.Name]
+		if !ok || value == "" {
+			return nil, validator.ValidationErrors{{Field: "attributes." + a.Name, Message: "is required"}}
+		}
+		names := make([]string, len(a.Values))
+		for i, v := range a.Values {
+			names[i] = v.Value
+		}
+		if !slices.Contains(names, value) {
+			return nil, validator.ValidationErrors{{
+				Field:   "attributes." + a.Name,
+				Message: "must be one of: " + strings.Join(names, " "),
+			}}
+		}
+		built = append(built, VariantAttribute{AttributeID: a.ID, Value: value})
+	}
+	return built, nil
+}
+
+// sameAttributes reports whether two variants have the same values.
+func sameAttributes(a, b []VariantAttribute) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for _, x := range a {
+		if !slices.ContainsFunc(b, func(y VariantAttribute) bool {
+			return y.AttributeID == x.AttributeID && y.Value == x.Value
+		}) {
+			return false
+		}
+	}
+	return true
+}
+
+// inherit copies what a variant takes from its parent onto it, and names
+// it after the parent and its values in attribute order.
+func inherit(variant, parent *Product, attributes []Attribute) {
+	values := make([]string, 0, len(variant.Attributes))
+	for _, a := range attributes {
+		for _, v := range variant.Attributes {
+			if v.AttributeID == a.ID {
+				values = append(values, v.Value)
+			}
+		}
+	}
+
+	variant.ParentID = &parent.ID
+	variant.Name = fmt.Sprintf("%s (%s)", parent.Name, strings.Join(values, ", "))
+	variant.Description = parent.Description
+	variant.CategoryID = parent.CategoryID
+	variant.TaxClassID = parent.TaxClassID
+	variant.BaseUnit = parent.BaseUnit
+	variant.Fractional = parent.Fractional
+	variant.PurchaseUnit = parent.PurchaseUnit
+	variant.SalesUnit = parent.SalesUnit
+	variant.Units = make([]Unit, len(parent.Units))
+	for i, u := range parent.Units {
+		variant.Units[i] = Unit{Name: u.Name, Factor: u.Factor}
+	}
+}
+
+// buildAttributeValues validates the values of an attribute request,
+// keeping their order.
+func buildAttributeValues(values []string) ([]AttributeValue, error) {
+	built := make([]AttributeValue, 0, len(values))
+	for i, value := range values {
+		if slices.Contains(values[:i], value) {
+			return nil, validator.ValidationErrors{{
+				Field:   fmt.Sprintf("values[%d]", i),
+				Message: "duplicate value",
+			}}
+		}
+		built = append(built, AttributeValue{Value: value, Position: i})
+	}
+	return built, nil
+}
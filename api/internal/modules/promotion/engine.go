@@ -0,0 +1,189 @@
+package promotion
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/money"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/quantity"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/validator"
+)
+
+// Engine applies the running promotions to carts.
+type Engine struct {
+	repo *PromotionRepository
+}
+
+// NewEngine creates a new promotion engine.
+func NewEngine(repo *PromotionRepository) *Engine {
+	return &Engine{repo: repo}
+}
+
+// Apply works out the discounts the promotions running at the given time
+// give on a cart, with the coupon codes presented. Every code must belong to
+// a running promotion. A discount never takes a line below zero.
+func (e *Engine) Apply(lines []CartLine, couponCodes []string, at time.Time) (*Application, error) {
+	codes := make([]string, len(couponCodes))
+	for i, code := range couponCodes {
+		codes[i] = NormalizeCode(code)
+	}
+
+	promotions, err := e.repo.FindRunning(at.UTC(), codes)
+	if err != nil {
+		return nil, err
+	}
+
+	running := make(map[string]bool)
+	for _, p := range promotions {
+		if p.CouponCode != nil {
+			running[*p.CouponCode] = true
+		}
+	}
+	for i, code := range codes {
+		if !running[code] {
+			return nil, validator.ValidationErrors{{
+				Field:   fmt.Sprintf("couponCodes[%d]", i),
+				Message: "coupon is not valid or has expired",
+			}}
+		}
+	}
+
+	app := &Application{
+		Lines:     make([]LineResult, len(lines)),
+		Discounts: []Discount{},
+	}
+	remaining := make([]money.Amount, len(lines))
+	discounted := make([]bool, len(lines))
+	closed := make([]bool, len(lines))
+	for i, line := range lines {
+		app.Lines[i].Subtotal = line.UnitPrice.Mul(line.Quantity)
+		remaining[i] = app.Lines[i].Subtotal
+	}
+
+	for _, p := range promotions {
+		var eligible []int
+		for i, line := range lines {
+			if p.targets(line) && !closed[i] && (p.Stackable || !discounted[i]) && remaining[i] > 0 {
+				eligible = append(eligible, i)
+			}
+		}
+		if len(eligible) == 0 {
+			continue
+		}
+
+		amounts := p.discounts(lines, eligible, remaining)
+		for k, i := range eligible {
+			amount := min(amounts[k], remaining[i])
+			if amount <= 0 {
+				continue
+			}
+			remaining[i] -= amount
+			discounted[i] = true
+			closed[i] = !p.Stackable
+			app.Discounts = append(app.Discounts, Discount{
+				PromotionID: p.ID,
+				Name:        p.Name,
+				Reason:      p.reason(),
+				Line:        i,
+				Amount:      amount,
+			})
+		}
+	}
+
+	for i := range app.Lines {
+		app.Lines[i].Total = remaining[i]
+		app.Lines[i].Discount = app.Lines[i].Subtotal - remaining[i]
+		app.Subtotal += app.Lines[i].Subtotal
+		app.DiscountTotal += app.Lines[i].Discount
+		app.Total += app.Lines[i].Total
+	}
+
+	return app, nil
+}
+
+// NormalizeCode returns a coupon code in the form it is stored in.
+func NormalizeCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// targets reports whether the promotion applies to a cart line.
+func (p *Promotion) targets(line CartLine) bool {
+	switch {
+	case p.ProductID != nil:
+		return *p.ProductID == line.ProductID
+	case p.CategoryID != nil:
+		return slices.Contains(line.CategoryIDs, *p.CategoryID)
+	default:
+		return true
+	}
+}
+
+// discounts returns the discount the promotion gives on each of the
+// eligible lines, given what is left of each line after earlier discounts.
+func (p *Promotion) discounts(lines []CartLine, eligible []int, remaining []money.Amount) []money.Amount {
+	amounts := make([]money.Amount, len(eligible))
+	switch p.Type {
+	case TypePercentage:
+		for k, i := range eligible {
+			amounts[k] = remaining[i].MulRat(int64(p.Percent), 100)
+		}
+	case TypeAmountOff:
+		for k, i := range eligible {
+			amounts[k] = p.AmountOff.Mul(lines[i].Quantity)
+		}
+	case TypeBuyXGetY:
+		// Every BuyQuantity+GetQuantity units across the eligible lines earn
+		// GetQuantity discounted units, taken from the cheapest first. Only
+		// whole units count towards the offer.
+		var units quantity.Quantity
+		for _, i := range eligible {
+			units += lines[i].Quantity
+		}
+		free := quantity.FromInt(units.Floor() / (p.BuyQuantity + p.GetQuantity) * p.GetQuantity)
+
+		order := make([]int, len(eligible))
+		for k := range order {
+			order[k] = k
+		}
+		sort.SliceStable(order, func(a, b int) bool {
+			return lines[eligible[order[a]]].UnitPrice < lines[eligible[order[b]]].UnitPrice
+		})
+		for _, k := range order {
+			if free == 0 {
+				break
+			}
+			line := lines[eligible[k]]
+			taken := min(free, line.Quantity)
+			amounts[k] = line.UnitPrice.Mul(taken).MulRat(int64(p.GetPercent), 100)
+			free -= taken
+		}
+	}
+	return amounts
+}
+
+// reason describes the promotion's discount for receipts and previews.
+func (p *Promotion) reason() string {
+	var reason string
+	switch p.Type {
+	case TypePercentage:
+		reason = fmt.Sprintf("%d%% off", p.Percent)
+	case TypeAmountOff:
+		reason = fmt.Sprintf("%s off each", p.AmountOff)
+	case TypeBuyXGetY:
+		if p.GetPercent == 100 {
+			reason = fmt.Sprintf("buy %d get %d free", p.BuyQuantity, p.GetQuantity)
+		} else {
+			reason = fmt.Sprintf("buy %d get %d at %d%% off", p.BuyQuantity, p.GetQuantity, p.GetPercent)
+		}
+	}
+	if p.CouponCode != nil {
+		reason += fmt.Sprintf(" with coupon %s", *p.CouponCode)
+	}
+	if p.EndsAt != nil {
+		reason += fmt.Sprintf(" until %s", p.EndsAt.Format("2006-01-02"))
+	}
+	return reason
+}
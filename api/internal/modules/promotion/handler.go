@@ -0,0 +1,189 @@
+package promotion
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/db"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/modules/auth"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/errors"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/response"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/validator"
+)
+
+// Handler handles HTTP requests for promotions.
+type Handler struct {
+	service *PromotionService
+}
+
+// NewHandler creates a new promotion handler.
+func NewHandler(database *db.DB) *Handler {
+	repo := NewPromotionRepository(database)
+	service := NewPromotionService(database, repo)
+	return &Handler{service: service}
+}
+
+// Routes returns the promotion routes.
+func (h *Handler) Routes() chi.Router {
+	r := chi.NewRouter()
+	r.Get("/", h.GetAll)
+	r.Post("/", h.Create)
+	r.Post("/apply", h.Apply)
+	r.Get("/{id}", h.GetByID)
+	r.Put("/{id}", h.Update)
+	r.Delete("/{id}", h.Delete)
+	return r
+}
+
+// GetAll handles retrieving all promotions.
+func (h *Handler) GetAll(w http.ResponseWriter, r *http.Request) {
+	promotions, err := h.service.GetAll()
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to retrieve promotions")
+		return
+	}
+
+	response.Success(w, promotions)
+}
+
+// GetByID handles retrieving a promotion by ID.
+func (h *Handler) GetByID(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid promotion ID")
+		return
+	}
+
+	promotion, err := h.service.GetByID(id)
+	if err != nil {
+		response.Error(w, http.StatusNotFound, "promotion not found")
+		return
+	}
+
+	response.Success(w, promotion)
+}
+
+// Create handles creating a new promotion.
+func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
+	var req CreatePromotionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	user, err := auth.GetUserFromContext(r.Context())
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to retrieve user")
+		return
+	}
+
+	promotion, err := h.service.Create(req, user)
+	if err != nil {
+		if validator.IsValidationError(err) {
+			response.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if errors.IsConflict(err) {
+			response.Error(w, http.StatusConflict, err.Error())
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, "failed to create promotion")
+		return
+	}
+
+	response.Created(w, promotion)
+}
+
+// Update handles updating a promotion.
+func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid promotion ID")
+		return
+	}
+
+	var req UpdatePromotionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	user, err := auth.GetUserFromContext(r.Context())
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to retrieve user")
+		return
+	}
+
+	promotion, err := h.service.Update(id, req, user)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			response.Error(w, http.StatusNotFound, "promotion not found")
+			return
+		}
+		if validator.IsValidationError(err) {
+			response.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if errors.IsConflict(err) {
+			response.Error(w, http.StatusConflict, err.Error())
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, "failed to update promotion")
+		return
+	}
+
+	response.Success(w, promotion)
+}
+
+// Delete handles deactivating a promotion.
+func (h *Handler) Delete(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid promotion ID")
+		return
+	}
+
+	user, err := auth.GetUserFromContext(r.Context())
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to retrieve user")
+		return
+	}
+
+	if err := h.service.Delete(id, user); err != nil {
+		if errors.IsNotFound(err) {
+			response.Error(w, http.StatusNotFound, "promotion not found")
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, "failed to delete promotion")
+		return
+	}
+
+	response.NoContent(w)
+}
+
+// Apply handles previewing the discounts the running promotions give on a
+// cart.
+func (h *Handler) Apply(w http.ResponseWriter, r *http.Request) {
+	var req ApplyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	application, err := h.service.Apply(req)
+	if err != nil {
+		if validator.IsValidationError(err) {
+			response.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, "failed to apply promotions")
+		return
+	}
+
+	response.Success(w, application)
+}
@@ -0,0 +1,152 @@
+package promotion
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/common"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/money"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/quantity"
+)
+
+// Type is the kind of discount a promotion gives.
+type Type string
+
+// Promotion types.
+const (
+	TypePercentage Type = "percentage"
+	TypeAmountOff  Type = "amount_off"
+	TypeBuyXGetY   Type = "buy_x_get_y"
+)
+
+// Promotion is a discount rule applied to sale lines.
+//
+// A promotion targets one product, every product of one category and its
+// subcategories, or every product when neither is set. It runs only between
+// StartsAt and EndsAt when they are set, only when its coupon code is
+// presented if it has one, and at most MaxUses sales when that is set.
+//
+// Promotions are applied highest Priority first. A stackable promotion
+// applies on top of earlier stackable ones; a non-stackable one only applies
+// to lines nothing has discounted yet, and nothing applies after it.
+type Promotion struct {
+	ID          uuid.UUID `gorm:"type:char(36);primaryKey" json:"id"`
+	Name        string    `gorm:"not null" json:"name"`
+	Description string    `json:"description"`
+	Type        Type      `gorm:"not null" json:"type"`
+
+	// Percent off the line for percentage promotions.
+	Percent int `gorm:"not null;default:0" json:"percent"`
+	// Amount off each unit for amount_off promotions.
+	AmountOff money.Amount `gorm:"not null;default:0" json:"amountOff"`
+	// Buy BuyQuantity and get GetQuantity more at GetPercent off, for
+	// buy_x_get_y promotions. The cheapest qualifying units are discounted.
+	BuyQuantity int `gorm:"not null;default:0" json:"buyQuantity"`
+	GetQuantity int `gorm:"not null;default:0" json:"getQuantity"`
+	GetPercent  int `gorm:"not null;default:0" json:"getPercent"`
+
+	ProductID  *uuid.UUID `gorm:"type:char(36);index" json:"productId"`
+	CategoryID *uuid.UUID `gorm:"type:char(36);index" json:"categoryId"`
+
+	StartsAt   *time.Time `gorm:"index" json:"startsAt"`
+	EndsAt     *time.Time `gorm:"index" json:"endsAt"`
+	CouponCode *string    `gorm:"unique" json:"couponCode"`
+
+	Priority  int  `gorm:"not null;default:0;index" json:"priority"`
+	Stackable bool `gorm:"not null;default:false" json:"stackable"`
+	MaxUses   *int `json:"maxUses"`
+	Uses      int  `gorm:"not null;default:0" json:"uses"`
+	Active    bool `gorm:"not null;default:true;index" json:"active"`
+
+	common.AuditFields
+}
+
+// CreatePromotionRequest represents a request to create a promotion.
+// Only the fields of the chosen type are used.
+type CreatePromotionRequest struct {
+	Name        string       `json:"name" validate:"required,min=1,max=255"`
+	Description string       `json:"description" validate:"max=1000"`
+	Type        Type         `json:"type" validate:"required,oneof=percentage amount_off buy_x_get_y"`
+	Percent     int          `json:"percent" validate:"gte=0,lte=100"`
+	AmountOff   money.Amount `json:"amountOff" validate:"gte=0"`
+	BuyQuantity int          `json:"buyQuantity" validate:"gte=0"`
+	GetQuantity int          `json:"getQuantity" validate:"gte=0"`
+	GetPercent  int          `json:"getPercent" validate:"gte=0,lte=100"`
+	ProductID   *uuid.UUID   `json:"productId"`
+	CategoryID  *uuid.UUID   `json:"categoryId"`
+	StartsAt    *time.Time   `json:"startsAt"`
+	EndsAt      *time.Time   `json:"endsAt"`
+	CouponCode  *string      `json:"couponCode" validate:"omitempty,min=1,max=50"`
+	Priority    int          `json:"priority"`
+	Stackable   bool         `json:"stackable"`
+	MaxUses     *int         `json:"maxUses" validate:"omitempty,gt=0"`
+}
+
+// UpdatePromotionRequest represents a request to update a promotion.
+// Its usage count is kept.
+type UpdatePromotionRequest struct {
+	CreatePromotionRequest
+	Active bool `json:"active"`
+}
+
+// CartLine is a line to apply promotions to. CategoryIDs are the product's
+// category and the categories above it.
+type CartLine struct {
+	ProductID   uuid.UUID
+	CategoryIDs []uuid.UUID
+	Quantity    quantity.Quantity
+	UnitPrice   money.Amount
+}
+
+// Discount is a discount a promotion gave on a cart line, with the reason
+// to show the customer.
+type Discount struct {
+	PromotionID uuid.UUID    `json:"promotionId"`
+	Name        string       `json:"name"`
+	Reason      string       `json:"reason"`
+	Line        int          `json:"line"`
+	Amount      money.Amount `json:"amount"`
+}
+
+// LineResult is a cart line's amount before and after its discounts.
+type LineResult struct {
+	Subtotal money.Amount `json:"subtotal"`
+	Discount money.Amount `json:"discount"`
+	Total    money.Amount `json:"total"`
+}
+
+// Application is the result of applying promotions to a cart. Lines is in
+// the order of the cart, and Discount.Line indexes it.
+type Application struct {
+	Lines         []LineResult `json:"lines"`
+	Discounts     []Discount   `json:"discounts"`
+	Subtotal      money.Amount `json:"subtotal"`
+	DiscountTotal money.Amount `json:"discountTotal"`
+	Total         money.Amount `json:"total"`
+}
+
+// Used returns the IDs of the promotions that gave a discount.
+func (a *Application) Used() []uuid.UUID {
+	seen := make(map[uuid.UUID]bool)
+	var ids []uuid.UUID
+	for _, d := range a.Discounts {
+		if !seen[d.PromotionID] {
+			seen[d.PromotionID] = true
+			ids = append(ids, d.PromotionID)
+		}
+	}
+	return ids
+}
+
+// ApplyRequest represents a cart to preview promotions on.
+type ApplyRequest struct {
+	Lines       []ApplyLineRequest `json:"lines" validate:"required,min=1,dive"`
+	CouponCodes []string           `json:"couponCodes" validate:"omitempty,dive,required,max=50"`
+}
+
+// ApplyLineRequest represents a line of a cart to preview promotions on.
+type ApplyLineRequest struct {
+	ProductID uuid.UUID         `json:"productId" validate:"required"`
+	Quantity  quantity.Quantity `json:"quantity" validate:"required,gt=0"`
+	UnitPrice money.Amount      `json:"unitPrice" validate:"gte=0"`
+}
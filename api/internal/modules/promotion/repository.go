@@ -0,0 +1,115 @@
+package promotion
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/db"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/errors"
+	"gorm.io/gorm"
+)
+
+// ErrUsageLimitReached is returned when a promotion has already been used
+// its maximum number of times.
+var ErrUsageLimitReached = errors.New(http.StatusConflict, errors.ErrConflict, "promotion has reached its usage limit")
+
+// PromotionRepository handles data access for promotions.
+type PromotionRepository struct {
+	db *db.DB
+}
+
+// NewPromotionRepository creates a new promotion repository.
+func NewPromotionRepository(database *db.DB) *PromotionRepository {
+	return &PromotionRepository{db: database}
+}
+
+// FindAll retrieves all promotions in the order they are applied.
+func (r *PromotionRepository) FindAll() ([]Promotion, error) {
+	var promotions []Promotion
+	if err := r.db.Order("priority DESC, created_at ASC").Find(&promotions).Error; err != nil {
+		return nil, err
+	}
+	return promotions, nil
+}
+
+// FindByID retrieves a promotion by ID.
+func (r *PromotionRepository) FindByID(id uuid.UUID) (*Promotion, error) {
+	var promotion Promotion
+	if err := r.db.First(&promotion, id).Error; err != nil {
+		return nil, err
+	}
+	return &promotion, nil
+}
+
+// FindByCouponCode retrieves the promotion with a coupon code.
+func (r *PromotionRepository) FindByCouponCode(code string) (*Promotion, error) {
+	var promotion Promotion
+	if err := r.db.Where("coupon_code = ?", code).First(&promotion).Error; err != nil {
+		return nil, err
+	}
+	return &promotion, nil
+}
+
+// FindRunning retrieves the active promotions running at t that have uses
+// left, in the order they are applied. Coupon promotions are included only
+// for the given codes.
+func (r *PromotionRepository) FindRunning(at time.Time, codes []string) ([]Promotion, error) {
+	var promotions []Promotion
+	query := r.db.Where("active = ?", true).
+		Where("starts_at IS NULL OR starts_at <= ?", at).
+		Where("ends_at IS NULL OR ends_at > ?", at).
+		Where("max_uses IS NULL OR uses < max_uses")
+	if len(codes) > 0 {
+		query = query.Where("coupon_code IS NULL OR coupon_code IN ?", codes)
+	} else {
+		query = query.Where("coupon_code IS NULL")
+	}
+	if err := query.Order("priority DESC, created_at ASC").Find(&promotions).Error; err != nil {
+		return nil, err
+	}
+	return promotions, nil
+}
+
+// Create creates a new promotion.
+func (r *PromotionRepository) Create(promotion *Promotion) error {
+	if promotion.ID == uuid.Nil {
+		promotion.ID = uuid.New()
+	}
+	return r.db.Create(promotion).Error
+}
+
+// Update updates an existing promotion. The usage count is never written
+// here; it only changes through IncrementUses.
+func (r *PromotionRepository) Update(promotion *Promotion) error {
+	return r.db.Omit("uses").Save(promotion).Error
+}
+
+// Deactivate stops a promotion from applying. Promotions are kept for the
+// sales that used them.
+func (r *PromotionRepository) Deactivate(id uuid.UUID, userID uuid.UUID) error {
+	result := r.db.Model(&Promotion{}).Where("id = ?", id).
+		Updates(map[string]any{"active": false, "updated_by": userID})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.ErrNotFound
+	}
+	return nil
+}
+
+// IncrementUses records a use of a promotion, failing with
+// ErrUsageLimitReached if it has no uses left.
+func (r *PromotionRepository) IncrementUses(id uuid.UUID) error {
+	result := r.db.Model(&Promotion{}).
+		Where("id = ? AND (max_uses IS NULL OR uses < max_uses)", id).
+		Update("uses", gorm.Expr("uses + 1"))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrUsageLimitReached
+	}
+	return nil
+}
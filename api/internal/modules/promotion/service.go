@@ -0,0 +1,206 @@
+package promotion
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/common"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/db"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/modules/auth"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/modules/product"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/errors"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/logger"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/validator"
+)
+
+// PromotionService handles business logic for promotions.
+type PromotionService struct {
+	db   *db.DB
+	repo *PromotionRepository
+}
+
+// NewPromotionService creates a new promotion service.
+func NewPromotionService(database *db.DB, repo *PromotionRepository) *PromotionService {
+	return &PromotionService{db: database, repo: repo}
+}
+
+// GetAll retrieves all promotions.
+func (s *PromotionService) GetAll() ([]Promotion, error) {
+	return s.repo.FindAll()
+}
+
+// GetByID retrieves a promotion by ID.
+func (s *PromotionService) GetByID(id uuid.UUID) (*Promotion, error) {
+	return s.repo.FindByID(id)
+}
+
+// Create creates a new promotion.
+func (s *PromotionService) Create(req CreatePromotionRequest, user *auth.User) (*Promotion, error) {
+	promotion := &Promotion{
+		Active: true,
+		AuditFields: common.AuditFields{
+			CreatedBy: user.ID,
+			UpdatedBy: user.ID,
+		},
+	}
+	if err := s.assign(promotion, req); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Create(promotion); err != nil {
+		return nil, err
+	}
+
+	logger.Info("promotion created", "promotion_id", promotion.ID, "type", promotion.Type, "created_by", user.ID)
+	return promotion, nil
+}
+
+// Update updates an existing promotion.
+func (s *PromotionService) Update(id uuid.UUID, req UpdatePromotionRequest, user *auth.User) (*Promotion, error) {
+	promotion, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.assign(promotion, req.CreatePromotionRequest); err != nil {
+		return nil, err
+	}
+	promotion.Active = req.Active
+	promotion.UpdatedBy = user.ID
+
+	if err := s.repo.Update(promotion); err != nil {
+		return nil, err
+	}
+
+	return promotion, nil
+}
+
+// Delete deactivates a promotion.
+func (s *PromotionService) Delete(id uuid.UUID, user *auth.User) error {
+	if err := s.repo.Deactivate(id, user.ID); err != nil {
+		return err
+	}
+
+	logger.Info("promotion deactivated", "promotion_id", id, "deleted_by", user.ID)
+	return nil
+}
+
+// Apply previews the discounts the running promotions give on a cart.
+// Nothing is recorded and no uses are counted.
+func (s *PromotionService) Apply(req ApplyRequest) (*Application, error) {
+	if err := validator.Struct(req); err != nil {
+		return nil, err
+	}
+
+	products := product.NewProductRepository(s.db)
+	hierarchy, err := product.NewCategoryRepository(s.db).FindHierarchy()
+	if err != nil {
+		return nil, err
+	}
+	lines := make([]CartLine, len(req.Lines))
+	for i, line := range req.Lines {
+		p, err := products.FindByID(line.ProductID)
+		if err != nil {
+			if errors.IsNotFound(err) {
+				return nil, validator.ValidationErrors{{
+					Field:   fmt.Sprintf("lines[%d].productId", i),
+					Message: "product not found",
+				}}
+			}
+			return nil, err
+		}
+		lines[i] = CartLine{
+			ProductID:   p.ID,
+			CategoryIDs: hierarchy.Path(p.CategoryID),
+			Quantity:    line.Quantity,
+			UnitPrice:   line.UnitPrice,
+		}
+	}
+
+	return NewEngine(s.repo).Apply(lines, req.CouponCodes, time.Now())
+}
+
+// assign validates a promotion request and copies it onto the promotion.
+func (s *PromotionService) assign(promotion *Promotion, req CreatePromotionRequest) error {
+	if err := validator.Struct(req); err != nil {
+		return err
+	}
+
+	// Fields of the other types are dropped rather than stored unused.
+	switch req.Type {
+	case TypePercentage:
+		if req.Percent == 0 {
+			return validator.ValidationErrors{{Field: "percent", Message: "is required for percentage promotions"}}
+		}
+		req.AmountOff, req.BuyQuantity, req.GetQuantity, req.GetPercent = 0, 0, 0, 0
+	case TypeAmountOff:
+		if req.AmountOff == 0 {
+			return validator.ValidationErrors{{Field: "amountOff", Message: "is required for amount_off promotions"}}
+		}
+		req.Percent, req.BuyQuantity, req.GetQuantity, req.GetPercent = 0, 0, 0, 0
+	case TypeBuyXGetY:
+		if req.BuyQuantity == 0 || req.GetQuantity == 0 {
+			return validator.ValidationErrors{{Field: "buyQuantity", Message: "buyQuantity and getQuantity are required for buy_x_get_y promotions"}}
+		}
+		if req.GetPercent == 0 {
+			req.GetPercent = 100
+		}
+		req.Percent, req.AmountOff = 0, 0
+	}
+	if req.ProductID != nil && req.CategoryID != nil {
+		return validator.ValidationErrors{{Field: "productId", Message: "cannot be combined with categoryId"}}
+	}
+	if req.StartsAt != nil && req.EndsAt != nil && !req.EndsAt.After(*req.StartsAt) {
+		return validator.ValidationErrors{{Field: "endsAt", Message: "must be after startsAt"}}
+	}
+
+	if req.ProductID != nil {
+		if _, err := product.NewProductRepository(s.db).FindByID(*req.ProductID); err != nil {
+			if errors.IsNotFound(err) {
+				return validator.ValidationErrors{{Field: "productId", Message: "product not found"}}
+			}
+			return err
+		}
+	}
+	if req.CategoryID != nil {
+		if _, err := product.NewCategoryRepository(s.db).FindByID(*req.CategoryID); err != nil {
+			if errors.IsNotFound(err) {
+				return validator.ValidationErrors{{Field: "categoryId", Message: "category not found"}}
+			}
+			return err
+		}
+	}
+
+	var couponCode *string
+	if req.CouponCode != nil {
+		code := NormalizeCode(*req.CouponCode)
+		existing, err := s.repo.FindByCouponCode(code)
+		if err != nil && !errors.IsNotFound(err) {
+			return err
+		}
+		if existing != nil && existing.ID != promotion.ID {
+			return errors.Newf(http.StatusConflict, errors.ErrConflict, "coupon code %q is already in use", code)
+		}
+		couponCode = &code
+	}
+
+	promotion.Name = req.Name
+	promotion.Description = req.Description
+	promotion.Type = req.Type
+	promotion.Percent = req.Percent
+	promotion.AmountOff = req.AmountOff
+	promotion.BuyQuantity = req.BuyQuantity
+	promotion.GetQuantity = req.GetQuantity
+	promotion.GetPercent = req.GetPercent
+	promotion.ProductID = req.ProductID
+	promotion.CategoryID = req.CategoryID
+	promotion.StartsAt = common.UTC(req.StartsAt)
+	promotion.EndsAt = common.UTC(req.EndsAt)
+	promotion.CouponCode = couponCode
+	promotion.Priority = req.Priority
+	promotion.Stackable = req.Stackable
+	promotion.MaxUses = req.MaxUses
+	return nil
+}
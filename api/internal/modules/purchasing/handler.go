@@ -0,0 +1,288 @@
+package purchasing
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/db"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/modules/auth"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/errors"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/response"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/validator"
+)
+
+// Handler handles HTTP requests for purchase orders.
+type Handler struct {
+	service *OrderService
+}
+
+// NewHandler creates a new purchase order handler.
+func NewHandler(database *db.DB) *Handler {
+	repo := NewOrderRepository(database)
+	service := NewOrderService(database, repo)
+	return &Handler{service: service}
+}
+
+// Routes returns the purchase order routes.
+func (h *Handler) Routes() chi.Router {
+	r := chi.NewRouter()
+	r.Get("/", h.GetAll)
+	r.Post("/", h.Create)
+	r.Get("/{id}", h.GetByID)
+	r.Put("/{id}", h.Update)
+	r.Post("/{id}/send", h.Send)
+	r.Post("/{id}/close", h.Close)
+	r.Get("/{id}/receipts", h.GetReceipts)
+	r.Post("/{id}/receipts", h.Receive)
+	return r
+}
+
+// GetAll handles retrieving purchase orders, optionally filtered by the
+// status query parameter.
+func (h *Handler) GetAll(w http.ResponseWriter, r *http.Request) {
+	status := OrderStatus(r.URL.Query().Get("status"))
+	switch status {
+	case "", OrderDraft, OrderSent, OrderPartiallyReceived, OrderClosed:
+	default:
+		response.Error(w, http.StatusBadRequest, "invalid purchase order status")
+		return
+	}
+
+	orders, err := h.service.GetAll(status)
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to retrieve purchase orders")
+		return
+	}
+
+	response.Success(w, orders)
+}
+
+// GetByID handles retrieving a purchase order by ID.
+func (h *Handler) GetByID(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid purchase order ID")
+		return
+	}
+
+	order, err := h.service.GetByID(id)
+	if err != nil {
+		response.Error(w, http.StatusNotFound, "purchase order not found")
+		return
+	}
+
+	response.Success(w, order)
+}
+
+// GetReceipts handles retrieving the goods received against a purchase
+// order.
+func (h *Handler) GetReceipts(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid purchase order ID")
+		return
+	}
+
+	receipts, err := h.service.GetReceipts(id)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			response.Error(w, http.StatusNotFound, "purchase order not found")
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, "failed to retrieve goods receipts")
+		return
+	}
+
+	response.Success(w, receipts)
+}
+
+// Create handles creating a draft purchase order.
+func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
+	var req CreatePurchaseOrderRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	user, err := auth.GetUserFromContext(r.Context())
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to retrieve user")
+		return
+	}
+
+	order, err := h.service.Create(req, user)
+	if err != nil {
+		if validator.IsValidationError(err) {
+			response.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if errors.IsUnprocessable(err) {
+			response.Error(w, http.StatusUnprocessableEntity, err.Error())
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, "failed to create purchase order")
+		return
+	}
+
+	response.Created(w, order)
+}
+
+// Update handles updating a draft purchase order.
+func (h *Handler) Update(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid purchase order ID")
+		return
+	}
+
+	var req UpdatePurchaseOrderRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	user, err := auth.GetUserFromContext(r.Context())
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to retrieve user")
+		return
+	}
+
+	order, err := h.service.Update(id, req, user)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			response.Error(w, http.StatusNotFound, "purchase order not found")
+			return
+		}
+		if validator.IsValidationError(err) {
+			response.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if errors.IsConflict(err) {
+			response.Error(w, http.StatusConflict, err.Error())
+			return
+		}
+		if errors.IsUnprocessable(err) {
+			response.Error(w, http.StatusUnprocessableEntity, err.Error())
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, "failed to update purchase order")
+		return
+	}
+
+	response.Success(w, order)
+}
+
+// Send handles marking a draft purchase order as sent.
+func (h *Handler) Send(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid purchase order ID")
+		return
+	}
+
+	user, err := auth.GetUserFromContext(r.Context())
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to retrieve user")
+		return
+	}
+
+	order, err := h.service.Send(id, user)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			response.Error(w, http.StatusNotFound, "purchase order not found")
+			return
+		}
+		if validator.IsValidationError(err) {
+			response.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if errors.IsConflict(err) {
+			response.Error(w, http.StatusConflict, err.Error())
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, "failed to send purchase order")
+		return
+	}
+
+	response.Success(w, order)
+}
+
+// Receive handles recording goods received against a purchase order.
+func (h *Handler) Receive(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid purchase order ID")
+		return
+	}
+
+	var req ReceiveGoodsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	user, err := auth.GetUserFromContext(r.Context())
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to retrieve user")
+		return
+	}
+
+	receipt, err := h.service.Receive(id, req, user)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			response.Error(w, http.StatusNotFound, "purchase order not found")
+			return
+		}
+		if validator.IsValidationError(err) {
+			response.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if errors.IsConflict(err) {
+			response.Error(w, http.StatusConflict, err.Error())
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, "failed to receive goods")
+		return
+	}
+
+	response.Created(w, receipt)
+}
+
+// Close handles closing a purchase order short.
+func (h *Handler) Close(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid purchase order ID")
+		return
+	}
+
+	user, err := auth.GetUserFromContext(r.Context())
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to retrieve user")
+		return
+	}
+
+	order, err := h.service.Close(id, user)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			response.Error(w, http.StatusNotFound, "purchase order not found")
+			return
+		}
+		if errors.IsConflict(err) {
+			response.Error(w, http.StatusConflict, err.Error())
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, "failed to close purchase order")
+		return
+	}
+
+	response.Success(w, order)
+}
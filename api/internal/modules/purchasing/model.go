@@ -0,0 +1,149 @@
+package purchasing
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/common"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/modules/product"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/modules/supplier"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/money"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/quantity"
+)
+
+// OrderStatus is the lifecycle state of a purchase order.
+type OrderStatus string
+
+// Purchase order statuses. A draft is edited until it is sent to the
+// supplier; goods are then received against it until every line is
+// delivered, or it is closed short.
+const (
+	OrderDraft             OrderStatus = "draft"
+	OrderSent              OrderStatus = "sent"
+	OrderPartiallyReceived OrderStatus = "partially_received"
+	OrderClosed            OrderStatus = "closed"
+)
+
+// PurchaseOrder is an order for stock placed with a supplier. Total is the
+// cost of the quantities ordered. Goods received against it are held at
+// LocationID.
+type PurchaseOrder struct {
+	ID         uuid.UUID    `gorm:"type:char(36);primaryKey" json:"id"`
+	SupplierID uuid.UUID    `gorm:"type:char(36);index;not null" json:"supplierId"`
+	LocationID uuid.UUID    `gorm:"type:char(36);index" json:"locationId"`
+	Status     OrderStatus  `gorm:"not null;default:draft;index" json:"status"`
+	Notes      string       `json:"notes"`
+	Total      money.Amount `gorm:"not null" json:"total"`
+	ExpectedAt *time.Time   `json:"expectedAt"`
+	SentAt     *time.Time   `json:"sentAt"`
+	ClosedAt   *time.Time   `json:"closedAt"`
+
+	Supplier supplier.Supplier   `gorm:"foreignKey:SupplierID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:RESTRICT;" json:"-"`
+	Lines    []PurchaseOrderLine `gorm:"foreignKey:PurchaseOrderID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"lines"`
+
+	common.AuditFields
+}
+
+// PurchaseOrderLine is a quantity of a product ordered at a unit cost.
+// The line is ordered as UnitQuantity of Unit, at UnitCost per Unit; the
+// ordered, received and variance quantities are in the product's base unit.
+// Variance is the quantity received less the quantity ordered: negative
+// while the line is short, positive when the supplier over-delivered.
+type PurchaseOrderLine struct {
+	ID               uuid.UUID         `gorm:"type:char(36);primaryKey" json:"id"`
+	PurchaseOrderID  uuid.UUID         `gorm:"type:char(36);index;not null" json:"purchaseOrderId"`
+	ProductID        uuid.UUID         `gorm:"type:char(36);index;not null" json:"productId"`
+	Unit             string            `gorm:"not null;default:''" json:"unit"`
+	UnitQuantity     quantity.Quantity `gorm:"not null;default:0" json:"unitQuantity"`
+	OrderedQuantity  quantity.Quantity `gorm:"not null" json:"orderedQuantity"`
+	ReceivedQuantity quantity.Quantity `gorm:"not null;default:0" json:"receivedQuantity"`
+	Variance         quantity.Quantity `gorm:"not null" json:"variance"`
+	UnitCost         money.Amount      `gorm:"not null" json:"unitCost"`
+	LineTotal        money.Amount      `gorm:"not null" json:"lineTotal"`
+
+	Product product.Product `gorm:"foreignKey:ProductID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:RESTRICT;" json:"-"`
+}
+
+// GoodsReceipt records a delivery received against a purchase order.
+// Reference is the supplier's delivery note number.
+type GoodsReceipt struct {
+	ID              uuid.UUID `gorm:"type:char(36);primaryKey" json:"id"`
+	PurchaseOrderID uuid.UUID `gorm:"type:char(36);index;not null" json:"purchaseOrderId"`
+	Reference       string    `json:"reference"`
+	Notes           string    `json:"notes"`
+	ReceivedAt      time.Time `gorm:"not null" json:"receivedAt"`
+
+	PurchaseOrder PurchaseOrder      `gorm:"foreignKey:PurchaseOrderID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:RESTRICT;" json:"-"`
+	Lines         []GoodsReceiptLine `gorm:"foreignKey:GoodsReceiptID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"lines"`
+
+	common.AuditFields
+}
+
+// GoodsReceiptLine is a quantity of an order line received into a new
+// batch, in the product's base unit. OverDelivered is the part of the
+// quantity beyond what was still outstanding on the order line.
+type GoodsReceiptLine struct {
+	ID                  uuid.UUID         `gorm:"type:char(36);primaryKey" json:"id"`
+	GoodsReceiptID      uuid.UUID         `gorm:"type:char(36);index;not null" json:"goodsReceiptId"`
+	PurchaseOrderLineID uuid.UUID         `gorm:"type:char(36);index;not null" json:"purchaseOrderLineId"`
+	ProductID           uuid.UUID         `gorm:"type:char(36);index;not null" json:"productId"`
+	BatchID             uuid.UUID         `gorm:"type:char(36);index;not null" json:"batchId"`
+	Quantity            quantity.Quantity `gorm:"not null" json:"quantity"`
+	OverDelivered       quantity.Quantity `gorm:"not null;default:0" json:"overDelivered"`
+	CostPrice           money.Amount      `gorm:"not null" json:"costPrice"`
+	SellingPrice        money.Amount      `gorm:"not null" json:"sellingPrice"`
+	ExpiresAt           *time.Time        `json:"expiresAt"`
+}
+
+// CreatePurchaseOrderRequest represents a request to create a draft
+// purchase order. Without a location, goods are delivered to the user's
+// default location.
+type CreatePurchaseOrderRequest struct {
+	SupplierID uuid.UUID                  `json:"supplierId" validate:"required"`
+	LocationID *uuid.UUID                 `json:"locationId"`
+	Notes      string                     `json:"notes" validate:"max=1000"`
+	ExpectedAt *time.Time                 `json:"expectedAt"`
+	Lines      []PurchaseOrderLineRequest `json:"lines" validate:"required,min=1,dive"`
+}
+
+// UpdatePurchaseOrderRequest represents a request to update a draft
+// purchase order. The lines replace the order's lines.
+type UpdatePurchaseOrderRequest struct {
+	SupplierID uuid.UUID                  `json:"supplierId" validate:"required"`
+	LocationID *uuid.UUID                 `json:"locationId"`
+	Notes      string                     `json:"notes" validate:"max=1000"`
+	ExpectedAt *time.Time                 `json:"expectedAt"`
+	Lines      []PurchaseOrderLineRequest `json:"lines" validate:"required,min=1,dive"`
+}
+
+// PurchaseOrderLineRequest represents a line of a purchase order request.
+// The quantity and unit cost are per Unit, which defaults to the product's
+// purchase unit.
+type PurchaseOrderLineRequest struct {
+	ProductID uuid.UUID         `json:"productId" validate:"required"`
+	Unit      string            `json:"unit" validate:"max=20"`
+	Quantity  quantity.Quantity `json:"quantity" validate:"required,gt=0"`
+	UnitCost  money.Amount      `json:"unitCost" validate:"gte=0"`
+}
+
+// ReceiveGoodsRequest represents a delivery received against a purchase
+// order. ReceivedAt defaults to now and becomes the batches' purchase date.
+type ReceiveGoodsRequest struct {
+	Reference  string                    `json:"reference" validate:"max=100"`
+	Notes      string                    `json:"notes" validate:"max=1000"`
+	ReceivedAt *time.Time                `json:"receivedAt"`
+	Lines      []ReceiveGoodsLineRequest `json:"lines" validate:"required,min=1,dive"`
+}
+
+// ReceiveGoodsLineRequest represents a quantity of an order line received,
+// in Unit, which defaults to the unit the line was ordered in. The cost and
+// selling prices are per base unit; CostPrice defaults to the order line's
+// unit cost spread over the base units of its unit.
+type ReceiveGoodsLineRequest struct {
+	PurchaseOrderLineID uuid.UUID         `json:"purchaseOrderLineId" validate:"required"`
+	Unit                string            `json:"unit" validate:"max=20"`
+	Quantity            quantity.Quantity `json:"quantity" validate:"required,gt=0"`
+	CostPrice           *money.Amount     `json:"costPrice" validate:"omitempty,gte=0"`
+	SellingPrice        money.Amount      `json:"sellingPrice" validate:"gte=0"`
+	ExpiresAt           *time.Time        `json:"expiresAt"`
+}
@@ -0,0 +1,242 @@
+package purchasing
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/db"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/errors"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/quantity"
+	"gorm.io/gorm"
+)
+
+// ErrOrderNotDraft is returned when a purchase order that has been sent is
+// edited or sent again.
+var ErrOrderNotDraft = errors.New(http.StatusConflict, errors.ErrConflict, "purchase order has already been sent")
+
+// ErrOrderNotReceivable is returned when goods are received against a
+// purchase order that is still a draft or already closed.
+var ErrOrderNotReceivable = errors.New(http.StatusConflict, errors.ErrConflict, "purchase order is not open for receiving")
+
+// ErrOrderClosed is returned when a closed purchase order is closed again.
+var ErrOrderClosed = errors.New(http.StatusConflict, errors.ErrConflict, "purchase order is already closed")
+
+// OrderRepository handles data access for purchase orders.
+type OrderRepository struct {
+	db *db.DB
+}
+
+// NewOrderRepository creates a new purchase order repository.
+func NewOrderRepository(database *db.DB) *OrderRepository {
+	return &OrderRepository{db: database}
+}
+
+// FindAll retrieves all purchase orders with their lines, newest first.
+// An empty status matches every order.
+func (r *OrderRepository) FindAll(status OrderStatus) ([]PurchaseOrder, error) {
+	var orders []PurchaseOrder
+	query := r.db.Preload("Lines")
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if err := query.Order("created_at DESC").Find(&orders).Error; err != nil {
+		return nil, err
+	}
+	return orders, nil
+}
+
+// FindByID retrieves a purchase order with its lines.
+func (r *OrderRepository) FindByID(id uuid.UUID) (*PurchaseOrder, error) {
+	var order PurchaseOrder
+	if err := r.db.Preload("Lines").First(&order, id).Error; err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+// Create creates a new purchase order together with its lines.
+func (r *OrderRepository) Create(order *PurchaseOrder) error {
+	if order.ID == uuid.Nil {
+		order.ID = uuid.New()
+	}
+	assignLineIDs(order)
+	return r.db.Omit("Supplier", "Lines.Product").Create(order).Error
+}
+
+// Update updates a draft purchase order and replaces its lines, failing
+// with ErrOrderNotDraft if it has been sent in the meantime.
+func (r *OrderRepository) Update(order *PurchaseOrder) error {
+	result := r.db.Model(&PurchaseOrder{}).
+		Where("id = ? AND status = ?", order.ID, OrderDraft).
+		Updates(map[string]any{
+			"supplier_id": order.SupplierID,
+			"location_id": order.LocationID,
+			"notes":       order.Notes,
+			"expected_at": order.ExpectedAt,
+			"total":       order.Total,
+			"updated_by":  order.UpdatedBy,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrOrderNotDraft
+	}
+
+	if err := r.db.Where("purchase_order_id = ?", order.ID).Delete(&PurchaseOrderLine{}).Error; err != nil {
+		return err
+	}
+	assignLineIDs(order)
+	return r.db.Omit("Product").Create(&order.Lines).Error
+}
+
+// Send marks a draft purchase order as sent, failing with ErrOrderNotDraft
+// if it is not a draft.
+func (r *OrderRepository) Send(id uuid.UUID, sentAt time.Time, userID uuid.UUID) error {
+	result := r.db.Model(&PurchaseOrder{}).
+		Where("id = ? AND status = ?", id, OrderDraft).
+		Updates(map[string]any{
+			"status":     OrderSent,
+			"sent_at":    sentAt,
+			"updated_by": userID,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrOrderNotDraft
+	}
+	return nil
+}
+
+// SetReceivingStatus moves a sent or partially received purchase order to
+// the status its receipts leave it in, failing with ErrOrderNotReceivable if
+// it is no longer open for receiving. closedAt is set when the status is
+// closed.
+func (r *OrderRepository) SetReceivingStatus(id uuid.UUID, status OrderStatus, closedAt *time.Time, userID uuid.UUID) error {
+	result := r.db.Model(&PurchaseOrder{}).
+		Where("id = ? AND status IN ?", id, []OrderStatus{OrderSent, OrderPartiallyReceived}).
+		Updates(map[string]any{
+			"status":     status,
+			"closed_at":  closedAt,
+			"updated_by": userID,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrOrderNotReceivable
+	}
+	return nil
+}
+
+// Close closes a purchase order that is not closed yet, failing with
+// ErrOrderClosed if it is.
+func (r *OrderRepository) Close(id uuid.UUID, closedAt time.Time, userID uuid.UUID) error {
+	result := r.db.Model(&PurchaseOrder{}).
+		Where("id = ? AND status <> ?", id, OrderClosed).
+		Updates(map[string]any{
+			"status":     OrderClosed,
+			"closed_at":  closedAt,
+			"updated_by": userID,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrOrderClosed
+	}
+	return nil
+}
+
+// AddReceivedQuantity atomically records a received quantity against an
+// order line and updates its variance.
+func (r *OrderRepository) AddReceivedQuantity(lineID uuid.UUID, received quantity.Quantity) error {
+	return r.db.Model(&PurchaseOrderLine{}).Where("id = ?", lineID).
+		Updates(map[string]any{
+			"received_quantity": gorm.Expr("received_quantity + ?", received),
+			"variance":          gorm.Expr("received_quantity + ? - ordered_quantity", received),
+		}).Error
+}
+
+// assignLineIDs gives new lines an ID and ties every line to its order.
+func assignLineIDs(order *PurchaseOrder) {
+	for i := range order.Lines {
+		if order.Lines[i].ID == uuid.Nil {
+			order.Lines[i].ID = uuid.New()
+		}
+		order.Lines[i].PurchaseOrderID = order.ID
+	}
+}
+
+// ReceiptRepository handles data access for goods receipts.
+// Receipts are append-only, so there is no update or delete.
+type ReceiptRepository struct {
+	db *db.DB
+}
+
+// NewReceiptRepository creates a new goods receipt repository.
+func NewReceiptRepository(database *db.DB) *ReceiptRepository {
+	return &ReceiptRepository{db: database}
+}
+
+// FindByOrderID retrieves the goods receipts of a purchase order with their
+// lines, in the order they were received.
+func (r *ReceiptRepository) FindByOrderID(orderID uuid.UUID) ([]GoodsReceipt, error) {
+	var receipts []GoodsReceipt
+	if err := r.db.Preload("Lines").Where("purchase_order_id = ?", orderID).
+		Order("received_at ASC, created_at ASC").Find(&receipts).Error; err != nil {
+		return nil, err
+	}
+	return receipts, nil
+}
+
+// Create creates a new goods receipt together with its lines.
+func (r *ReceiptRepository) Create(receipt *GoodsReceipt) error {
+	if receipt.ID == uuid.Nil {
+		receipt.ID = uuid.New()
+	}
+	for i := range receipt.Lines {
+		if receipt.Lines[i].ID == uuid.Nil {
+			receipt.Lines[i].ID = uuid.New()
+		}
+		receipt.Lines[i].GoodsReceiptID = receipt.ID
+	}
+	return r.db.Omit("PurchaseOrder").Create(receipt).Error
+}
+
+// MigrateQuantitiesToThousandths converts ordered and received quantities
+// stored as whole units to thousandths.
+var MigrateQuantitiesToThousandths = db.Migration{
+	ID: "20261017_purchasing_quantity_thousandths",
+	Up: func(tx *db.DB) error {
+		tables := []struct {
+			name    string
+			columns []string
+		}{
+			{"purchase_order_lines", []string{"ordered_quantity", "received_quantity", "variance"}},
+			{"goods_receipt_lines", []string{"quantity", "over_delivered"}},
+		}
+		for _, t := range tables {
+			if err := quantity.MigrateColumns(tx.DB, t.name, t.columns...); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+}
+
+// MigrateOrderLineUnits records the lines of orders placed before units of
+// measure as ordered in their product's base unit.
+var MigrateOrderLineUnits = db.Migration{
+	ID: "20261017_purchasing_order_line_units",
+	Up: func(tx *db.DB) error {
+		return tx.Model(&PurchaseOrderLine{}).
+			Where("unit = ?", "").
+			Updates(map[string]any{
+				"unit":          gorm.Expr("(SELECT base_unit FROM products WHERE products.id = purchase_order_lines.product_id)"),
+				"unit_quantity": gorm.Expr("ordered_quantity"),
+			}).Error
+	},
+}
@@ -0,0 +1,403 @@
+package purchasing
+
+import (
+	"cmp"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/common"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/db"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/modules/auth"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/modules/inventory"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/modules/location"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/modules/product"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/modules/supplier"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/errors"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/logger"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/validator"
+)
+
+// OrderService handles business logic for purchase orders and the goods
+// received against them.
+type OrderService struct {
+	db   *db.DB
+	repo *OrderRepository
+}
+
+// NewOrderService creates a new purchase order service.
+func NewOrderService(database *db.DB, repo *OrderRepository) *OrderService {
+	return &OrderService{db: database, repo: repo}
+}
+
+// GetAll retrieves all purchase orders, optionally only those in a status.
+func (s *OrderService) GetAll(status OrderStatus) ([]PurchaseOrder, error) {
+	return s.repo.FindAll(status)
+}
+
+// GetByID retrieves a purchase order by ID.
+func (s *OrderService) GetByID(id uuid.UUID) (*PurchaseOrder, error) {
+	return s.repo.FindByID(id)
+}
+
+// GetReceipts retrieves the goods received against a purchase order.
+func (s *OrderService) GetReceipts(id uuid.UUID) ([]GoodsReceipt, error) {
+	if _, err := s.repo.FindByID(id); err != nil {
+		return nil, err
+	}
+	return NewReceiptRepository(s.db).FindByOrderID(id)
+}
+
+// Create creates a draft purchase order.
+func (s *OrderService) Create(req CreatePurchaseOrderRequest, user *auth.User) (*PurchaseOrder, error) {
+	if err := validator.Struct(req); err != nil {
+		return nil, err
+	}
+
+	order := &PurchaseOrder{
+		Status: OrderDraft,
+		AuditFields: common.AuditFields{
+			CreatedBy: user.ID,
+			UpdatedBy: user.ID,
+		},
+	}
+
+	err := s.db.Transaction(func(tx *db.DB) error {
+		locationID, err := location.NewResolver(tx).Resolve(req.LocationID, user)
+		if err != nil {
+			return err
+		}
+		order.LocationID = locationID
+
+		if err := assign(tx, order, req.SupplierID, req.Notes, req.ExpectedAt, req.Lines); err != nil {
+			return err
+		}
+		return NewOrderRepository(tx).Create(order)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("purchase order created", "purchase_order_id", order.ID, "supplier_id", order.SupplierID, "total", order.Total.String(), "created_by", user.ID)
+	return order, nil
+}
+
+// Update updates a draft purchase order, replacing its lines.
+func (s *OrderService) Update(id uuid.UUID, req UpdatePurchaseOrderRequest, user *auth.User) (*PurchaseOrder, error) {
+	if err := validator.Struct(req); err != nil {
+		return nil, err
+	}
+
+	var order *PurchaseOrder
+	err := s.db.Transaction(func(tx *db.DB) error {
+		repo := NewOrderRepository(tx)
+
+		var err error
+		order, err = repo.FindByID(id)
+		if err != nil {
+			return err
+		}
+		if order.Status != OrderDraft {
+			return ErrOrderNotDraft
+		}
+
+		order.LocationID, err = location.NewResolver(tx).Resolve(req.LocationID, user)
+		if err != nil {
+			return err
+		}
+		if err := assign(tx, order, req.SupplierID, req.Notes, req.ExpectedAt, req.Lines); err != nil {
+			return err
+		}
+		order.UpdatedBy = user.ID
+		return repo.Update(order)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return order, nil
+}
+
+// Send marks a draft purchase order as sent to its supplier. Only orders
+// from active suppliers are sent.
+func (s *OrderService) Send(id uuid.UUID, user *auth.User) (*PurchaseOrder, error) {
+	var order *PurchaseOrder
+	err := s.db.Transaction(func(tx *db.DB) error {
+		repo := NewOrderRepository(tx)
+
+		var err error
+		order, err = repo.FindByID(id)
+		if err != nil {
+			return err
+		}
+		if err := checkSupplier(tx, order.SupplierID); err != nil {
+			return err
+		}
+
+		now := time.Now()
+		if err := repo.Send(id, now, user.ID); err != nil {
+			return err
+		}
+		order.Status = OrderSent
+		order.SentAt = &now
+		order.UpdatedBy = user.ID
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("purchase order sent", "purchase_order_id", id, "sent_by", user.ID)
+	return order, nil
+}
+
+// Receive records goods received against a sent purchase order. Each line
+// received becomes a new batch at the order's location, its quantity
+// recorded as a receipt stock movement. Quantities beyond what is
+// outstanding are accepted and recorded as over-delivery. The order is
+// closed once every line is fully received, and is partially received until
+// then.
+func (s *OrderService) Receive(id uuid.UUID, req ReceiveGoodsRequest, user *auth.User) (*GoodsReceipt, error) {
+	if err := validator.Struct(req); err != nil {
+		return nil, err
+	}
+
+	receipt := &GoodsReceipt{
+		ID:              uuid.New(),
+		PurchaseOrderID: id,
+		Reference:       req.Reference,
+		Notes:           req.Notes,
+		ReceivedAt:      time.Now(),
+		AuditFields: common.AuditFields{
+			CreatedBy: user.ID,
+			UpdatedBy: user.ID,
+		},
+	}
+	if req.ReceivedAt != nil {
+		receipt.ReceivedAt = *req.ReceivedAt
+	}
+
+	err := s.db.Transaction(func(tx *db.DB) error {
+		orders := NewOrderRepository(tx)
+		batches := inventory.NewBatchRepository(tx)
+		products := product.NewProductRepository(tx)
+
+		order, err := orders.FindByID(id)
+		if err != nil {
+			return err
+		}
+		if order.Status != OrderSent && order.Status != OrderPartiallyReceived {
+			return ErrOrderNotReceivable
+		}
+
+		lines := make(map[uuid.UUID]*PurchaseOrderLine, len(order.Lines))
+		for i := range order.Lines {
+			lines[order.Lines[i].ID] = &order.Lines[i]
+		}
+
+		for i, l := range req.Lines {
+			line, ok := lines[l.PurchaseOrderLineID]
+			if !ok {
+				return validator.ValidationErrors{{
+					Field:   fmt.Sprintf("lines[%d].purchaseOrderLineId", i),
+					Message: "line is not on this purchase order",
+				}}
+			}
+
+			p, err := products.FindByID(line.ProductID)
+			if err != nil {
+				return err
+			}
+			received, err := p.ToBase(cmp.Or(l.Unit, line.Unit), l.Quantity)
+			if err != nil {
+				return validator.Nest(fmt.Sprintf("lines[%d]", i), err)
+			}
+
+			// The order line's unit cost is per the unit it was ordered in.
+			costPrice := line.UnitCost.MulRat(int64(line.UnitQuantity), int64(line.OrderedQuantity))
+			if l.CostPrice != nil {
+				costPrice = *l.CostPrice
+			}
+			outstanding := max(line.OrderedQuantity-line.ReceivedQuantity, 0)
+
+			batch := &inventory.ProductBatch{
+				ProductID:    line.ProductID,
+				LocationID:   order.LocationID,
+				CostPrice:    costPrice,
+				SellingPrice: l.SellingPrice,
+				PurchasedAt:  receipt.ReceivedAt.UTC(),
+				ExpiresAt:    common.UTC(l.ExpiresAt),
+				AuditFields: common.AuditFields{
+					CreatedBy: user.ID,
+					UpdatedBy: user.ID,
+				},
+			}
+			if err := batches.Create(batch); err != nil {
+				return err
+			}
+			movement := &inventory.StockMovement{
+				BatchID:       batch.ID,
+				ProductID:     batch.ProductID,
+				Type:          inventory.MovementReceipt,
+				Quantity:      received,
+				ReferenceType: "goods_receipt",
+				ReferenceID:   &receipt.ID,
+				CreatedBy:     user.ID,
+			}
+			if err := batches.ApplyMovement(movement); err != nil {
+				return err
+			}
+
+			if err := orders.AddReceivedQuantity(line.ID, received); err != nil {
+				return err
+			}
+			line.ReceivedQuantity += received
+			line.Variance = line.ReceivedQuantity - line.OrderedQuantity
+
+			receipt.Lines = append(receipt.Lines, GoodsReceiptLine{
+				PurchaseOrderLineID: line.ID,
+				ProductID:           line.ProductID,
+				BatchID:             batch.ID,
+				Quantity:            received,
+				OverDelivered:       max(received-outstanding, 0),
+				CostPrice:           costPrice,
+				SellingPrice:        l.SellingPrice,
+				ExpiresAt:           l.ExpiresAt,
+			})
+		}
+
+		if err := NewReceiptRepository(tx).Create(receipt); err != nil {
+			return err
+		}
+
+		status := OrderClosed
+		var closedAt *time.Time
+		for _, line := range order.Lines {
+			if line.ReceivedQuantity < line.OrderedQuantity {
+				status = OrderPartiallyReceived
+				break
+			}
+		}
+		if status == OrderClosed {
+			now := time.Now()
+			closedAt = &now
+		}
+		return orders.SetReceivingStatus(id, status, closedAt, user.ID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("goods received", "goods_receipt_id", receipt.ID, "purchase_order_id", id, "lines", len(receipt.Lines), "received_by", user.ID)
+	return receipt, nil
+}
+
+// Close closes a purchase order without waiting for the rest of its
+// goods. The lines keep their variance, so what was never delivered stays on
+// record.
+func (s *OrderService) Close(id uuid.UUID, user *auth.User) (*PurchaseOrder, error) {
+	var order *PurchaseOrder
+	err := s.db.Transaction(func(tx *db.DB) error {
+		repo := NewOrderRepository(tx)
+
+		var err error
+		order, err = repo.FindByID(id)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		if err := repo.Close(id, now, user.ID); err != nil {
+			return err
+		}
+		order.Status = OrderClosed
+		order.ClosedAt = &now
+		order.UpdatedBy = user.ID
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("purchase order closed", "purchase_order_id", id, "closed_by", user.ID)
+	return order, nil
+}
+
+// assign validates the supplier and lines of a purchase order request and
+// copies them onto the order.
+func assign(tx *db.DB, order *PurchaseOrder, supplierID uuid.UUID, notes string, expectedAt *time.Time, reqLines []PurchaseOrderLineRequest) error {
+	if err := checkSupplier(tx, supplierID); err != nil {
+		return err
+	}
+
+	products := product.NewProductRepository(tx)
+	lines := make([]PurchaseOrderLine, 0, len(reqLines))
+	order.Total = 0
+	for i, l := range reqLines {
+		p, err := products.FindByID(l.ProductID)
+		if err != nil {
+			if errors.IsNotFound(err) {
+				return validator.ValidationErrors{{
+					Field:   fmt.Sprintf("lines[%d].productId", i),
+					Message: "product not found",
+				}}
+			}
+			return err
+		}
+		variants, err := products.CountVariants(p.ID)
+		if err != nil {
+			return err
+		}
+		if variants > 0 {
+			return validator.ValidationErrors{{
+				Field:   fmt.Sprintf("lines[%d].productId", i),
+				Message: "order one of the product's variants",
+			}}
+		}
+		if p.IsKit() {
+			return validator.ValidationErrors{{
+				Field:   fmt.Sprintf("lines[%d].productId", i),
+				Message: "order the kit's components",
+			}}
+		}
+
+		unit := cmp.Or(l.Unit, p.PurchaseUnit, p.BaseUnit)
+		ordered, err := p.ToBase(unit, l.Quantity)
+		if err != nil {
+			return validator.Nest(fmt.Sprintf("lines[%d]", i), err)
+		}
+
+		lineTotal := l.UnitCost.Mul(l.Quantity)
+		lines = append(lines, PurchaseOrderLine{
+			ProductID:       l.ProductID,
+			Unit:            unit,
+			UnitQuantity:    l.Quantity,
+			OrderedQuantity: ordered,
+			Variance:        -ordered,
+			UnitCost:        l.UnitCost,
+			LineTotal:       lineTotal,
+		})
+		order.Total += lineTotal
+	}
+
+	order.SupplierID = supplierID
+	order.Notes = notes
+	order.ExpectedAt = expectedAt
+	order.Lines = lines
+	return nil
+}
+
+// checkSupplier verifies that a supplier exists and is active.
+func checkSupplier(tx *db.DB, id uuid.UUID) error {
+	s, err := supplier.NewSupplierRepository(tx).FindByID(id)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return validator.ValidationErrors{{Field: "supplierId", Message: "supplier not found"}}
+		}
+		return err
+	}
+	if !s.Active {
+		return validator.ValidationErrors{{Field: "supplierId", Message: "supplier is not active"}}
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package receipt
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/config"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/db"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/modules/product"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/modules/sale"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/errors"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/response"
+)
+
+// Handler handles HTTP requests for receipts.
+type Handler struct {
+	service *ReceiptService
+}
+
+// NewHandler creates a new receipt handler.
+func NewHandler(database *db.DB, cfg config.ReceiptConfig) *Handler {
+	sales := sale.NewSaleRepository(database)
+	products := product.NewProductRepository(database)
+	service := NewReceiptService(sales, products, cfg)
+	return &Handler{service: service}
+}
+
+// Routes returns the receipt routes, mounted under a sale.
+func (h *Handler) Routes() chi.Router {
+	r := chi.NewRouter()
+	r.Get("/", h.GetBySaleID)
+	return r
+}
+
+// GetBySaleID handles rendering the receipt of a sale. The format query
+// parameter selects text (default), html or escpos; ESC/POS receipts are
+// returned as raw printer bytes.
+func (h *Handler) GetBySaleID(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid sale ID")
+		return
+	}
+
+	format := Format(r.URL.Query().Get("format"))
+	if format == "" {
+		format = FormatText
+	}
+	if format != FormatText && format != FormatHTML && format != FormatESCPOS {
+		response.Error(w, http.StatusBadRequest, "format must be one of text, html, escpos")
+		return
+	}
+
+	body, err := h.service.Render(id, format)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			response.Error(w, http.StatusNotFound, "sale not found")
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, "failed to render receipt")
+		return
+	}
+
+	response.Raw(w, http.StatusOK, format.ContentType(), body)
+}
@@ -0,0 +1,80 @@
+package receipt
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/modules/payment"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/money"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/quantity"
+)
+
+// Format is the output format of a rendered receipt.
+type Format string
+
+// Receipt formats.
+const (
+	FormatText   Format = "text"
+	FormatHTML   Format = "html"
+	FormatESCPOS Format = "escpos"
+)
+
+// ContentType returns the HTTP content type of a receipt format.
+func (f Format) ContentType() string {
+	switch f {
+	case FormatHTML:
+		return "text/html; charset=utf-8"
+	case FormatESCPOS:
+		return "application/octet-stream"
+	default:
+		return "text/plain; charset=utf-8"
+	}
+}
+
+// Receipt is the printable content of a sale.
+type Receipt struct {
+	StoreName   string
+	HeaderLines []string
+	FooterLines []string
+	Width       int
+
+	SaleID   uuid.UUID
+	IssuedAt time.Time
+	Lines    []Line
+	Subtotal money.Amount
+	Taxes    []Tax
+	Total    money.Amount
+	Tenders  []Tender
+	Change   money.Amount
+}
+
+// Line is a product line on a receipt, with the discounts given on it.
+// Unit is the unit the quantity is in, and is empty for products counted
+// in each.
+type Line struct {
+	Name      string
+	Quantity  quantity.Quantity
+	Unit      string
+	UnitPrice money.Amount
+	LineTotal money.Amount
+	Discounts []Discount
+}
+
+// Discount is a promotion's discount shown under a receipt line.
+type Discount struct {
+	Label  string
+	Amount money.Amount
+}
+
+// Tax is a tax amount shown in a receipt's tax summary.
+type Tax struct {
+	Label  string
+	Amount money.Amount
+}
+
+// Tender is a payment shown on a receipt.
+type Tender struct {
+	Method    payment.Method
+	Amount    money.Amount
+	Reference string
+}
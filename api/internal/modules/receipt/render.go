@@ -0,0 +1,245 @@
+package receipt
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"strings"
+
+	"github.com/shanmugharajk/go-react-web-api/api/internal/modules/payment"
+)
+
+// row is a line of a laid-out receipt. Text is already padded to the paper
+// width, except for centered rows which each format centers its own way.
+type row struct {
+	Text     string
+	Centered bool
+	Emphasis bool
+}
+
+// layout lays out a receipt as fixed-width rows, shared by the text and
+// ESC/POS formats.
+func layout(r *Receipt) []row {
+	var rows []row
+	rule := row{Text: strings.Repeat("-", r.Width)}
+
+	rows = append(rows, row{Text: fit(r.StoreName, r.Width), Centered: true, Emphasis: true})
+	for _, line := range r.HeaderLines {
+		rows = append(rows, row{Text: fit(line, r.Width), Centered: true})
+	}
+	rows = append(rows, rule)
+	rows = append(rows, row{Text: fit("Sale "+r.SaleID.String(), r.Width)})
+	rows = append(rows, row{Text: r.IssuedAt.Format("2006-01-02 15:04")})
+	rows = append(rows, rule)
+
+	for _, line := range r.Lines {
+		rows = append(rows, row{Text: fit(line.Name, r.Width)})
+		sold := line.Quantity.String()
+		if line.Unit != "" {
+			sold += " " + line.Unit
+		}
+		qty := fmt.Sprintf("  %s x %s", sold, line.UnitPrice.String())
+		rows = append(rows, row{Text: columns(qty, line.LineTotal.String(), r.Width)})
+		for _, d := range line.Discounts {
+			rows = append(rows, row{Text: columns("  "+d.Label, "-"+d.Amount.String(), r.Width)})
+		}
+	}
+	rows = append(rows, rule)
+
+	rows = append(rows, row{Text: columns("Subtotal", r.Subtotal.String(), r.Width)})
+	for _, tax := range r.Taxes {
+		rows = append(rows, row{Text: columns(tax.Label, tax.Amount.String(), r.Width)})
+	}
+	rows = append(rows, row{Text: columns("TOTAL", r.Total.String(), r.Width), Emphasis: true})
+
+	if len(r.Tenders) > 0 {
+		rows = append(rows, rule)
+		for _, t := range r.Tenders {
+			rows = append(rows, row{Text: columns(tenderLabel(t), t.Amount.String(), r.Width)})
+		}
+		rows = append(rows, row{Text: columns("Change", r.Change.String(), r.Width)})
+	}
+
+	if len(r.FooterLines) > 0 {
+		rows = append(rows, rule)
+		for _, line := range r.FooterLines {
+			rows = append(rows, row{Text: fit(line, r.Width), Centered: true})
+		}
+	}
+
+	return rows
+}
+
+// renderText renders a receipt as plain text.
+func renderText(r *Receipt) []byte {
+	var buf bytes.Buffer
+	for _, row := range layout(r) {
+		text := row.Text
+		if row.Centered {
+			text = strings.Repeat(" ", (r.Width-len([]rune(text)))/2) + text
+		}
+		buf.WriteString(strings.TrimRight(text, " "))
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes()
+}
+
+// ESC/POS command sequences.
+var (
+	escInit        = []byte{0x1b, 0x40}
+	escAlignLeft   = []byte{0x1b, 0x61, 0x00}
+	escAlignCenter = []byte{0x1b, 0x61, 0x01}
+	escEmphasisOn  = []byte{0x1b, 0x45, 0x01}
+	escEmphasisOff = []byte{0x1b, 0x45, 0x00}
+	escFeedAndCut  = []byte{0x1d, 0x56, 0x42, 0x03}
+	escFeedLines   = []byte{0x1b, 0x64}
+)
+
+// renderESCPOS renders a receipt as raw ESC/POS printer commands. Text is
+// sent in the printer's default code page, so characters outside ASCII are
+// replaced.
+func renderESCPOS(r *Receipt) []byte {
+	var buf bytes.Buffer
+	buf.Write(escInit)
+
+	for _, row := range layout(r) {
+		if row.Centered {
+			buf.Write(escAlignCenter)
+		}
+		if row.Emphasis {
+			buf.Write(escEmphasisOn)
+		}
+		buf.WriteString(ascii(strings.TrimRight(row.Text, " ")))
+		buf.WriteByte('\n')
+		if row.Emphasis {
+			buf.Write(escEmphasisOff)
+		}
+		if row.Centered {
+			buf.Write(escAlignLeft)
+		}
+	}
+
+	buf.Write(escFeedLines)
+	buf.WriteByte(3)
+	buf.Write(escFeedAndCut)
+	return buf.Bytes()
+}
+
+// htmlTemplate is the layout of HTML receipts.
+var htmlTemplate = template.Must(template.New("receipt").Funcs(template.FuncMap{
+	"tender": tenderLabel,
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Receipt {{.SaleID}}</title>
+<style>
+body { font-family: monospace; max-width: {{.Width}}ch; margin: 0 auto; }
+header, footer { text-align: center; }
+table { width: 100%; border-collapse: collapse; border-top: 1px dashed; }
+td.amount { text-align: right; vertical-align: bottom; }
+tr.total { font-weight: bold; }
+</style>
+</head>
+<body>
+<header>
+<h1>{{.StoreName}}</h1>
+{{- range .HeaderLines}}
+<div>{{.}}</div>
+{{- end}}
+</header>
+<p>Sale {{.SaleID}}<br>{{.IssuedAt.Format "2006-01-02 15:04"}}</p>
+<table class="lines">
+{{- range .Lines}}
+<tr><td>{{.Name}}<br>&nbsp;&nbsp;{{.Quantity}}{{with .Unit}} {{.}}{{end}} x {{.UnitPrice}}</td><td class="amount">{{.LineTotal}}</td></tr>
+{{- range .Discounts}}
+<tr><td>&nbsp;&nbsp;{{.Label}}</td><td class="amount">-{{.Amount}}</td></tr>
+{{- end}}
+{{- end}}
+</table>
+<table class="totals">
+<tr><td>Subtotal</td><td class="amount">{{.Subtotal}}</td></tr>
+{{- range .Taxes}}
+<tr><td>{{.Label}}</td><td class="amount">{{.Amount}}</td></tr>
+{{- end}}
+<tr class="total"><td>TOTAL</td><td class="amount">{{.Total}}</td></tr>
+</table>
+{{- if .Tenders}}
+<table class="tenders">
+{{- range .Tenders}}
+<tr><td>{{tender .}}</td><td class="amount">{{.Amount}}</td></tr>
+{{- end}}
+<tr><td>Change</td><td class="amount">{{.Change}}</td></tr>
+</table>
+{{- end}}
+{{- if .FooterLines}}
+<footer>
+{{- range .FooterLines}}
+<div>{{.}}</div>
+{{- end}}
+</footer>
+{{- end}}
+</body>
+</html>
+`))
+
+// renderHTML renders a receipt as an HTML page.
+func renderHTML(r *Receipt) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := htmlTemplate.Execute(&buf, r); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// tenderLabels are the printed names of payment methods.
+var tenderLabels = map[payment.Method]string{
+	payment.MethodCash:            "Cash",
+	payment.MethodCard:            "Card",
+	payment.MethodStoreCredit:     "Store credit",
+	payment.MethodGiftVoucher:     "Gift voucher",
+	payment.MethodCustomerAccount: "On account",
+}
+
+// tenderLabel returns the printed name of a tender, with its reference.
+func tenderLabel(t Tender) string {
+	label, ok := tenderLabels[t.Method]
+	if !ok {
+		label = string(t.Method)
+	}
+	if t.Reference != "" {
+		label += " " + t.Reference
+	}
+	return label
+}
+
+// columns lays out left- and right-aligned text on a line of the given
+// width, truncating the left text if both do not fit.
+func columns(left, right string, width int) string {
+	space := width - len([]rune(right)) - 1
+	left = fit(left, space)
+	pad := max(width-len([]rune(left))-len([]rune(right)), 1)
+	return left + strings.Repeat(" ", pad) + right
+}
+
+// fit truncates text to the given width.
+func fit(text string, width int) string {
+	runes := []rune(text)
+	if width <= 0 {
+		return ""
+	}
+	if len(runes) > width {
+		return string(runes[:width])
+	}
+	return text
+}
+
+// ascii replaces characters a printer's default code page cannot print.
+func ascii(text string) string {
+	return strings.Map(func(r rune) rune {
+		if r > 0x7e {
+			return '?'
+		}
+		return r
+	}, text)
+}
@@ -0,0 +1,135 @@
+package receipt
+
+import (
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/config"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/modules/payment"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/modules/product"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/modules/sale"
+)
+
+// ReceiptService builds and renders sale receipts.
+type ReceiptService struct {
+	sales    *sale.SaleRepository
+	products *product.ProductRepository
+	cfg      config.ReceiptConfig
+}
+
+// NewReceiptService creates a new receipt service.
+func NewReceiptService(sales *sale.SaleRepository, products *product.ProductRepository, cfg config.ReceiptConfig) *ReceiptService {
+	return &ReceiptService{sales: sales, products: products, cfg: cfg}
+}
+
+// Build assembles the receipt of a sale using the configured layout.
+func (s *ReceiptService) Build(saleID uuid.UUID) (*Receipt, error) {
+	sl, err := s.sales.FindByID(saleID)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]uuid.UUID, 0, len(sl.Lines))
+	for _, line := range sl.Lines {
+		ids = append(ids, line.ProductID)
+		if line.KitID != nil {
+			ids = append(ids, *line.KitID)
+		}
+	}
+	products, err := s.products.FindByIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+	byID := make(map[uuid.UUID]product.Product, len(products))
+	for _, p := range products {
+		byID[p.ID] = p
+	}
+
+	r := &Receipt{
+		StoreName:   s.cfg.StoreName,
+		HeaderLines: s.cfg.HeaderLines,
+		FooterLines: s.cfg.FooterLines,
+		Width:       s.cfg.PaperWidth,
+		SaleID:      sl.ID,
+		IssuedAt:    sl.CreatedAt,
+		Total:       sl.Total,
+		Change:      sl.Change,
+	}
+	if sl.CompletedAt != nil {
+		r.IssuedAt = *sl.CompletedAt
+	}
+
+	discounts := make(map[uuid.UUID][]Discount)
+	for _, d := range sl.Discounts {
+		label := fmt.Sprintf("%s (%s)", d.Name, d.Reason)
+		discounts[d.SaleLineID] = append(discounts[d.SaleLineID], Discount{Label: label, Amount: d.Amount})
+	}
+
+	// The subtotal is after discounts, so taxes added to it give the total.
+	// The components of a kit are named after it.
+	for _, line := range sl.Lines {
+		name, unit := "Unknown product", ""
+		if p, ok := byID[line.ProductID]; ok {
+			name = p.Name
+			if p.BaseUnit != product.DefaultBaseUnit {
+				unit = p.BaseUnit
+			}
+		}
+		if line.KitID != nil {
+			if kit, ok := byID[*line.KitID]; ok {
+				name = kit.Name + ": " + name
+			}
+		}
+		r.Lines = append(r.Lines, Line{
+			Name:      name,
+			Quantity:  line.Quantity,
+			Unit:      unit,
+			UnitPrice: line.UnitPrice,
+			LineTotal: line.LineTotal,
+			Discounts: discounts[line.ID],
+		})
+		r.Subtotal += line.LineTotal - line.DiscountAmount
+	}
+
+	// Taxes already in the prices are shown for information only; otherwise
+	// they are what takes the subtotal to the total.
+	for _, t := range sl.Taxes {
+		label := fmt.Sprintf("%s %s%%", t.Name, t.Rate)
+		if sl.PricesIncludeTax {
+			label += " (incl.)"
+		}
+		r.Taxes = append(r.Taxes, Tax{Label: label, Amount: t.Amount})
+	}
+
+	// Refunds are recorded against the sale too, but belong on the
+	// return's paperwork rather than the original receipt.
+	for _, p := range sl.Payments {
+		if p.ReturnID != nil {
+			continue
+		}
+		tender := Tender{Method: p.Method, Amount: p.Amount, Reference: p.Reference}
+		if p.Method == payment.MethodCash {
+			tender.Amount = p.Tendered
+		}
+		r.Tenders = append(r.Tenders, tender)
+	}
+
+	return r, nil
+}
+
+// Render builds the receipt of a sale and renders it in the given format.
+func (s *ReceiptService) Render(saleID uuid.UUID, format Format) ([]byte, error) {
+	r, err := s.Build(saleID)
+	if err != nil {
+		return nil, err
+	}
+
+	switch format {
+	case FormatHTML:
+		return renderHTML(r)
+	case FormatESCPOS:
+		return renderESCPOS(r), nil
+	default:
+		return renderText(r), nil
+	}
+}
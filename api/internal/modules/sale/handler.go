@@ -0,0 +1,272 @@
+package sale
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/config"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/db"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/modules/auth"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/errors"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/response"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/validator"
+)
+
+// Handler handles HTTP requests for sales.
+type Handler struct {
+	service *SaleService
+}
+
+// NewHandler creates a new sale handler.
+func NewHandler(database *db.DB, taxCfg config.TaxConfig, inventoryCfg config.InventoryConfig) *Handler {
+	repo := NewSaleRepository(database)
+	service := NewSaleService(database, repo, taxCfg, inventoryCfg)
+	return &Handler{service: service}
+}
+
+// Routes returns the sale routes.
+func (h *Handler) Routes() chi.Router {
+	r := chi.NewRouter()
+	r.Get("/", h.GetAll)
+	r.Post("/", h.Create)
+	r.Get("/by-category", h.ByCategory)
+	r.Get("/{id}", h.GetByID)
+	r.Post("/{id}/payments", h.Pay)
+	return r
+}
+
+// GetAll handles retrieving all sales.
+func (h *Handler) GetAll(w http.ResponseWriter, r *http.Request) {
+	sales, err := h.service.GetAll()
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to retrieve sales")
+		return
+	}
+
+	response.Success(w, sales)
+}
+
+// GetByID handles retrieving a sale by ID.
+func (h *Handler) GetByID(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid sale ID")
+		return
+	}
+
+	sale, err := h.service.GetByID(id)
+	if err != nil {
+		response.Error(w, http.StatusNotFound, "sale not found")
+		return
+	}
+
+	response.Success(w, sale)
+}
+
+// ByCategory handles retrieving what completed sales took per category,
+// rolled up to the requested level of the category tree. from and to are
+// dates, both included.
+func (h *Handler) ByCategory(w http.ResponseWriter, r *http.Request) {
+	level := 0
+	if levelStr := r.URL.Query().Get("level"); levelStr != "" {
+		n, err := strconv.Atoi(levelStr)
+		if err != nil || n < 0 {
+			response.Error(w, http.StatusBadRequest, "invalid level")
+			return
+		}
+		level = n
+	}
+
+	var from, to *time.Time
+	if fromStr := r.URL.Query().Get("from"); fromStr != "" {
+		d, err := time.ParseInLocation(time.DateOnly, fromStr, time.Local)
+		if err != nil {
+			response.Error(w, http.StatusBadRequest, "invalid from date")
+			return
+		}
+		from = &d
+	}
+	if toStr := r.URL.Query().Get("to"); toStr != "" {
+		d, err := time.ParseInLocation(time.DateOnly, toStr, time.Local)
+		if err != nil {
+			response.Error(w, http.StatusBadRequest, "invalid to date")
+			return
+		}
+		d = d.AddDate(0, 0, 1)
+		to = &d
+	}
+
+	sales, err := h.service.ByCategory(level, from, to)
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to retrieve sales by category")
+		return
+	}
+
+	response.Success(w, sales)
+}
+
+// Create handles creating a new sale.
+func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
+	var req CreateSaleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	user, err := auth.GetUserFromContext(r.Context())
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to retrieve user")
+		return
+	}
+
+	sale, err := h.service.Create(req, user)
+	if err != nil {
+		if validator.IsValidationError(err) {
+			response.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if errors.IsConflict(err) {
+			response.Error(w, http.StatusConflict, err.Error())
+			return
+		}
+		if errors.IsUnprocessable(err) {
+			response.Error(w, http.StatusUnprocessableEntity, err.Error())
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, "failed to create sale")
+		return
+	}
+
+	response.Created(w, sale)
+}
+
+// Pay handles paying an open sale.
+func (h *Handler) Pay(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid sale ID")
+		return
+	}
+
+	var req PaySaleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	user, err := auth.GetUserFromContext(r.Context())
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to retrieve user")
+		return
+	}
+
+	sale, err := h.service.Pay(id, req, user)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			response.Error(w, http.StatusNotFound, "sale not found")
+			return
+		}
+		if validator.IsValidationError(err) {
+			response.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if errors.IsConflict(err) {
+			response.Error(w, http.StatusConflict, err.Error())
+			return
+		}
+		if errors.IsUnprocessable(err) {
+			response.Error(w, http.StatusUnprocessableEntity, err.Error())
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, "failed to pay sale")
+		return
+	}
+
+	response.Success(w, sale)
+}
+
+// ReturnHandler handles HTTP requests for sale returns.
+type ReturnHandler struct {
+	service *ReturnService
+}
+
+// NewReturnHandler creates a new sale return handler.
+func NewReturnHandler(database *db.DB) *ReturnHandler {
+	repo := NewReturnRepository(database)
+	service := NewReturnService(database, repo)
+	return &ReturnHandler{service: service}
+}
+
+// Routes returns the sale return routes, mounted under a sale.
+func (h *ReturnHandler) Routes() chi.Router {
+	r := chi.NewRouter()
+	r.Get("/", h.GetBySaleID)
+	r.Post("/", h.Create)
+	return r
+}
+
+// GetBySaleID handles retrieving all returns of a sale.
+func (h *ReturnHandler) GetBySaleID(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid sale ID")
+		return
+	}
+
+	returns, err := h.service.GetBySaleID(id)
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to retrieve sale returns")
+		return
+	}
+
+	response.Success(w, returns)
+}
+
+// Create handles returning goods from a sale.
+func (h *ReturnHandler) Create(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid sale ID")
+		return
+	}
+
+	var req CreateSaleReturnRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	user, err := auth.GetUserFromContext(r.Context())
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to retrieve user")
+		return
+	}
+
+	ret, err := h.service.Create(id, req, user)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			response.Error(w, http.StatusNotFound, "sale not found")
+			return
+		}
+		if validator.IsValidationError(err) {
+			response.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if errors.IsConflict(err) {
+			response.Error(w, http.StatusConflict, err.Error())
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, "failed to create sale return")
+		return
+	}
+
+	response.Created(w, ret)
+}
@@ -0,0 +1,202 @@
+package sale
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/common"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/modules/payment"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/modules/tax"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/money"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/quantity"
+)
+
+// SaleStatus is the lifecycle state of a sale.
+type SaleStatus string
+
+// Sale statuses.
+const (
+	SaleOpen      SaleStatus = "open"
+	SaleCompleted SaleStatus = "completed"
+)
+
+// Sale represents a checkout transaction.
+// A sale stays open until tenders covering its total are recorded; Change is
+// the cash handed back when it was completed. Total is the amount due
+// including tax, of which TaxTotal is tax; PricesIncludeTax records whether
+// the line prices already included it. DiscountTotal is what promotions took
+// off the lines before tax. Stock is taken from batches held at LocationID.
+type Sale struct {
+	ID               uuid.UUID    `gorm:"type:char(36);primaryKey" json:"id"`
+	CustomerID       *uuid.UUID   `gorm:"type:char(36);index" json:"customerId"`
+	LocationID       uuid.UUID    `gorm:"type:char(36);index" json:"locationId"`
+	Total            money.Amount `gorm:"not null" json:"total"`
+	DiscountTotal    money.Amount `gorm:"not null;default:0" json:"discountTotal"`
+	TaxTotal         money.Amount `gorm:"not null;default:0" json:"taxTotal"`
+	PricesIncludeTax bool         `gorm:"not null;default:false" json:"pricesIncludeTax"`
+	Status           SaleStatus   `gorm:"not null;default:open;index" json:"status"`
+	Change           money.Amount `gorm:"not null;default:0" json:"change"`
+	CompletedAt      *time.Time   `json:"completedAt"`
+
+	Lines     []SaleLine        `gorm:"foreignKey:SaleID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"lines"`
+	Discounts []SaleDiscount    `gorm:"foreignKey:SaleID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"discounts"`
+	Taxes     []SaleTax         `gorm:"foreignKey:SaleID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"taxes"`
+	Payments  []payment.Payment `gorm:"foreignKey:SaleID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:RESTRICT;" json:"payments"`
+
+	common.AuditFields
+}
+
+// SaleLine represents a quantity of a product sold from a specific batch.
+// The quantity and unit price are in the product's base unit. LineTotal is
+// priced the way the sale's prices are, before DiscountAmount is taken off,
+// and TaxAmount is the tax charged on the discounted line under its tax
+// class. KitID is set on the lines of a kit's components; when they share
+// the kit's price, their unit price is the line total spread over the
+// quantity, rounded.
+type SaleLine struct {
+	ID               uuid.UUID         `gorm:"type:char(36);primaryKey" json:"id"`
+	SaleID           uuid.UUID         `gorm:"type:char(36);index;not null" json:"saleId"`
+	ProductID        uuid.UUID         `gorm:"type:char(36);index;not null" json:"productId"`
+	KitID            *uuid.UUID        `gorm:"type:char(36);index" json:"kitId"`
+	BatchID          uuid.UUID         `gorm:"type:char(36);index;not null" json:"batchId"`
+	Quantity         quantity.Quantity `gorm:"not null" json:"quantity"`
+	UnitPrice        money.Amount      `gorm:"not null" json:"unitPrice"`
+	LineTotal        money.Amount      `gorm:"not null" json:"lineTotal"`
+	DiscountAmount   money.Amount      `gorm:"not null;default:0" json:"discountAmount"`
+	TaxClassID       *uuid.UUID        `gorm:"type:char(36);index" json:"taxClassId"`
+	TaxAmount        money.Amount      `gorm:"not null;default:0" json:"taxAmount"`
+	ReturnedQuantity quantity.Quantity `gorm:"not null;default:0" json:"returnedQuantity"`
+}
+
+// SaleDiscount is a discount a promotion gave on a sale line. Name and
+// Reason are copied from the promotion for receipts.
+type SaleDiscount struct {
+	ID          uuid.UUID    `gorm:"type:char(36);primaryKey" json:"id"`
+	SaleID      uuid.UUID    `gorm:"type:char(36);index;not null" json:"saleId"`
+	SaleLineID  uuid.UUID    `gorm:"type:char(36);index;not null" json:"saleLineId"`
+	PromotionID uuid.UUID    `gorm:"type:char(36);index;not null" json:"promotionId"`
+	Name        string       `gorm:"not null" json:"name"`
+	Reason      string       `gorm:"not null" json:"reason"`
+	Amount      money.Amount `gorm:"not null" json:"amount"`
+}
+
+// SaleTax is the tax charged on a sale at one rate. Name and Rate are
+// copied from the rate so the sale keeps them if the rate is superseded.
+type SaleTax struct {
+	ID        uuid.UUID    `gorm:"type:char(36);primaryKey" json:"id"`
+	SaleID    uuid.UUID    `gorm:"type:char(36);index;not null" json:"saleId"`
+	TaxRateID uuid.UUID    `gorm:"type:char(36);index;not null" json:"taxRateId"`
+	Name      string       `gorm:"not null" json:"name"`
+	Rate      tax.Rate     `gorm:"not null" json:"rate"`
+	Taxable   money.Amount `gorm:"not null" json:"taxable"`
+	Amount    money.Amount `gorm:"not null" json:"amount"`
+}
+
+// CategorySales is what the completed sales of the products of a category,
+// and of the categories rolled up into it, took. Sales are net of discounts
+// and tax, and both leave out what has since been returned. Level is the
+// category's depth in the tree, 0 at the top.
+type CategorySales struct {
+	CategoryID uuid.UUID         `json:"categoryId"`
+	Name       string            `json:"name"`
+	Level      int               `json:"level"`
+	Quantity   quantity.Quantity `json:"quantity"`
+	Sales      money.Amount      `json:"sales"`
+	Tax        money.Amount      `json:"tax"`
+}
+
+// CreateSaleRequest represents a request to create a sale.
+// Running promotions are applied automatically, and coupon promotions for
+// the coupon codes given. When tenders are given the sale is paid and
+// completed straight away; otherwise it stays open until paid. Without a
+// location, stock is taken from the cashier's default location.
+type CreateSaleRequest struct {
+	CustomerID  *uuid.UUID              `json:"customerId"`
+	LocationID  *uuid.UUID              `json:"locationId"`
+	Lines       []CreateSaleLineRequest `json:"lines" validate:"required,min=1,dive"`
+	CouponCodes []string                `json:"couponCodes" validate:"omitempty,dive,required,max=50"`
+	Tenders     []payment.TenderRequest `json:"tenders" validate:"omitempty,dive"`
+}
+
+// CreateSaleLineRequest represents a single line of a sale request. The
+// quantity is in Unit, which defaults to the product's sales unit, and is
+// sold as the equivalent quantity of the base unit.
+// When BatchID is omitted the quantity is allocated across batches
+// first-expiry-first-out and may produce several sale lines. Kits are
+// always allocated, component by component.
+type CreateSaleLineRequest struct {
+	ProductID uuid.UUID         `json:"productId" validate:"required"`
+	BatchID   *uuid.UUID        `json:"batchId"`
+	Unit      string            `json:"unit" validate:"max=20"`
+	Quantity  quantity.Quantity `json:"quantity" validate:"required,gt=0"`
+}
+
+// PaySaleRequest represents the tenders paying an open sale.
+type PaySaleRequest struct {
+	Tenders []payment.TenderRequest `json:"tenders" validate:"required,min=1,dive"`
+}
+
+// ReturnDisposition describes what happens to returned goods.
+type ReturnDisposition string
+
+// Return dispositions.
+const (
+	DispositionRestock    ReturnDisposition = "restock"
+	DispositionQuarantine ReturnDisposition = "quarantine"
+	DispositionDiscard    ReturnDisposition = "discard"
+)
+
+// RefundMethod describes how a return is refunded.
+type RefundMethod string
+
+// Refund methods.
+const (
+	RefundOriginalTender RefundMethod = "original_tender"
+	RefundCustomerCredit RefundMethod = "customer_credit"
+)
+
+// SaleReturn represents goods brought back against a sale.
+type SaleReturn struct {
+	ID           uuid.UUID    `gorm:"type:char(36);primaryKey" json:"id"`
+	SaleID       uuid.UUID    `gorm:"type:char(36);index;not null" json:"saleId"`
+	CustomerID   *uuid.UUID   `gorm:"type:char(36);index" json:"customerId"`
+	RefundMethod RefundMethod `gorm:"not null" json:"refundMethod"`
+	RefundTotal  money.Amount `gorm:"not null" json:"refundTotal"`
+	Reason       string       `json:"reason"`
+
+	Sale    Sale              `gorm:"foreignKey:SaleID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:RESTRICT;" json:"-"`
+	Lines   []SaleReturnLine  `gorm:"foreignKey:ReturnID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"lines"`
+	Refunds []payment.Payment `gorm:"foreignKey:ReturnID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:RESTRICT;" json:"refunds"`
+
+	common.AuditFields
+}
+
+// SaleReturnLine represents a returned quantity of an original sale line.
+// RefundAmount includes the line's tax on the returned quantity, of which
+// TaxAmount is the tax.
+type SaleReturnLine struct {
+	ID           uuid.UUID         `gorm:"type:char(36);primaryKey" json:"id"`
+	ReturnID     uuid.UUID         `gorm:"type:char(36);index;not null" json:"returnId"`
+	SaleLineID   uuid.UUID         `gorm:"type:char(36);index;not null" json:"saleLineId"`
+	ProductID    uuid.UUID         `gorm:"type:char(36);index;not null" json:"productId"`
+	BatchID      uuid.UUID         `gorm:"type:char(36);index;not null" json:"batchId"`
+	Quantity     quantity.Quantity `gorm:"not null" json:"quantity"`
+	Disposition  ReturnDisposition `gorm:"not null" json:"disposition"`
+	RefundAmount money.Amount      `gorm:"not null" json:"refundAmount"`
+	TaxAmount    money.Amount      `gorm:"not null;default:0" json:"taxAmount"`
+}
+
+// CreateSaleReturnRequest represents a request to return goods from a sale.
+type CreateSaleReturnRequest struct {
+	RefundMethod RefundMethod                  `json:"refundMethod" validate:"required,oneof=original_tender customer_credit"`
+	Reason       string                        `json:"reason" validate:"max=1000"`
+	Lines        []CreateSaleReturnLineRequest `json:"lines" validate:"required,min=1,dive"`
+}
+
+// CreateSaleReturnLineRequest represents a single line of a return request.
+// The quantity is in the product's base unit, as the sale line's is.
+type CreateSaleReturnLineRequest struct {
+	SaleLineID  uuid.UUID         `json:"saleLineId" validate:"required"`
+	Quantity    quantity.Quantity `json:"quantity" validate:"required,gt=0"`
+	Disposition ReturnDisposition `json:"disposition" validate:"required,oneof=restock quarantine discard"`
+}
@@ -0,0 +1,218 @@
+package sale
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/db"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/errors"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/money"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/quantity"
+	"gorm.io/gorm"
+)
+
+// ErrSaleNotOpen is returned when paying a sale that is already completed.
+var ErrSaleNotOpen = errors.New(http.StatusConflict, errors.ErrConflict, "sale is already completed")
+
+// ErrReturnExceedsSold is returned when a return would bring back more than
+// is left unreturned on a sale line.
+var ErrReturnExceedsSold = errors.New(http.StatusConflict, errors.ErrConflict, "returned quantity exceeds quantity sold")
+
+// SaleRepository handles data access for sales.
+type SaleRepository struct {
+	db *db.DB
+}
+
+// NewSaleRepository creates a new sale repository.
+func NewSaleRepository(database *db.DB) *SaleRepository {
+	return &SaleRepository{db: database}
+}
+
+// FindAll retrieves all sales, most recent first.
+func (r *SaleRepository) FindAll() ([]Sale, error) {
+	var sales []Sale
+	if err := r.db.Preload("Lines").Preload("Discounts").Preload("Taxes").Preload("Payments").Order("created_at DESC").Find(&sales).Error; err != nil {
+		return nil, err
+	}
+	return sales, nil
+}
+
+// FindCompleted retrieves the sales completed in [from, to) with their
+// lines. Either bound may be nil.
+func (r *SaleRepository) FindCompleted(from, to *time.Time) ([]Sale, error) {
+	query := r.db.Preload("Lines").Where("status = ?", SaleCompleted)
+	if from != nil {
+		query = query.Where("completed_at >= ?", *from)
+	}
+	if to != nil {
+		query = query.Where("completed_at < ?", *to)
+	}
+	var sales []Sale
+	if err := query.Find(&sales).Error; err != nil {
+		return nil, err
+	}
+	return sales, nil
+}
+
+// FindByID retrieves a sale with its lines and payments by ID.
+func (r *SaleRepository) FindByID(id uuid.UUID) (*Sale, error) {
+	var sale Sale
+	if err := r.db.Preload("Lines").Preload("Discounts").Preload("Taxes").Preload("Payments").First(&sale, id).Error; err != nil {
+		return nil, err
+	}
+	return &sale, nil
+}
+
+// Create creates a new sale together with its lines, discounts and taxes.
+func (r *SaleRepository) Create(sale *Sale) error {
+	if sale.ID == uuid.Nil {
+		sale.ID = uuid.New()
+	}
+	for i := range sale.Lines {
+		if sale.Lines[i].ID == uuid.Nil {
+			sale.Lines[i].ID = uuid.New()
+		}
+		sale.Lines[i].SaleID = sale.ID
+	}
+	for i := range sale.Discounts {
+		if sale.Discounts[i].ID == uuid.Nil {
+			sale.Discounts[i].ID = uuid.New()
+		}
+		sale.Discounts[i].SaleID = sale.ID
+	}
+	for i := range sale.Taxes {
+		if sale.Taxes[i].ID == uuid.Nil {
+			sale.Taxes[i].ID = uuid.New()
+		}
+		sale.Taxes[i].SaleID = sale.ID
+	}
+	return r.db.Omit("Payments").Create(sale).Error
+}
+
+// Complete marks an open sale as completed, failing with ErrSaleNotOpen if
+// it has been completed in the meantime.
+func (r *SaleRepository) Complete(id uuid.UUID, change money.Amount, completedAt time.Time, userID uuid.UUID) error {
+	result := r.db.Model(&Sale{}).
+		Where("id = ? AND status = ?", id, SaleOpen).
+		Updates(map[string]any{
+			"status":       SaleCompleted,
+			"change":       change,
+			"completed_at": completedAt,
+			"updated_by":   userID,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrSaleNotOpen
+	}
+	return nil
+}
+
+// AddReturnedQuantity atomically records a returned quantity against a sale
+// line, failing if it would exceed the quantity sold minus what has already
+// been returned.
+func (r *SaleRepository) AddReturnedQuantity(lineID uuid.UUID, returned quantity.Quantity) error {
+	result := r.db.Model(&SaleLine{}).
+		Where("id = ? AND quantity - returned_quantity >= ?", lineID, returned).
+		Update("returned_quantity", gorm.Expr("returned_quantity + ?", returned))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrReturnExceedsSold
+	}
+	return nil
+}
+
+// ReturnRepository handles data access for sale returns.
+type ReturnRepository struct {
+	db *db.DB
+}
+
+// NewReturnRepository creates a new sale return repository.
+func NewReturnRepository(database *db.DB) *ReturnRepository {
+	return &ReturnRepository{db: database}
+}
+
+// FindBySaleID retrieves all returns of a sale with their lines and refunds.
+func (r *ReturnRepository) FindBySaleID(saleID uuid.UUID) ([]SaleReturn, error) {
+	var returns []SaleReturn
+	if err := r.db.Preload("Lines").Preload("Refunds").Where("sale_id = ?", saleID).Order("created_at ASC").Find(&returns).Error; err != nil {
+		return nil, err
+	}
+	return returns, nil
+}
+
+// Create creates a new sale return together with its lines.
+func (r *ReturnRepository) Create(ret *SaleReturn) error {
+	if ret.ID == uuid.Nil {
+		ret.ID = uuid.New()
+	}
+	for i := range ret.Lines {
+		if ret.Lines[i].ID == uuid.Nil {
+			ret.Lines[i].ID = uuid.New()
+		}
+		ret.Lines[i].ReturnID = ret.ID
+	}
+	return r.db.Omit("Sale", "Refunds").Create(ret).Error
+}
+
+// MigrateMoneyToMinorUnits converts sale and return amounts stored as
+// decimals to exact minor units.
+var MigrateMoneyToMinorUnits = db.Migration{
+	ID: "20261016_sale_money_minor_units",
+	Up: func(tx *db.DB) error {
+		tables := []struct {
+			name    string
+			columns []string
+		}{
+			{"sales", []string{"total", "change"}},
+			{"sale_lines", []string{"unit_price", "line_total"}},
+			{"sale_returns", []string{"refund_total"}},
+			{"sale_return_lines", []string{"refund_amount"}},
+		}
+		for _, t := range tables {
+			if err := money.MigrateColumns(tx.DB, t.name, t.columns...); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+}
+
+// MigrateQuantitiesToThousandths converts sale and return quantities stored
+// as whole units to thousandths.
+var MigrateQuantitiesToThousandths = db.Migration{
+	ID: "20261017_sale_quantity_thousandths",
+	Up: func(tx *db.DB) error {
+		tables := []struct {
+			name    string
+			columns []string
+		}{
+			{"sale_lines", []string{"quantity", "returned_quantity"}},
+			{"sale_return_lines", []string{"quantity"}},
+		}
+		for _, t := range tables {
+			if err := quantity.MigrateColumns(tx.DB, t.name, t.columns...); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+}
+
+// MigrateCompleteExistingSales marks sales recorded before payments were
+// tracked as completed, so they can still be returned against.
+var MigrateCompleteExistingSales = db.Migration{
+	ID: "20261016_sale_complete_existing",
+	Up: func(tx *db.DB) error {
+		return tx.Model(&Sale{}).
+			Where("completed_at IS NULL").
+			Updates(map[string]any{
+				"status":       SaleCompleted,
+				"completed_at": gorm.Expr("created_at"),
+			}).Error
+	},
+}
@@ -0,0 +1,785 @@
+package sale
+
+import (
+	"cmp"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/common"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/config"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/db"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/modules/auth"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/modules/customer"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/modules/inventory"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/modules/location"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/modules/payment"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/modules/pricelist"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/modules/product"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/modules/promotion"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/modules/shift"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/modules/tax"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/errors"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/logger"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/money"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/quantity"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/validator"
+)
+
+// SaleService handles business logic for sales.
+type SaleService struct {
+	db           *db.DB
+	repo         *SaleRepository
+	taxCfg       config.TaxConfig
+	inventoryCfg config.InventoryConfig
+}
+
+// NewSaleService creates a new sale service.
+func NewSaleService(database *db.DB, repo *SaleRepository, taxCfg config.TaxConfig, inventoryCfg config.InventoryConfig) *SaleService {
+	return &SaleService{db: database, repo: repo, taxCfg: taxCfg, inventoryCfg: inventoryCfg}
+}
+
+// GetAll retrieves all sales.
+func (s *SaleService) GetAll() ([]Sale, error) {
+	return s.repo.FindAll()
+}
+
+// GetByID retrieves a sale by ID.
+func (s *SaleService) GetByID(id uuid.UUID) (*Sale, error) {
+	return s.repo.FindByID(id)
+}
+
+// ByCategory returns what the sales completed in [from, to) took in every
+// category sold, with the categories below a level rolled up into their
+// ancestor at that level. Either bound may be nil.
+func (s *SaleService) ByCategory(level int, from, to *time.Time) ([]CategorySales, error) {
+	sales, err := s.repo.FindCompleted(from, to)
+	if err != nil {
+		return nil, err
+	}
+	var ids []uuid.UUID
+	for _, sale := range sales {
+		for _, line := range sale.Lines {
+			ids = append(ids, line.ProductID)
+		}
+	}
+	products, err := product.NewProductRepository(s.db).FindByIDs(ids)
+	if err != nil {
+		return nil, err
+	}
+	categories, err := product.NewCategoryRepository(s.db).FindAll()
+	if err != nil {
+		return nil, err
+	}
+
+	productCategories := make(map[uuid.UUID]uuid.UUID, len(products))
+	for _, p := range products {
+		productCategories[p.ID] = p.CategoryID
+	}
+	names := make(map[uuid.UUID]string, len(categories))
+	for _, c := range categories {
+		names[c.ID] = c.Name
+	}
+	hierarchy := product.NewHierarchy(categories)
+
+	totals := make(map[uuid.UUID]*CategorySales)
+	for _, sale := range sales {
+		for _, line := range sale.Lines {
+			categoryID := hierarchy.Ancestor(productCategories[line.ProductID], level)
+			row, ok := totals[categoryID]
+			if !ok {
+				row = &CategorySales{
+					CategoryID: categoryID,
+					Name:       names[categoryID],
+					Level:      len(hierarchy.Path(categoryID)) - 1,
+				}
+				totals[categoryID] = row
+			}
+
+			kept := portion(line.LineTotal-line.DiscountAmount, line.Quantity, line.ReturnedQuantity, line.Quantity)
+			lineTax := portion(line.TaxAmount, line.Quantity, line.ReturnedQuantity, line.Quantity)
+			if sale.PricesIncludeTax {
+				kept -= lineTax
+			}
+			row.Quantity += line.Quantity - line.ReturnedQuantity
+			row.Sales += kept
+			row.Tax += lineTax
+		}
+	}
+
+	rows := make([]CategorySales, 0, len(totals))
+	for _, row := range totals {
+		rows = append(rows, *row)
+	}
+	slices.SortFunc(rows, func(a, b CategorySales) int { return cmp.Compare(a.Name, b.Name) })
+	return rows, nil
+}
+
+// Create validates the sale lines, decrements batch stock, prices the lines
+// from the customer's or the default price list, applies the running
+// promotions, taxes the discounted lines and persists the sale, paying it
+// when tenders are given. A kit is sold as lines for the batches of its
+// components. All changes are applied in a single
+// transaction, so a sale is either recorded with all of its stock movements
+// and payments or not at all.
+func (s *SaleService) Create(req CreateSaleRequest, user *auth.User) (*Sale, error) {
+	if err := validator.Struct(req); err != nil {
+		return nil, err
+	}
+
+	sale := &Sale{
+		ID:         uuid.New(),
+		CustomerID: req.CustomerID,
+		Status:     SaleOpen,
+		AuditFields: common.AuditFields{
+			CreatedBy: user.ID,
+			UpdatedBy: user.ID,
+		},
+	}
+
+	now := time.Now()
+	err := s.db.Transaction(func(tx *db.DB) error {
+		products := product.NewProductRepository(tx)
+		batches := inventory.NewBatchRepository(tx)
+		prices := pricelist.NewResolver(tx)
+		categories := make(map[uuid.UUID]uuid.UUID)
+		var kits []kitSale
+
+		if req.CustomerID != nil {
+			if err := checkCustomer(customer.NewCustomerRepository(tx), *req.CustomerID); err != nil {
+				return err
+			}
+		}
+		locationID, err := location.NewResolver(tx).Resolve(req.LocationID, user)
+		if err != nil {
+			return err
+		}
+		sale.LocationID = locationID
+
+		for i, line := range req.Lines {
+			p, err := products.FindByID(line.ProductID)
+			if err != nil {
+				if errors.IsNotFound(err) {
+					return lineError(i, "productId", "product not found")
+				}
+				return err
+			}
+			if !p.IsActive {
+				return lineError(i, "productId", "product is not active")
+			}
+			line.Quantity, err = p.ToBase(cmp.Or(line.Unit, p.SalesUnit), line.Quantity)
+			if err != nil {
+				return validator.Nest(fmt.Sprintf("lines[%d]", i), err)
+			}
+			taxClassID, err := products.TaxClassIDOf(p)
+			if err != nil {
+				return err
+			}
+			categories[p.ID] = p.CategoryID
+			listPrice, err := prices.ListPrice(p.ID, req.CustomerID, now)
+			if err != nil {
+				return err
+			}
+
+			entry := inventory.StockMovement{
+				Type:          inventory.MovementSale,
+				ReferenceType: "sale",
+				ReferenceID:   &sale.ID,
+				CreatedBy:     user.ID,
+			}
+			if p.IsKit() {
+				if line.BatchID != nil {
+					return lineError(i, "batchId", "kits are sold from their components' batches")
+				}
+				lines, err := s.kitLines(tx, i, p, line.Quantity, listPrice, req.CustomerID, locationID, now, entry, categories)
+				if err != nil {
+					return err
+				}
+				kits = append(kits, kitSale{lineSpan: lineSpan{first: len(sale.Lines), count: len(lines)}, kitID: p.ID, kits: line.Quantity})
+				sale.Lines = append(sale.Lines, lines...)
+				continue
+			}
+			allocations, err := allocateLine(batches, i, line, locationID, now, entry)
+			if err != nil {
+				if errors.IsConflict(err) {
+					return errors.Newf(http.StatusConflict, errors.ErrConflict,
+						"product %q: %s", p.Name, err.Error())
+				}
+				return err
+			}
+
+			// A list price applies to every batch picked; without one each
+			// batch sells at its own selling price. Batches near expiry are
+			// marked down from either.
+			for _, a := range allocations {
+				unitPrice := a.SellingPrice
+				if listPrice != nil {
+					unitPrice = listPrice.Price
+				}
+				unitPrice = inventory.MarkdownPrice(unitPrice, a.ExpiresAt, now, s.inventoryCfg)
+				sale.Lines = append(sale.Lines, SaleLine{
+					ID:         uuid.New(),
+					ProductID:  p.ID,
+					BatchID:    a.BatchID,
+					Quantity:   a.Quantity,
+					UnitPrice:  unitPrice,
+					LineTotal:  unitPrice.Mul(a.Quantity),
+					TaxClassID: taxClassID,
+				})
+			}
+		}
+
+		hierarchy, err := product.NewCategoryRepository(tx).FindHierarchy()
+		if err != nil {
+			return err
+		}
+		if err := applyPromotions(promotion.NewPromotionRepository(tx), sale, kits, categories, hierarchy, req.CouponCodes, now); err != nil {
+			return err
+		}
+		if err := applyTax(tax.NewEngine(tax.NewRateRepository(tx), s.taxCfg), sale, now); err != nil {
+			return err
+		}
+
+		if err := NewSaleRepository(tx).Create(sale); err != nil {
+			return err
+		}
+		if len(req.Tenders) == 0 {
+			return nil
+		}
+		return settle(tx, sale, req.Tenders, user)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("sale created", "sale_id", sale.ID, "total", sale.Total, "status", sale.Status, "created_by", user.ID)
+	return sale, nil
+}
+
+// Pay records the tenders paying an open sale and completes it.
+func (s *SaleService) Pay(id uuid.UUID, req PaySaleRequest, user *auth.User) (*Sale, error) {
+	if err := validator.Struct(req); err != nil {
+		return nil, err
+	}
+
+	var sale *Sale
+	err := s.db.Transaction(func(tx *db.DB) error {
+		var err error
+		sale, err = NewSaleRepository(tx).FindByID(id)
+		if err != nil {
+			return err
+		}
+		if sale.Status != SaleOpen {
+			return ErrSaleNotOpen
+		}
+		return settle(tx, sale, req.Tenders, user)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("sale paid", "sale_id", sale.ID, "total", sale.Total, "change", sale.Change, "created_by", user.ID)
+	return sale, nil
+}
+
+// kitLines takes the components of kits sold on line index of a sale out
+// of inventory at a location, and returns a line for each component batch
+// picked, taxed under the component's tax class. With a list price for the
+// kit, the kits' price is shared among the lines; otherwise each component
+// sells at its own price. categories gets the components' categories.
+func (s *SaleService) kitLines(tx *db.DB, index int, kit *product.Product, kits quantity.Quantity, kitPrice *pricelist.Resolution, customerID *uuid.UUID, locationID uuid.UUID, at time.Time, entry inventory.StockMovement, categories map[uuid.UUID]uuid.UUID) ([]SaleLine, error) {
+	products := product.NewProductRepository(tx)
+	prices := pricelist.NewResolver(tx)
+	allocation := inventory.NewAllocationService(inventory.NewBatchRepository(tx))
+
+	var lines []SaleLine
+	for _, c := range kit.Components {
+		component, err := products.FindByID(c.ComponentID)
+		if err != nil {
+			return nil, err
+		}
+		if !component.IsActive {
+			return nil, lineError(index, "productId", fmt.Sprintf("component %q is not active", component.Name))
+		}
+		taxClassID, err := products.TaxClassIDOf(component)
+		if err != nil {
+			return nil, err
+		}
+		categories[component.ID] = component.CategoryID
+		listPrice, err := prices.ListPrice(component.ID, customerID, at)
+		if err != nil {
+			return nil, err
+		}
+
+		allocations, err := allocation.Allocate(component.ID, &locationID, kits.Mul(c.Quantity), at, entry)
+		if err != nil {
+			if errors.IsConflict(err) {
+				return nil, errors.Newf(http.StatusConflict, errors.ErrConflict,
+					"product %q: component %q: %s", kit.Name, component.Name, err.Error())
+			}
+			return nil, err
+		}
+		for _, a := range allocations {
+			unitPrice := a.SellingPrice
+			if listPrice != nil {
+				unitPrice = listPrice.Price
+			}
+			unitPrice = inventory.MarkdownPrice(unitPrice, a.ExpiresAt, at, s.inventoryCfg)
+			lines = append(lines, SaleLine{
+				ID:         uuid.New(),
+				ProductID:  component.ID,
+				KitID:      &kit.ID,
+				BatchID:    a.BatchID,
+				Quantity:   a.Quantity,
+				UnitPrice:  unitPrice,
+				LineTotal:  unitPrice.Mul(a.Quantity),
+				TaxClassID: taxClassID,
+			})
+		}
+	}
+
+	if kitPrice != nil {
+		shareKitPrice(lines, kitPrice.Price.Mul(kits))
+	}
+	return lines, nil
+}
+
+// shareKitPrice shares the price of kits among their sale lines in
+// proportion to what the lines would sell for on their own, or to their
+// quantities when that is nothing, so the lines add up to it exactly.
+func shareKitPrice(lines []SaleLine, price money.Amount) {
+	shares := share(price, lines)
+	for i := range lines {
+		lines[i].LineTotal = shares[i]
+		lines[i].UnitPrice = lines[i].LineTotal.MulRat(quantity.Scale, int64(lines[i].Quantity))
+	}
+}
+
+// share splits an amount among sale lines in proportion to their line
+// totals, or to their quantities when those are nothing, so the shares add
+// up to it exactly.
+func share(amount money.Amount, lines []SaleLine) []money.Amount {
+	weights := make([]int64, len(lines))
+	var total int64
+	for i, line := range lines {
+		weights[i] = int64(line.LineTotal)
+		total += weights[i]
+	}
+	if total == 0 {
+		for i, line := range lines {
+			weights[i] = int64(line.Quantity)
+			total += weights[i]
+		}
+	}
+
+	shares := make([]money.Amount, len(lines))
+	var cumulative int64
+	var shared money.Amount
+	for i := range lines {
+		cumulative += weights[i]
+		next := amount.MulRat(cumulative, total)
+		shares[i] = next - shared
+		shared = next
+	}
+	return shares
+}
+
+// lineSpan is count lines of a sale from index first.
+type lineSpan struct {
+	first int
+	count int
+}
+
+// lines returns the sale lines in the span.
+func (s lineSpan) lines(sale *Sale) []SaleLine {
+	return sale.Lines[s.first : s.first+s.count]
+}
+
+// kitSale is the lines of a sale that sell a quantity of kits of one
+// product, a line for each component batch picked.
+type kitSale struct {
+	lineSpan
+	kitID uuid.UUID
+	kits  quantity.Quantity
+}
+
+// applyPromotions applies the promotions running at the given time to a
+// sale's lines, with the coupon codes presented, and records a use of each
+// promotion that gave a discount. Promotions see the lines of each kit sale
+// as one line of the kit, at the kits' price, so that they target the kit
+// rather than its components; a discount on it is shared among the lines.
+// categories maps the products sold to their categories, which sit in
+// hierarchy.
+func applyPromotions(repo *promotion.PromotionRepository, sale *Sale, kits []kitSale, categories map[uuid.UUID]uuid.UUID, hierarchy product.Hierarchy, couponCodes []string, at time.Time) error {
+	kitAt := make(map[int]kitSale, len(kits))
+	for _, k := range kits {
+		kitAt[k.first] = k
+	}
+
+	// Each cart line stands for the span of sale lines at the same index.
+	var cart []promotion.CartLine
+	var spans []lineSpan
+	for i := 0; i < len(sale.Lines); {
+		line := sale.Lines[i]
+		if k, ok := kitAt[i]; ok {
+			var total money.Amount
+			for _, l := range k.lines(sale) {
+				total += l.LineTotal
+			}
+			cart = append(cart, promotion.CartLine{
+				ProductID:   k.kitID,
+				CategoryIDs: hierarchy.Path(categories[k.kitID]),
+				Quantity:    k.kits,
+				UnitPrice:   total.MulRat(quantity.Scale, int64(k.kits)),
+			})
+			spans = append(spans, k.lineSpan)
+			i += k.count
+			continue
+		}
+		cart = append(cart, promotion.CartLine{
+			ProductID:   line.ProductID,
+			CategoryIDs: hierarchy.Path(categories[line.ProductID]),
+			Quantity:    line.Quantity,
+			UnitPrice:   line.UnitPrice,
+		})
+		spans = append(spans, lineSpan{first: i, count: 1})
+		i++
+	}
+
+	app, err := promotion.NewEngine(repo).Apply(cart, couponCodes, at)
+	if err != nil {
+		return err
+	}
+
+	sale.Discounts = make([]SaleDiscount, 0, len(app.Discounts))
+	for _, d := range app.Discounts {
+		lines := spans[d.Line].lines(sale)
+		for i, amount := range share(d.Amount, lines) {
+			// The kit's price may round above its lines' total
+			amount = min(amount, lines[i].LineTotal-lines[i].DiscountAmount)
+			if amount <= 0 {
+				continue
+			}
+			lines[i].DiscountAmount += amount
+			sale.DiscountTotal += amount
+			sale.Discounts = append(sale.Discounts, SaleDiscount{
+				SaleLineID:  lines[i].ID,
+				PromotionID: d.PromotionID,
+				Name:        d.Name,
+				Reason:      d.Reason,
+				Amount:      amount,
+			})
+		}
+	}
+
+	for _, id := range app.Used() {
+		if err := repo.IncrementUses(id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applyTax taxes a sale's discounted lines at the rates in effect at the
+// given time and sets the sale's total, tax total and per-rate taxes.
+func applyTax(engine *tax.Engine, sale *Sale, at time.Time) error {
+	items := make([]tax.Item, len(sale.Lines))
+	for i, line := range sale.Lines {
+		items[i] = tax.Item{ClassID: line.TaxClassID, Amount: line.LineTotal - line.DiscountAmount}
+	}
+
+	calc, err := engine.Calculate(items, at)
+	if err != nil {
+		return err
+	}
+
+	for i := range sale.Lines {
+		sale.Lines[i].TaxAmount = calc.Items[i].Tax
+	}
+	sale.Taxes = make([]SaleTax, 0, len(calc.Breakdown))
+	for _, b := range calc.Breakdown {
+		sale.Taxes = append(sale.Taxes, SaleTax{
+			TaxRateID: b.RateID,
+			Name:      b.Name,
+			Rate:      b.Rate,
+			Taxable:   b.Taxable,
+			Amount:    b.Amount,
+		})
+	}
+	sale.Total = calc.Gross
+	sale.TaxTotal = calc.Tax
+	sale.PricesIncludeTax = calc.PricesIncludeTax
+	return nil
+}
+
+// settle records the tenders paying a sale's total in the cashier's open
+// shift and completes the sale.
+func settle(tx *db.DB, sale *Sale, tenders []payment.TenderRequest, user *auth.User) error {
+	shiftID, err := openShiftID(tx, user)
+	if err != nil {
+		return err
+	}
+
+	settlement, err := payment.NewPaymentService(tx, payment.NewPaymentRepository(tx)).
+		Settle(sale.ID, sale.CustomerID, shiftID, sale.Total, tenders, user)
+	if err != nil {
+		return err
+	}
+
+	completedAt := time.Now()
+	if err := NewSaleRepository(tx).Complete(sale.ID, settlement.Change, completedAt, user.ID); err != nil {
+		return err
+	}
+
+	sale.Status = SaleCompleted
+	sale.Change = settlement.Change
+	sale.CompletedAt = &completedAt
+	sale.Payments = append(sale.Payments, settlement.Payments...)
+	return nil
+}
+
+// openShiftID returns the ID of the user's open shift, or nil if they have
+// none.
+func openShiftID(tx *db.DB, user *auth.User) (*uuid.UUID, error) {
+	open, err := shift.NewShiftRepository(tx).FindOpenByUserID(user.ID)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &open.ID, nil
+}
+
+// allocateLine takes the stock for a sale line out of inventory at a
+// location at the given time, recording each decrement as a movement built
+// from entry. Lines naming a batch are taken from that batch, which must not
+// have expired; otherwise the allocation engine picks unexpired batches.
+func allocateLine(batches *inventory.BatchRepository, index int, line CreateSaleLineRequest, locationID uuid.UUID, at time.Time, entry inventory.StockMovement) ([]inventory.Allocation, error) {
+	if line.BatchID == nil {
+		return inventory.NewAllocationService(batches).Allocate(line.ProductID, &locationID, line.Quantity, at, entry)
+	}
+
+	batch, err := batches.FindByID(*line.BatchID)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil, lineError(index, "batchId", "batch not found")
+		}
+		return nil, err
+	}
+	if batch.ProductID != line.ProductID {
+		return nil, lineError(index, "batchId", "batch does not belong to product")
+	}
+	if batch.LocationID != locationID {
+		return nil, lineError(index, "batchId", "batch is not held at the sale's location")
+	}
+	if batch.IsExpired(at) {
+		return nil, lineError(index, "batchId", "batch has expired")
+	}
+
+	entry.BatchID = batch.ID
+	entry.ProductID = batch.ProductID
+	entry.Quantity = -line.Quantity
+	if err := batches.ApplyMovement(&entry); err != nil {
+		if errors.IsConflict(err) {
+			return nil, errors.Newf(http.StatusConflict, errors.ErrConflict,
+				"insufficient stock in batch %s", batch.ID)
+		}
+		return nil, err
+	}
+
+	return []inventory.Allocation{{
+		BatchID:      batch.ID,
+		ProductID:    batch.ProductID,
+		Quantity:     line.Quantity,
+		CostPrice:    batch.CostPrice,
+		SellingPrice: batch.SellingPrice,
+		PurchasedAt:  batch.PurchasedAt,
+		ExpiresAt:    batch.ExpiresAt,
+	}}, nil
+}
+
+// checkCustomer ensures the customer exists and is active.
+func checkCustomer(repo *customer.CustomerRepository, id uuid.UUID) error {
+	c, err := repo.FindByID(id)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return validator.ValidationErrors{{Field: "customerId", Message: "customer not found"}}
+		}
+		return err
+	}
+	if !c.Active {
+		return validator.ValidationErrors{{Field: "customerId", Message: "customer is not active"}}
+	}
+	return nil
+}
+
+// lineError builds a validation error for a field of a sale line.
+func lineError(index int, field, message string) error {
+	return validator.ValidationErrors{{
+		Field:   fmt.Sprintf("lines[%d].%s", index, field),
+		Message: message,
+	}}
+}
+
+// ReturnService handles business logic for sale returns.
+type ReturnService struct {
+	db   *db.DB
+	repo *ReturnRepository
+}
+
+// NewReturnService creates a new sale return service.
+func NewReturnService(database *db.DB, repo *ReturnRepository) *ReturnService {
+	return &ReturnService{db: database, repo: repo}
+}
+
+// GetBySaleID retrieves all returns of a sale.
+func (s *ReturnService) GetBySaleID(saleID uuid.UUID) ([]SaleReturn, error) {
+	return s.repo.FindBySaleID(saleID)
+}
+
+// Create records goods returned against the lines of a sale. Restocked goods
+// go back into their original batch, damaged goods into its quarantine, and
+// the refund of what was paid for the returned goods, after discounts and
+// with tax, is issued through the original tender or as customer credit.
+func (s *ReturnService) Create(saleID uuid.UUID, req CreateSaleReturnRequest, user *auth.User) (*SaleReturn, error) {
+	if err := validator.Struct(req); err != nil {
+		return nil, err
+	}
+
+	ret := &SaleReturn{
+		ID:           uuid.New(),
+		SaleID:       saleID,
+		RefundMethod: req.RefundMethod,
+		Reason:       req.Reason,
+		AuditFields: common.AuditFields{
+			CreatedBy: user.ID,
+			UpdatedBy: user.ID,
+		},
+	}
+
+	err := s.db.Transaction(func(tx *db.DB) error {
+		sales := NewSaleRepository(tx)
+		batches := inventory.NewBatchRepository(tx)
+
+		sale, err := sales.FindByID(saleID)
+		if err != nil {
+			return err
+		}
+		if sale.Status != SaleCompleted {
+			return errors.New(http.StatusConflict, errors.ErrConflict, "only completed sales can be returned against")
+		}
+		ret.CustomerID = sale.CustomerID
+
+		saleLines := make(map[uuid.UUID]SaleLine, len(sale.Lines))
+		for _, line := range sale.Lines {
+			saleLines[line.ID] = line
+		}
+
+		for i, line := range req.Lines {
+			saleLine, ok := saleLines[line.SaleLineID]
+			if !ok {
+				return lineError(i, "saleLineId", "sale line does not belong to sale")
+			}
+
+			if err := sales.AddReturnedQuantity(saleLine.ID, line.Quantity); err != nil {
+				if errors.IsConflict(err) {
+					return errors.Newf(http.StatusConflict, errors.ErrConflict,
+						"lines[%d]: returned quantity exceeds quantity sold", i)
+				}
+				return err
+			}
+
+			if line.Disposition != DispositionDiscard {
+				movement := &inventory.StockMovement{
+					BatchID:       saleLine.BatchID,
+					ProductID:     saleLine.ProductID,
+					Type:          inventory.MovementReturn,
+					Bucket:        inventory.BucketAvailable,
+					Quantity:      line.Quantity,
+					ReferenceType: "sale_return",
+					ReferenceID:   &ret.ID,
+					CreatedBy:     user.ID,
+				}
+				if line.Disposition == DispositionQuarantine {
+					movement.Bucket = inventory.BucketQuarantine
+				}
+				if err := batches.ApplyMovement(movement); err != nil {
+					return err
+				}
+			}
+
+			from, to := saleLine.ReturnedQuantity, saleLine.ReturnedQuantity+line.Quantity
+			saleLine.ReturnedQuantity = to
+			saleLines[saleLine.ID] = saleLine
+
+			refund := portion(saleLine.LineTotal-saleLine.DiscountAmount, saleLine.Quantity, from, to)
+			lineTax := portion(saleLine.TaxAmount, saleLine.Quantity, from, to)
+			if !sale.PricesIncludeTax {
+				refund += lineTax
+			}
+			ret.Lines = append(ret.Lines, SaleReturnLine{
+				SaleLineID:   saleLine.ID,
+				ProductID:    saleLine.ProductID,
+				BatchID:      saleLine.BatchID,
+				Quantity:     line.Quantity,
+				Disposition:  line.Disposition,
+				RefundAmount: refund,
+				TaxAmount:    lineTax,
+			})
+			ret.RefundTotal += refund
+		}
+
+		if req.RefundMethod == RefundCustomerCredit {
+			if sale.CustomerID == nil {
+				return validator.ValidationErrors{{Field: "refundMethod", Message: "customer credit requires a sale with a customer"}}
+			}
+			entry := &customer.LedgerEntry{
+				CustomerID:    *sale.CustomerID,
+				Type:          customer.EntryCredit,
+				Kind:          customer.KindRefund,
+				Amount:        ret.RefundTotal,
+				ReferenceType: "sale_return",
+				ReferenceID:   &ret.ID,
+				CreatedBy:     user.ID,
+			}
+			if err := customer.NewLedgerRepository(tx).Post(entry); err != nil {
+				return err
+			}
+		}
+
+		if err := NewReturnRepository(tx).Create(ret); err != nil {
+			return err
+		}
+
+		if req.RefundMethod == RefundOriginalTender {
+			shiftID, err := openShiftID(tx, user)
+			if err != nil {
+				return err
+			}
+			refunds, err := payment.NewPaymentService(tx, payment.NewPaymentRepository(tx)).
+				Refund(saleID, ret.ID, shiftID, ret.RefundTotal, user)
+			if err != nil {
+				return err
+			}
+			ret.Refunds = refunds
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("sale return created", "return_id", ret.ID, "sale_id", saleID, "refund_total", ret.RefundTotal, "created_by", user.ID)
+	return ret, nil
+}
+
+// portion returns the part of a line amount spread over qty units that
+// falls on units from..to, counted from the first unit. It is the difference
+// of two cumulative shares, so returning a line a few units at a time
+// refunds exactly the amount.
+func portion(amount money.Amount, qty, from, to quantity.Quantity) money.Amount {
+	return amount.MulRat(int64(to), int64(qty)) - amount.MulRat(int64(from), int64(qty))
+}
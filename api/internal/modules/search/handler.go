@@ -0,0 +1,69 @@
+package search
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/shanmugharajk/go-react-web-api/api/internal/db"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/errors"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/response"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/validator"
+)
+
+// Result limits of a search.
+const (
+	defaultLimit = 20
+	maxLimit     = 100
+)
+
+// Handler handles HTTP requests for search.
+type Handler struct {
+	service *SearchService
+}
+
+// NewHandler creates a new Handler instance.
+func NewHandler(database *db.DB) *Handler {
+	repo := NewSearchRepository(database)
+	service := NewSearchService(repo)
+	return &Handler{service: service}
+}
+
+// Search handles searching products and customers by the q query
+// parameter, optionally only those of the type query parameter.
+func (h *Handler) Search(w http.ResponseWriter, r *http.Request) {
+	var resultType *ResultType
+	if typeStr := r.URL.Query().Get("type"); typeStr != "" {
+		t := ResultType(typeStr)
+		if t != TypeProduct && t != TypeCustomer {
+			response.Error(w, http.StatusBadRequest, "invalid type")
+			return
+		}
+		resultType = &t
+	}
+
+	limit := defaultLimit
+	if limitStr := r.URL.Query().Get("limit"); limitStr != "" {
+		n, err := strconv.Atoi(limitStr)
+		if err != nil || n < 1 || n > maxLimit {
+			response.Error(w, http.StatusBadRequest, "invalid limit")
+			return
+		}
+		limit = n
+	}
+
+	results, err := h.service.Search(r.URL.Query().Get("q"), resultType, limit)
+	if err != nil {
+		if validator.IsValidationError(err) {
+			response.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if errors.IsUnavailable(err) {
+			response.Error(w, http.StatusServiceUnavailable, err.Error())
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, "failed to search")
+		return
+	}
+
+	response.Success(w, results)
+}
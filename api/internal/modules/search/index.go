@@ -0,0 +1,148 @@
+package search
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/shanmugharajk/go-react-web-api/api/internal/db"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/errors"
+)
+
+// ErrUnavailable is returned when SQLite was built without FTS5, which
+// needs the sqlite_fts5 build tag.
+var ErrUnavailable = errors.New(http.StatusServiceUnavailable, errors.ErrUnavailable, "search is unavailable")
+
+// The index is a pair of FTS5 tables, one for products and one for
+// customers. Each row carries the ID of the record it indexes in an
+// unindexed column. Text is folded to lower case without diacritics, and
+// two- and three-character prefixes are indexed for type-ahead.
+const (
+	createProductIndex = `CREATE VIRTUAL TABLE IF NOT EXISTS product_search USING fts5(
+		product_id UNINDEXED, name, description, sku, barcodes,
+		tokenize = 'unicode61 remove_diacritics 2', prefix = '2 3')`
+
+	createCustomerIndex = `CREATE VIRTUAL TABLE IF NOT EXISTS customer_search USING fts5(
+		customer_id UNINDEXED, name, email, mobile,
+		tokenize = 'unicode61 remove_diacritics 2', prefix = '2 3')`
+)
+
+// productBarcodes is the barcodes column of a product: its codes separated
+// by spaces.
+const productBarcodes = `coalesce((SELECT group_concat(code, ' ') FROM product_barcodes WHERE product_id = %s), '')`
+
+// customerMobile is the mobile column of a customer: the number as entered
+// followed by its digits alone, so that it matches however it is typed.
+const customerMobile = `%[1]s || ' ' || replace(replace(replace(replace(replace(replace(%[1]s,
+	' ', ''), '-', ''), '(', ''), ')', ''), '+', ''), '.', '')`
+
+// triggers keep the index in sync with products, their barcodes and
+// customers. They are dropped when FTS5 is unavailable, as writes to the
+// tables would otherwise fail.
+var triggers = []struct {
+	name string
+	sql  string
+}{
+	{"product_search_insert", `AFTER INSERT ON products BEGIN
+		INSERT INTO product_search (product_id, name, description, sku, barcodes)
+		VALUES (new.id, new.name, new.description, coalesce(new.sku, ''), ` + fmt.Sprintf(productBarcodes, "new.id") + `);
+	END`},
+	{"product_search_update", `AFTER UPDATE OF name, description, sku ON products BEGIN
+		UPDATE product_search SET name = new.name, description = new.description, sku = coalesce(new.sku, '')
+		WHERE product_id = new.id;
+	END`},
+	{"product_search_delete", `AFTER DELETE ON products BEGIN
+		DELETE FROM product_search WHERE product_id = old.id;
+	END`},
+	{"product_search_barcode_insert", `AFTER INSERT ON product_barcodes BEGIN
+		UPDATE product_search SET barcodes = ` + fmt.Sprintf(productBarcodes, "new.product_id") + `
+		WHERE product_id = new.product_id;
+	END`},
+	{"product_search_barcode_delete", `AFTER DELETE ON product_barcodes BEGIN
+		UPDATE product_search SET barcodes = ` + fmt.Sprintf(productBarcodes, "old.product_id") + `
+		WHERE product_id = old.product_id;
+	END`},
+	{"customer_search_insert", `AFTER INSERT ON customers BEGIN
+		INSERT INTO customer_search (customer_id, name, email, mobile)
+		VALUES (new.id, new.name, new.email, ` + fmt.Sprintf(customerMobile, "new.mobile") + `);
+	END`},
+	{"customer_search_update", `AFTER UPDATE OF name, email, mobile ON customers BEGIN
+		UPDATE customer_search SET name = new.name, email = new.email, mobile = ` + fmt.Sprintf(customerMobile, "new.mobile") + `
+		WHERE customer_id = new.id;
+	END`},
+	{"customer_search_delete", `AFTER DELETE ON customers BEGIN
+		DELETE FROM customer_search WHERE customer_id = old.id;
+	END`},
+}
+
+// rebuild refills the index from the products and customers tables.
+var rebuild = []string{
+	`DELETE FROM product_search`,
+	`INSERT INTO product_search (product_id, name, description, sku, barcodes)
+		SELECT id, name, description, coalesce(sku, ''), ` + fmt.Sprintf(productBarcodes, "products.id") + `
+		FROM products`,
+	`DELETE FROM customer_search`,
+	`INSERT INTO customer_search (customer_id, name, email, mobile)
+		SELECT id, name, email, ` + fmt.Sprintf(customerMobile, "mobile") + `
+		FROM customers`,
+}
+
+// Setup creates the search index and the triggers that keep it in sync,
+// and runs after the products and customers tables are migrated. Whenever
+// the triggers were missing, such as on first run or after running without
+// FTS5, the index is rebuilt, since it may have missed changes. Without
+// FTS5 it drops the triggers and returns ErrUnavailable.
+func Setup(database *db.DB) error {
+	available, err := fts5Available(database)
+	if err != nil {
+		return fmt.Errorf("failed to check for FTS5: %w", err)
+	}
+	if !available {
+		for _, t := range triggers {
+			if err := database.Exec("DROP TRIGGER IF EXISTS " + t.name).Error; err != nil {
+				return fmt.Errorf("failed to drop trigger %s: %w", t.name, err)
+			}
+		}
+		return ErrUnavailable
+	}
+
+	return database.Transaction(func(tx *db.DB) error {
+		for _, sql := range []string{createProductIndex, createCustomerIndex} {
+			if err := tx.Exec(sql).Error; err != nil {
+				return fmt.Errorf("failed to create search index: %w", err)
+			}
+		}
+
+		stale := false
+		for _, t := range triggers {
+			var count int64
+			if err := tx.Raw("SELECT count(*) FROM sqlite_master WHERE type = 'trigger' AND name = ?", t.name).
+				Scan(&count).Error; err != nil {
+				return fmt.Errorf("failed to check trigger %s: %w", t.name, err)
+			}
+			if count > 0 {
+				continue
+			}
+			if err := tx.Exec("CREATE TRIGGER " + t.name + " " + t.sql).Error; err != nil {
+				return fmt.Errorf("failed to create trigger %s: %w", t.name, err)
+			}
+			stale = true
+		}
+		if !stale {
+			return nil
+		}
+
+		for _, sql := range rebuild {
+			if err := tx.Exec(sql).Error; err != nil {
+				return fmt.Errorf("failed to rebuild search index: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+// fts5Available reports whether SQLite was built with FTS5.
+func fts5Available(database *db.DB) (bool, error) {
+	var used bool
+	err := database.Raw("SELECT sqlite_compileoption_used('ENABLE_FTS5')").Scan(&used).Error
+	return used, err
+}
@@ -0,0 +1,27 @@
+package search
+
+import (
+	"github.com/google/uuid"
+)
+
+// ResultType is the kind of record a search result is.
+type ResultType string
+
+// Result types.
+const (
+	TypeProduct  ResultType = "product"
+	TypeCustomer ResultType = "customer"
+)
+
+// Result is a product or customer matching a search. Score is how well it
+// matches, higher being better, and orders results of both types together.
+// SKU is only set for products, and email and mobile only for customers.
+type Result struct {
+	Type   ResultType `json:"type"`
+	ID     uuid.UUID  `json:"id"`
+	Name   string     `json:"name"`
+	SKU    string     `json:"sku,omitempty"`
+	Email  string     `json:"email,omitempty"`
+	Mobile string     `json:"mobile,omitempty"`
+	Score  float64    `json:"score"`
+}
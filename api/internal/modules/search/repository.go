@@ -0,0 +1,51 @@
+package search
+
+import (
+	"github.com/shanmugharajk/go-react-web-api/api/internal/db"
+)
+
+// SearchRepository handles queries against the search index.
+type SearchRepository struct {
+	db *db.DB
+}
+
+// NewSearchRepository creates a new SearchRepository instance.
+func NewSearchRepository(database *db.DB) *SearchRepository {
+	return &SearchRepository{db: database}
+}
+
+// Available reports whether the search index can be queried.
+func (r *SearchRepository) Available() (bool, error) {
+	return fts5Available(r.db)
+}
+
+// FindProducts returns the active products matching an FTS5 query, best
+// first. A match in the name counts the most, then the SKU and barcodes,
+// then the description.
+func (r *SearchRepository) FindProducts(match string, limit int) ([]Result, error) {
+	var results []Result
+	err := r.db.Raw(`
+		SELECT 'product' AS type, p.id, p.name, coalesce(p.sku, '') AS sku,
+			-bm25(product_search, 0, 10, 1, 5, 5) AS score
+		FROM product_search
+		JOIN products p ON p.id = product_search.product_id
+		WHERE product_search MATCH ? AND p.is_active = true
+		ORDER BY score DESC
+		LIMIT ?`, match, limit).Scan(&results).Error
+	return results, err
+}
+
+// FindCustomers returns the active customers matching an FTS5 query, best
+// first. A match in the name counts the most.
+func (r *SearchRepository) FindCustomers(match string, limit int) ([]Result, error) {
+	var results []Result
+	err := r.db.Raw(`
+		SELECT 'customer' AS type, c.id, c.name, c.email, c.mobile,
+			-bm25(customer_search, 0, 10, 5, 5) AS score
+		FROM customer_search
+		JOIN customers c ON c.id = customer_search.customer_id
+		WHERE customer_search MATCH ? AND c.active = true
+		ORDER BY score DESC
+		LIMIT ?`, match, limit).Scan(&results).Error
+	return results, err
+}
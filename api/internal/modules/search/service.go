@@ -0,0 +1,75 @@
+package search
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/validator"
+)
+
+// maxTerms is how many words of a query are searched for.
+const maxTerms = 8
+
+// SearchService handles searching products and customers.
+type SearchService struct {
+	repo *SearchRepository
+}
+
+// NewSearchService creates a new SearchService instance.
+func NewSearchService(repo *SearchRepository) *SearchService {
+	return &SearchService{repo: repo}
+}
+
+// Search returns up to limit products and customers matching a query, best
+// first, or only those of one type when resultType is set. A result
+// matches when it has every word of the query, each as the start of a word,
+// so partly typed words match as the user types.
+func (s *SearchService) Search(q string, resultType *ResultType, limit int) ([]Result, error) {
+	match := matchQuery(q)
+	if match == "" {
+		return nil, validator.ValidationErrors{{Field: "q", Message: "must contain a letter or digit"}}
+	}
+
+	available, err := s.repo.Available()
+	if err != nil {
+		return nil, err
+	}
+	if !available {
+		return nil, ErrUnavailable
+	}
+
+	results := []Result{}
+	if resultType == nil || *resultType == TypeProduct {
+		products, err := s.repo.FindProducts(match, limit)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, products...)
+	}
+	if resultType == nil || *resultType == TypeCustomer {
+		customers, err := s.repo.FindCustomers(match, limit)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, customers...)
+	}
+
+	slices.SortStableFunc(results, func(a, b Result) int { return cmp.Compare(b.Score, a.Score) })
+	return results[:min(len(results), limit)], nil
+}
+
+// matchQuery turns what a user typed into an FTS5 query for rows having
+// every word of it as a prefix. Words are split the way the index splits
+// text and quoted, so nothing typed is taken as FTS5 syntax.
+func matchQuery(q string) string {
+	words := strings.FieldsFunc(q, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+	words = words[:min(len(words), maxTerms)]
+	for i, w := range words {
+		words[i] = `"` + w + `"*`
+	}
+	return strings.Join(words, " ")
+}
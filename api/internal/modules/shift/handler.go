@@ -0,0 +1,249 @@
+package shift
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/db"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/modules/auth"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/errors"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/response"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/validator"
+)
+
+// Handler handles HTTP requests for shifts.
+type Handler struct {
+	service *ShiftService
+}
+
+// NewHandler creates a new shift handler.
+func NewHandler(database *db.DB) *Handler {
+	repo := NewShiftRepository(database)
+	service := NewShiftService(database, repo)
+	return &Handler{service: service}
+}
+
+// Routes returns the shift routes.
+func (h *Handler) Routes() chi.Router {
+	r := chi.NewRouter()
+	r.Get("/", h.GetAll)
+	r.Post("/", h.Open)
+	r.Get("/current", h.GetCurrent)
+	r.Get("/{id}", h.GetByID)
+	r.Post("/{id}/cash-movements", h.AddCashMovement)
+	r.Post("/{id}/close", h.Close)
+	return r
+}
+
+// GetAll handles retrieving all shifts.
+func (h *Handler) GetAll(w http.ResponseWriter, r *http.Request) {
+	shifts, err := h.service.GetAll()
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to retrieve shifts")
+		return
+	}
+
+	response.Success(w, shifts)
+}
+
+// GetByID handles retrieving a shift by ID.
+func (h *Handler) GetByID(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid shift ID")
+		return
+	}
+
+	shift, err := h.service.GetByID(id)
+	if err != nil {
+		response.Error(w, http.StatusNotFound, "shift not found")
+		return
+	}
+
+	response.Success(w, shift)
+}
+
+// GetCurrent handles retrieving the open shift of the current user.
+func (h *Handler) GetCurrent(w http.ResponseWriter, r *http.Request) {
+	user, err := auth.GetUserFromContext(r.Context())
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to retrieve user")
+		return
+	}
+
+	shift, err := h.service.GetCurrent(user)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			response.Error(w, http.StatusNotFound, "no open shift")
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, "failed to retrieve shift")
+		return
+	}
+
+	response.Success(w, shift)
+}
+
+// Open handles opening a shift for the current user.
+func (h *Handler) Open(w http.ResponseWriter, r *http.Request) {
+	var req OpenShiftRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	user, err := auth.GetUserFromContext(r.Context())
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to retrieve user")
+		return
+	}
+
+	shift, err := h.service.Open(req, user)
+	if err != nil {
+		if validator.IsValidationError(err) {
+			response.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if errors.IsConflict(err) {
+			response.Error(w, http.StatusConflict, err.Error())
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, "failed to open shift")
+		return
+	}
+
+	response.Created(w, shift)
+}
+
+// AddCashMovement handles recording a payout or drop from a shift's drawer.
+func (h *Handler) AddCashMovement(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid shift ID")
+		return
+	}
+
+	var req CreateCashMovementRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	user, err := auth.GetUserFromContext(r.Context())
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to retrieve user")
+		return
+	}
+
+	movement, err := h.service.AddCashMovement(id, req, user)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			response.Error(w, http.StatusNotFound, "shift not found")
+			return
+		}
+		if validator.IsValidationError(err) {
+			response.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if errors.IsForbidden(err) {
+			response.Error(w, http.StatusForbidden, err.Error())
+			return
+		}
+		if errors.IsConflict(err) {
+			response.Error(w, http.StatusConflict, err.Error())
+			return
+		}
+		if errors.IsUnprocessable(err) {
+			response.Error(w, http.StatusUnprocessableEntity, err.Error())
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, "failed to record cash movement")
+		return
+	}
+
+	response.Created(w, movement)
+}
+
+// Close handles closing a shift against the counted drawer.
+func (h *Handler) Close(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid shift ID")
+		return
+	}
+
+	var req CloseShiftRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	user, err := auth.GetUserFromContext(r.Context())
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to retrieve user")
+		return
+	}
+
+	shift, err := h.service.Close(id, req, user)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			response.Error(w, http.StatusNotFound, "shift not found")
+			return
+		}
+		if validator.IsValidationError(err) {
+			response.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if errors.IsForbidden(err) {
+			response.Error(w, http.StatusForbidden, err.Error())
+			return
+		}
+		if errors.IsConflict(err) {
+			response.Error(w, http.StatusConflict, err.Error())
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, "failed to close shift")
+		return
+	}
+
+	response.Success(w, shift)
+}
+
+// ZReportHandler handles HTTP requests for Z-reports.
+type ZReportHandler struct {
+	service *ZReportService
+}
+
+// NewZReportHandler creates a new Z-report handler.
+func NewZReportHandler(database *db.DB) *ZReportHandler {
+	repo := NewZReportRepository(database)
+	service := NewZReportService(repo)
+	return &ZReportHandler{service: service}
+}
+
+// GetByShiftID handles retrieving the Z-report of a shift for reprinting.
+func (h *ZReportHandler) GetByShiftID(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid shift ID")
+		return
+	}
+
+	report, err := h.service.GetByShiftID(id)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			response.Error(w, http.StatusNotFound, "z-report not found")
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, "failed to retrieve z-report")
+		return
+	}
+
+	response.Success(w, report)
+}
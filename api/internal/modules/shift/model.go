@@ -0,0 +1,126 @@
+package shift
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/common"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/modules/payment"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/money"
+)
+
+// Status is the lifecycle state of a shift.
+type Status string
+
+// Shift statuses.
+const (
+	StatusOpen   Status = "open"
+	StatusClosed Status = "closed"
+)
+
+// Shift is a cashier's session on a cash drawer. Cash tenders, payouts and
+// drops are tied to the shift they were taken in, and closing it reconciles
+// the drawer against what was counted.
+type Shift struct {
+	ID           uuid.UUID    `gorm:"type:char(36);primaryKey" json:"id"`
+	UserID       uuid.UUID    `gorm:"type:char(36);index;not null" json:"userId"`
+	Status       Status       `gorm:"not null;index" json:"status"`
+	OpeningFloat money.Amount `gorm:"not null" json:"openingFloat"`
+	OpenedAt     time.Time    `gorm:"not null" json:"openedAt"`
+	ClosedAt     *time.Time   `json:"closedAt"`
+	Variance     money.Amount `gorm:"not null;default:0" json:"variance"`
+
+	Totals        []ShiftTotal   `gorm:"foreignKey:ShiftID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"totals"`
+	CashMovements []CashMovement `gorm:"foreignKey:ShiftID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"cashMovements"`
+
+	common.AuditFields
+}
+
+// ShiftTotal is the expected and counted amount of one tender type at the
+// close of a shift.
+type ShiftTotal struct {
+	ID       uuid.UUID      `gorm:"type:char(36);primaryKey" json:"id"`
+	ShiftID  uuid.UUID      `gorm:"type:char(36);index;not null" json:"shiftId"`
+	Method   payment.Method `gorm:"not null" json:"method"`
+	Expected money.Amount   `gorm:"not null" json:"expected"`
+	Counted  money.Amount   `gorm:"not null" json:"counted"`
+	Variance money.Amount   `gorm:"not null" json:"variance"`
+}
+
+// CashMovementType is the reason cash leaves the drawer outside a sale.
+type CashMovementType string
+
+// Cash movement types.
+const (
+	CashPayout CashMovementType = "payout"
+	CashDrop   CashMovementType = "drop"
+)
+
+// CashMovement is cash taken out of the drawer during a shift: a payout to
+// a third party or a drop to the safe.
+type CashMovement struct {
+	ID        uuid.UUID        `gorm:"type:char(36);primaryKey" json:"id"`
+	ShiftID   uuid.UUID        `gorm:"type:char(36);index;not null" json:"shiftId"`
+	Type      CashMovementType `gorm:"not null" json:"type"`
+	Amount    money.Amount     `gorm:"not null" json:"amount"`
+	Reason    string           `gorm:"not null" json:"reason"`
+	CreatedAt time.Time        `json:"createdAt"`
+	CreatedBy uuid.UUID        `gorm:"type:char(36)" json:"createdBy"`
+}
+
+// ZReport is the end-of-shift report, stored as an immutable snapshot so it
+// can be reprinted exactly as it was issued. Reports are numbered in the
+// order shifts are closed.
+type ZReport struct {
+	ID        uuid.UUID       `gorm:"type:char(36);primaryKey" json:"id"`
+	ShiftID   uuid.UUID       `gorm:"type:char(36);uniqueIndex;not null" json:"shiftId"`
+	Number    int             `gorm:"uniqueIndex;not null" json:"number"`
+	Snapshot  json.RawMessage `gorm:"type:text;not null" json:"snapshot"`
+	CreatedAt time.Time       `json:"createdAt"`
+	CreatedBy uuid.UUID       `gorm:"type:char(36)" json:"createdBy"`
+
+	Shift Shift `gorm:"foreignKey:ShiftID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:RESTRICT;" json:"-"`
+}
+
+// ZReportSnapshot is the content of a Z-report.
+type ZReportSnapshot struct {
+	Number        int            `json:"number"`
+	ShiftID       uuid.UUID      `json:"shiftId"`
+	UserID        uuid.UUID      `json:"userId"`
+	OpenedAt      time.Time      `json:"openedAt"`
+	ClosedAt      time.Time      `json:"closedAt"`
+	OpeningFloat  money.Amount   `json:"openingFloat"`
+	SaleCount     int            `json:"saleCount"`
+	RefundCount   int            `json:"refundCount"`
+	Payouts       money.Amount   `json:"payouts"`
+	Drops         money.Amount   `json:"drops"`
+	Totals        []ShiftTotal   `json:"totals"`
+	CashMovements []CashMovement `json:"cashMovements"`
+	Variance      money.Amount   `json:"variance"`
+}
+
+// OpenShiftRequest represents a request to open a shift.
+type OpenShiftRequest struct {
+	OpeningFloat money.Amount `json:"openingFloat" validate:"gte=0"`
+}
+
+// CreateCashMovementRequest represents cash taken out of the drawer.
+type CreateCashMovementRequest struct {
+	Type   CashMovementType `json:"type" validate:"required,oneof=payout drop"`
+	Amount money.Amount     `json:"amount" validate:"required,gt=0"`
+	Reason string           `json:"reason" validate:"required,min=1,max=1000"`
+}
+
+// CloseShiftRequest represents the counts taken when closing a shift.
+// Cash must be counted; card and gift voucher counts are the totals of the
+// slips in the drawer.
+type CloseShiftRequest struct {
+	Counts []CountRequest `json:"counts" validate:"required,min=1,dive"`
+}
+
+// CountRequest is the counted amount of one tender type.
+type CountRequest struct {
+	Method payment.Method `json:"method" validate:"required,oneof=cash card gift_voucher"`
+	Amount money.Amount   `json:"amount" validate:"gte=0"`
+}
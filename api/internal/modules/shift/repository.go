@@ -0,0 +1,168 @@
+package shift
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/db"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/errors"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/money"
+	"gorm.io/gorm"
+)
+
+// ErrShiftClosed is returned when changing a shift that has been closed.
+var ErrShiftClosed = errors.New(http.StatusConflict, errors.ErrConflict, "shift is closed")
+
+// ShiftRepository handles data access for shifts.
+type ShiftRepository struct {
+	db *db.DB
+}
+
+// NewShiftRepository creates a new shift repository.
+func NewShiftRepository(database *db.DB) *ShiftRepository {
+	return &ShiftRepository{db: database}
+}
+
+// FindAll retrieves all shifts, most recently opened first.
+func (r *ShiftRepository) FindAll() ([]Shift, error) {
+	var shifts []Shift
+	if err := r.db.Order("opened_at DESC").Find(&shifts).Error; err != nil {
+		return nil, err
+	}
+	return shifts, nil
+}
+
+// FindByID retrieves a shift with its totals and cash movements by ID.
+func (r *ShiftRepository) FindByID(id uuid.UUID) (*Shift, error) {
+	var shift Shift
+	err := r.db.Preload("Totals").
+		Preload("CashMovements", orderByCreatedAt).
+		First(&shift, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &shift, nil
+}
+
+// FindOpenByUserID retrieves the open shift of a user.
+func (r *ShiftRepository) FindOpenByUserID(userID uuid.UUID) (*Shift, error) {
+	var shift Shift
+	err := r.db.Preload("CashMovements", orderByCreatedAt).
+		Where("user_id = ? AND status = ?", userID, StatusOpen).
+		First(&shift).Error
+	if err != nil {
+		return nil, err
+	}
+	return &shift, nil
+}
+
+// orderByCreatedAt orders preloaded cash movements by the time they were taken.
+func orderByCreatedAt(tx *gorm.DB) *gorm.DB {
+	return tx.Order("created_at ASC")
+}
+
+// Create creates a new shift.
+func (r *ShiftRepository) Create(shift *Shift) error {
+	if shift.ID == uuid.Nil {
+		shift.ID = uuid.New()
+	}
+	return r.db.Create(shift).Error
+}
+
+// Close marks an open shift as closed and records its totals, failing with
+// ErrShiftClosed if it has been closed in the meantime.
+func (r *ShiftRepository) Close(shift *Shift, closedAt time.Time) error {
+	result := r.db.Model(&Shift{}).
+		Where("id = ? AND status = ?", shift.ID, StatusOpen).
+		Updates(map[string]any{
+			"status":     StatusClosed,
+			"closed_at":  closedAt,
+			"variance":   shift.Variance,
+			"updated_by": shift.UpdatedBy,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrShiftClosed
+	}
+
+	for i := range shift.Totals {
+		if shift.Totals[i].ID == uuid.Nil {
+			shift.Totals[i].ID = uuid.New()
+		}
+		shift.Totals[i].ShiftID = shift.ID
+	}
+	if len(shift.Totals) == 0 {
+		return nil
+	}
+	return r.db.Create(&shift.Totals).Error
+}
+
+// CreateCashMovement records cash taken out of the drawer.
+func (r *ShiftRepository) CreateCashMovement(movement *CashMovement) error {
+	if movement.ID == uuid.Nil {
+		movement.ID = uuid.New()
+	}
+	return r.db.Create(movement).Error
+}
+
+// ZReportRepository handles data access for Z-reports.
+type ZReportRepository struct {
+	db *db.DB
+}
+
+// NewZReportRepository creates a new Z-report repository.
+func NewZReportRepository(database *db.DB) *ZReportRepository {
+	return &ZReportRepository{db: database}
+}
+
+// FindByShiftID retrieves the Z-report of a shift.
+func (r *ZReportRepository) FindByShiftID(shiftID uuid.UUID) (*ZReport, error) {
+	var report ZReport
+	if err := r.db.Where("shift_id = ?", shiftID).First(&report).Error; err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+// NextNumber returns the number the next Z-report is issued under.
+func (r *ZReportRepository) NextNumber() (int, error) {
+	var last int
+	if err := r.db.Model(&ZReport{}).Select("COALESCE(MAX(number), 0)").Scan(&last).Error; err != nil {
+		return 0, err
+	}
+	return last + 1, nil
+}
+
+// Create stores a Z-report. Reports are never updated once stored.
+func (r *ZReportRepository) Create(report *ZReport) error {
+	if report.ID == uuid.Nil {
+		report.ID = uuid.New()
+	}
+	return r.db.Omit("Shift").Create(report).Error
+}
+
+// MigrateMoneyToMinorUnits converts shift amounts stored as decimals to
+// exact minor units. Z-report snapshots keep the decimal amounts they were
+// issued with.
+var MigrateMoneyToMinorUnits = db.Migration{
+	ID: "20261016_shift_money_minor_units",
+	Up: func(tx *db.DB) error {
+		tables := []struct {
+			name    string
+			columns []string
+		}{
+			{"shifts", []string{"opening_float", "variance"}},
+			{"shift_totals", []string{"expected", "counted", "variance"}},
+			{"cash_movements", []string{"amount"}},
+		}
+		for _, t := range tables {
+			if err := money.MigrateColumns(tx.DB, t.name, t.columns...); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+}
@@ -0,0 +1,311 @@
+package shift
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/common"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/db"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/modules/auth"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/modules/payment"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/errors"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/logger"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/money"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/validator"
+)
+
+// ShiftService handles business logic for shifts.
+type ShiftService struct {
+	db   *db.DB
+	repo *ShiftRepository
+}
+
+// NewShiftService creates a new shift service.
+func NewShiftService(database *db.DB, repo *ShiftRepository) *ShiftService {
+	return &ShiftService{db: database, repo: repo}
+}
+
+// GetAll retrieves all shifts.
+func (s *ShiftService) GetAll() ([]Shift, error) {
+	return s.repo.FindAll()
+}
+
+// GetByID retrieves a shift by ID.
+func (s *ShiftService) GetByID(id uuid.UUID) (*Shift, error) {
+	return s.repo.FindByID(id)
+}
+
+// GetCurrent retrieves the open shift of a user.
+func (s *ShiftService) GetCurrent(user *auth.User) (*Shift, error) {
+	return s.repo.FindOpenByUserID(user.ID)
+}
+
+// Open opens a shift for a user with the cash float placed in the drawer.
+// A user can have only one open shift at a time.
+func (s *ShiftService) Open(req OpenShiftRequest, user *auth.User) (*Shift, error) {
+	if err := validator.Struct(req); err != nil {
+		return nil, err
+	}
+
+	shift := &Shift{
+		UserID:       user.ID,
+		Status:       StatusOpen,
+		OpeningFloat: req.OpeningFloat,
+		OpenedAt:     time.Now(),
+		AuditFields: common.AuditFields{
+			CreatedBy: user.ID,
+			UpdatedBy: user.ID,
+		},
+	}
+
+	err := s.db.Transaction(func(tx *db.DB) error {
+		repo := NewShiftRepository(tx)
+		if _, err := repo.FindOpenByUserID(user.ID); err == nil {
+			return errors.New(http.StatusConflict, errors.ErrConflict, "user already has an open shift")
+		} else if !errors.IsNotFound(err) {
+			return err
+		}
+		return repo.Create(shift)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("shift opened", "shift_id", shift.ID, "user_id", user.ID, "opening_float", shift.OpeningFloat)
+	return shift, nil
+}
+
+// AddCashMovement records a payout or drop from the drawer of an open shift.
+// Only the cashier running the shift can take cash out of it, and never more
+// than the drawer is expected to hold.
+func (s *ShiftService) AddCashMovement(id uuid.UUID, req CreateCashMovementRequest, user *auth.User) (*CashMovement, error) {
+	if err := validator.Struct(req); err != nil {
+		return nil, err
+	}
+
+	movement := &CashMovement{
+		ShiftID:   id,
+		Type:      req.Type,
+		Amount:    req.Amount,
+		Reason:    req.Reason,
+		CreatedBy: user.ID,
+	}
+
+	err := s.db.Transaction(func(tx *db.DB) error {
+		repo := NewShiftRepository(tx)
+		shift, err := openShiftOf(repo, id, user)
+		if err != nil {
+			return err
+		}
+
+		totals, err := payment.NewPaymentRepository(tx).TotalsByShiftID(shift.ID)
+		if err != nil {
+			return err
+		}
+		if cash := expectedCash(shift, totals); movement.Amount > cash {
+			return errors.Newf(http.StatusUnprocessableEntity, errors.ErrUnprocessable,
+				"%s of %s exceeds the %s expected in the drawer", req.Type, movement.Amount, cash)
+		}
+
+		return repo.CreateCashMovement(movement)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("shift cash movement recorded", "shift_id", id, "type", req.Type, "amount", movement.Amount, "created_by", user.ID)
+	return movement, nil
+}
+
+// Close closes an open shift against the amounts counted in the drawer,
+// records the expected-vs-counted variance per tender type and issues the
+// shift's Z-report. Customer account and store credit tenders are posted to
+// the customer ledger rather than held in the drawer, so they are taken as
+// counted.
+func (s *ShiftService) Close(id uuid.UUID, req CloseShiftRequest, user *auth.User) (*Shift, error) {
+	if err := validator.Struct(req); err != nil {
+		return nil, err
+	}
+
+	counted := make(map[payment.Method]money.Amount, len(req.Counts))
+	for i, c := range req.Counts {
+		if _, ok := counted[c.Method]; ok {
+			return nil, validator.ValidationErrors{{Field: fmt.Sprintf("counts[%d].method", i), Message: "tender type is counted more than once"}}
+		}
+		counted[c.Method] = c.Amount
+	}
+	if _, ok := counted[payment.MethodCash]; !ok {
+		return nil, validator.ValidationErrors{{Field: "counts", Message: "cash must be counted"}}
+	}
+
+	var shift *Shift
+	err := s.db.Transaction(func(tx *db.DB) error {
+		repo := NewShiftRepository(tx)
+		payments := payment.NewPaymentRepository(tx)
+
+		var err error
+		shift, err = openShiftOf(repo, id, user)
+		if err != nil {
+			return err
+		}
+
+		totals, err := payments.TotalsByShiftID(shift.ID)
+		if err != nil {
+			return err
+		}
+
+		expected := map[payment.Method]money.Amount{payment.MethodCash: expectedCash(shift, totals)}
+		for _, t := range totals {
+			if t.Method != payment.MethodCash {
+				expected[t.Method] = t.Amount
+			}
+		}
+		for method := range counted {
+			if _, ok := expected[method]; !ok {
+				expected[method] = 0
+			}
+		}
+
+		shift.Variance = 0
+		for _, method := range tenderOrder {
+			exp, ok := expected[method]
+			if !ok {
+				continue
+			}
+			count, ok := counted[method]
+			if !ok && (method == payment.MethodCustomerAccount || method == payment.MethodStoreCredit) {
+				count = exp
+			}
+			total := ShiftTotal{
+				Method:   method,
+				Expected: exp,
+				Counted:  count,
+				Variance: count - exp,
+			}
+			shift.Totals = append(shift.Totals, total)
+			shift.Variance += total.Variance
+		}
+
+		closedAt := time.Now()
+		shift.UpdatedBy = user.ID
+		if err := repo.Close(shift, closedAt); err != nil {
+			return err
+		}
+		shift.Status = StatusClosed
+		shift.ClosedAt = &closedAt
+
+		return issueZReport(tx, shift, payments, user)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("shift closed", "shift_id", shift.ID, "user_id", shift.UserID, "variance", shift.Variance)
+	return shift, nil
+}
+
+// issueZReport stores the Z-report snapshot of a closed shift.
+func issueZReport(tx *db.DB, shift *Shift, payments *payment.PaymentRepository, user *auth.User) error {
+	reports := NewZReportRepository(tx)
+
+	number, err := reports.NextNumber()
+	if err != nil {
+		return err
+	}
+	sales, returns, err := payments.CountByShiftID(shift.ID)
+	if err != nil {
+		return err
+	}
+
+	snapshot := ZReportSnapshot{
+		Number:        number,
+		ShiftID:       shift.ID,
+		UserID:        shift.UserID,
+		OpenedAt:      shift.OpenedAt,
+		ClosedAt:      *shift.ClosedAt,
+		OpeningFloat:  shift.OpeningFloat,
+		SaleCount:     sales,
+		RefundCount:   returns,
+		Totals:        shift.Totals,
+		CashMovements: shift.CashMovements,
+		Variance:      shift.Variance,
+	}
+	for _, m := range shift.CashMovements {
+		switch m.Type {
+		case CashPayout:
+			snapshot.Payouts += m.Amount
+		case CashDrop:
+			snapshot.Drops += m.Amount
+		}
+	}
+
+	content, err := json.Marshal(snapshot)
+	if err != nil {
+		return err
+	}
+
+	return reports.Create(&ZReport{
+		ShiftID:   shift.ID,
+		Number:    number,
+		Snapshot:  content,
+		CreatedBy: user.ID,
+	})
+}
+
+// openShiftOf loads a shift for a change by its cashier, failing if it
+// belongs to another user or has been closed.
+func openShiftOf(repo *ShiftRepository, id uuid.UUID, user *auth.User) (*Shift, error) {
+	shift, err := repo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if shift.UserID != user.ID {
+		return nil, errors.New(http.StatusForbidden, errors.ErrForbidden, "shift belongs to another user")
+	}
+	if shift.Status != StatusOpen {
+		return nil, ErrShiftClosed
+	}
+	return shift, nil
+}
+
+// expectedCash is the cash a shift's drawer should hold: the opening float
+// plus net cash taken, less payouts and drops.
+func expectedCash(shift *Shift, totals []payment.MethodTotal) money.Amount {
+	cash := shift.OpeningFloat
+	for _, t := range totals {
+		if t.Method == payment.MethodCash {
+			cash += t.Amount
+		}
+	}
+	for _, m := range shift.CashMovements {
+		cash -= m.Amount
+	}
+	return cash
+}
+
+// tenderOrder is the order tender types are reported in.
+var tenderOrder = []payment.Method{
+	payment.MethodCash,
+	payment.MethodCard,
+	payment.MethodGiftVoucher,
+	payment.MethodStoreCredit,
+	payment.MethodCustomerAccount,
+}
+
+// ZReportService handles business logic for Z-reports.
+type ZReportService struct {
+	repo *ZReportRepository
+}
+
+// NewZReportService creates a new Z-report service.
+func NewZReportService(repo *ZReportRepository) *ZReportService {
+	return &ZReportService{repo: repo}
+}
+
+// GetByShiftID retrieves the Z-report of a closed shift.
+func (s *ZReportService) GetByShiftID(shiftID uuid.UUID) (*ZReport, error) {
+	return s.repo.FindByShiftID(shiftID)
+}
@@ -0,0 +1,237 @@
+package stocktake
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/config"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/db"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/modules/auth"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/errors"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/response"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/validator"
+)
+
+// Handler handles HTTP requests for stocktakes.
+type Handler struct {
+	service *StocktakeService
+}
+
+// NewHandler creates a new stocktake handler.
+func NewHandler(database *db.DB, inventoryCfg config.InventoryConfig) *Handler {
+	repo := NewStocktakeRepository(database)
+	service := NewStocktakeService(database, repo, inventoryCfg)
+	return &Handler{service: service}
+}
+
+// Routes returns the stocktake routes.
+func (h *Handler) Routes() chi.Router {
+	r := chi.NewRouter()
+	r.Get("/", h.GetAll)
+	r.Post("/", h.Create)
+	r.Get("/{id}", h.GetByID)
+	r.Get("/{id}/variance", h.Variance)
+	r.Post("/{id}/counts", h.SubmitCounts)
+	r.Post("/{id}/finalize", h.Finalize)
+	r.Post("/{id}/cancel", h.Cancel)
+	return r
+}
+
+// GetAll handles retrieving stocktakes, optionally filtered by the status
+// query parameter.
+func (h *Handler) GetAll(w http.ResponseWriter, r *http.Request) {
+	status := Status(r.URL.Query().Get("status"))
+	switch status {
+	case "", StatusOpen, StatusFinalized, StatusCancelled:
+	default:
+		response.Error(w, http.StatusBadRequest, "invalid stocktake status")
+		return
+	}
+
+	stocktakes, err := h.service.GetAll(status)
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to retrieve stocktakes")
+		return
+	}
+
+	response.Success(w, stocktakes)
+}
+
+// GetByID handles retrieving a stocktake by ID.
+func (h *Handler) GetByID(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid stocktake ID")
+		return
+	}
+
+	stocktake, err := h.service.GetByID(id)
+	if err != nil {
+		response.Error(w, http.StatusNotFound, "stocktake not found")
+		return
+	}
+
+	response.Success(w, stocktake)
+}
+
+// Variance handles reporting the variances of a stocktake.
+func (h *Handler) Variance(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid stocktake ID")
+		return
+	}
+
+	variances, err := h.service.Variance(id)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			response.Error(w, http.StatusNotFound, "stocktake not found")
+			return
+		}
+		if errors.IsForbidden(err) {
+			response.Error(w, http.StatusForbidden, err.Error())
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, "failed to report stocktake variances")
+		return
+	}
+
+	response.Success(w, variances)
+}
+
+// Create handles starting a stocktake.
+func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
+	var req CreateStocktakeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	user, err := auth.GetUserFromContext(r.Context())
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to retrieve user")
+		return
+	}
+
+	stocktake, err := h.service.Create(req, user)
+	if err != nil {
+		if validator.IsValidationError(err) {
+			response.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, "failed to create stocktake")
+		return
+	}
+
+	response.Created(w, stocktake)
+}
+
+// SubmitCounts handles recording the quantities a counter found.
+func (h *Handler) SubmitCounts(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid stocktake ID")
+		return
+	}
+
+	var req SubmitCountsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	user, err := auth.GetUserFromContext(r.Context())
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to retrieve user")
+		return
+	}
+
+	stocktake, err := h.service.SubmitCounts(id, req, user)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			response.Error(w, http.StatusNotFound, "stocktake not found")
+			return
+		}
+		if validator.IsValidationError(err) {
+			response.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if errors.IsConflict(err) {
+			response.Error(w, http.StatusConflict, err.Error())
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, "failed to submit counts")
+		return
+	}
+
+	response.Success(w, stocktake)
+}
+
+// Finalize handles finalizing a stocktake and posting its variances.
+func (h *Handler) Finalize(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid stocktake ID")
+		return
+	}
+
+	user, err := auth.GetUserFromContext(r.Context())
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to retrieve user")
+		return
+	}
+
+	variances, err := h.service.Finalize(id, user)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			response.Error(w, http.StatusNotFound, "stocktake not found")
+			return
+		}
+		if errors.IsConflict(err) {
+			response.Error(w, http.StatusConflict, err.Error())
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, "failed to finalize stocktake")
+		return
+	}
+
+	response.Success(w, variances)
+}
+
+// Cancel handles abandoning a stocktake.
+func (h *Handler) Cancel(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid stocktake ID")
+		return
+	}
+
+	user, err := auth.GetUserFromContext(r.Context())
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to retrieve user")
+		return
+	}
+
+	stocktake, err := h.service.Cancel(id, user)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			response.Error(w, http.StatusNotFound, "stocktake not found")
+			return
+		}
+		if errors.IsConflict(err) {
+			response.Error(w, http.StatusConflict, err.Error())
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, "failed to cancel stocktake")
+		return
+	}
+
+	response.Success(w, stocktake)
+}
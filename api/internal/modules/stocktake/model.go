@@ -0,0 +1,129 @@
+package stocktake
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/common"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/money"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/quantity"
+)
+
+// Status is where a stocktake is in its lifecycle.
+type Status string
+
+// Stocktake statuses.
+const (
+	StatusOpen      Status = "open"
+	StatusFinalized Status = "finalized"
+	StatusCancelled Status = "cancelled"
+)
+
+// Stocktake is a count session over the batches of the products of one
+// category and its subcategories, or of every product when CategoryID is not
+// set, held at one location, or at every location when LocationID is not
+// set.
+//
+// The expected quantity of every batch in scope is frozen when the session
+// starts. Counters submit what they find per batch; finalizing the session
+// posts the difference between counted and expected quantities as a stock
+// adjustment. In a blind count the expected quantities and variances are
+// hidden until the session is finalized.
+type Stocktake struct {
+	ID           uuid.UUID  `gorm:"type:char(36);primaryKey" json:"id"`
+	Name         string     `gorm:"not null" json:"name"`
+	CategoryID   *uuid.UUID `gorm:"type:char(36);index" json:"categoryId"`
+	LocationID   *uuid.UUID `gorm:"type:char(36);index" json:"locationId"`
+	Blind        bool       `gorm:"not null;default:false" json:"blind"`
+	Status       Status     `gorm:"not null;index" json:"status"`
+	FinalizedAt  *time.Time `json:"finalizedAt"`
+	FinalizedBy  *uuid.UUID `gorm:"type:char(36)" json:"finalizedBy"`
+	AdjustmentID *uuid.UUID `gorm:"type:char(36)" json:"adjustmentId"`
+	Lines        []Line     `gorm:"foreignKey:StocktakeID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"lines"`
+
+	common.AuditFields
+}
+
+// Line is one batch in a stocktake. ExpectedQuantity is the batch's
+// available quantity when the session started. CountedQuantity is the sum
+// of the counters' latest counts, or nil while nobody has counted the batch.
+type Line struct {
+	ID               uuid.UUID          `gorm:"type:char(36);primaryKey" json:"id"`
+	StocktakeID      uuid.UUID          `gorm:"type:char(36);index;not null" json:"stocktakeId"`
+	BatchID          uuid.UUID          `gorm:"type:char(36);index;not null" json:"batchId"`
+	ProductID        uuid.UUID          `gorm:"type:char(36);index;not null" json:"productId"`
+	CostPrice        money.Amount       `gorm:"not null" json:"costPrice"`
+	ExpectedQuantity *quantity.Quantity `gorm:"not null" json:"expectedQuantity"`
+	CountedQuantity  *quantity.Quantity `json:"countedQuantity"`
+}
+
+// TableName specifies the table name for the Line model.
+func (Line) TableName() string {
+	return "stocktake_lines"
+}
+
+// Count is one counter's count of a stocktake line. A counter counting a
+// batch again replaces their earlier count, so several counters can split
+// a batch between them and each correct their own figure.
+type Count struct {
+	ID          uuid.UUID         `gorm:"type:char(36);primaryKey" json:"id"`
+	StocktakeID uuid.UUID         `gorm:"type:char(36);index;not null" json:"stocktakeId"`
+	LineID      uuid.UUID         `gorm:"type:char(36);not null;uniqueIndex:idx_stocktake_counts_line_counter" json:"lineId"`
+	Quantity    quantity.Quantity `gorm:"not null" json:"quantity"`
+	CountedBy   uuid.UUID         `gorm:"type:char(36);not null;uniqueIndex:idx_stocktake_counts_line_counter" json:"countedBy"`
+	CreatedAt   time.Time         `json:"createdAt"`
+	UpdatedAt   time.Time         `json:"updatedAt"`
+
+	Line Line `gorm:"foreignKey:LineID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"-"`
+}
+
+// TableName specifies the table name for the Count model.
+func (Count) TableName() string {
+	return "stocktake_counts"
+}
+
+// VarianceReport compares the counted and expected quantities of a
+// stocktake. Uncounted lines are listed but left out of the totals and are
+// never adjusted.
+type VarianceReport struct {
+	StocktakeID   uuid.UUID         `json:"stocktakeId"`
+	Status        Status            `json:"status"`
+	AdjustmentID  *uuid.UUID        `json:"adjustmentId"`
+	Lines         []VarianceLine    `json:"lines"`
+	Counted       int               `json:"counted"`
+	Uncounted     int               `json:"uncounted"`
+	Variance      quantity.Quantity `json:"variance"`
+	VarianceValue money.Amount      `json:"varianceValue"`
+}
+
+// VarianceLine is the variance of one batch in a stocktake, valued at the
+// batch's cost price.
+type VarianceLine struct {
+	BatchID          uuid.UUID          `json:"batchId"`
+	ProductID        uuid.UUID          `json:"productId"`
+	ExpectedQuantity quantity.Quantity  `json:"expectedQuantity"`
+	CountedQuantity  *quantity.Quantity `json:"countedQuantity"`
+	Variance         quantity.Quantity  `json:"variance"`
+	VarianceValue    money.Amount       `json:"varianceValue"`
+}
+
+// CreateStocktakeRequest represents a request to start a stocktake.
+type CreateStocktakeRequest struct {
+	Name       string     `json:"name" validate:"required,min=1,max=255"`
+	CategoryID *uuid.UUID `json:"categoryId"`
+	LocationID *uuid.UUID `json:"locationId"`
+	Blind      bool       `json:"blind"`
+}
+
+// SubmitCountsRequest represents the counts a counter found.
+type SubmitCountsRequest struct {
+	Counts []CountRequest `json:"counts" validate:"required,min=1,dive"`
+}
+
+// CountRequest represents the quantity of one batch a counter found, in
+// Unit, which defaults to the product's base unit.
+type CountRequest struct {
+	BatchID  uuid.UUID         `json:"batchId" validate:"required"`
+	Unit     string            `json:"unit" validate:"max=20"`
+	Quantity quantity.Quantity `json:"quantity" validate:"gte=0"`
+}
@@ -0,0 +1,151 @@
+package stocktake
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/db"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/errors"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/quantity"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrStocktakeNotOpen is returned when counting, finalizing or cancelling a
+// stocktake that has already been finalized or cancelled.
+var ErrStocktakeNotOpen = errors.New(http.StatusConflict, errors.ErrConflict, "stocktake is not open")
+
+// StocktakeRepository handles data access for stocktakes.
+type StocktakeRepository struct {
+	db *db.DB
+}
+
+// NewStocktakeRepository creates a new stocktake repository.
+func NewStocktakeRepository(database *db.DB) *StocktakeRepository {
+	return &StocktakeRepository{db: database}
+}
+
+// FindAll retrieves stocktakes without their lines, newest first. An empty
+// status matches every stocktake.
+func (r *StocktakeRepository) FindAll(status Status) ([]Stocktake, error) {
+	var stocktakes []Stocktake
+	query := r.db.Model(&Stocktake{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if err := query.Order("created_at DESC").Find(&stocktakes).Error; err != nil {
+		return nil, err
+	}
+	return stocktakes, nil
+}
+
+// FindByID retrieves a stocktake with its lines.
+func (r *StocktakeRepository) FindByID(id uuid.UUID) (*Stocktake, error) {
+	var stocktake Stocktake
+	err := r.db.Preload("Lines", func(tx *gorm.DB) *gorm.DB {
+		return tx.Order("product_id, batch_id")
+	}).First(&stocktake, id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &stocktake, nil
+}
+
+// FindLineByBatchID retrieves the line of a stocktake that counts a batch.
+func (r *StocktakeRepository) FindLineByBatchID(stocktakeID, batchID uuid.UUID) (*Line, error) {
+	var line Line
+	if err := r.db.Where("stocktake_id = ? AND batch_id = ?", stocktakeID, batchID).First(&line).Error; err != nil {
+		return nil, err
+	}
+	return &line, nil
+}
+
+// Create creates a stocktake together with its lines.
+func (r *StocktakeRepository) Create(stocktake *Stocktake) error {
+	if stocktake.ID == uuid.Nil {
+		stocktake.ID = uuid.New()
+	}
+	for i := range stocktake.Lines {
+		if stocktake.Lines[i].ID == uuid.Nil {
+			stocktake.Lines[i].ID = uuid.New()
+		}
+	}
+	return r.db.Create(stocktake).Error
+}
+
+// SaveCount records a counter's count of a line, replacing their earlier
+// count of it, and updates the line's counted quantity.
+func (r *StocktakeRepository) SaveCount(count *Count) error {
+	if count.ID == uuid.Nil {
+		count.ID = uuid.New()
+	}
+
+	return r.db.Transaction(func(tx *db.DB) error {
+		err := tx.Omit("Line").Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "line_id"}, {Name: "counted_by"}},
+			DoUpdates: clause.AssignmentColumns([]string{"quantity", "updated_at"}),
+		}).Create(count).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Model(&Line{}).Where("id = ?", count.LineID).
+			Update("counted_quantity", tx.Model(&Count{}).Select("SUM(quantity)").Where("line_id = ?", count.LineID)).Error
+	})
+}
+
+// Finalize marks an open stocktake as finalized with the adjustment that
+// posted its variances, failing with ErrStocktakeNotOpen if it is not open.
+func (r *StocktakeRepository) Finalize(id uuid.UUID, userID uuid.UUID, adjustmentID *uuid.UUID, at time.Time) error {
+	return r.close(id, map[string]any{
+		"status":        StatusFinalized,
+		"finalized_at":  at,
+		"finalized_by":  userID,
+		"adjustment_id": adjustmentID,
+		"updated_by":    userID,
+	})
+}
+
+// Cancel marks an open stocktake as cancelled, failing with
+// ErrStocktakeNotOpen if it is not open.
+func (r *StocktakeRepository) Cancel(id uuid.UUID, userID uuid.UUID) error {
+	return r.close(id, map[string]any{
+		"status":     StatusCancelled,
+		"updated_by": userID,
+	})
+}
+
+// close writes the given columns to an open stocktake in a single
+// conditional update, so a stocktake is only ever closed once.
+func (r *StocktakeRepository) close(id uuid.UUID, columns map[string]any) error {
+	result := r.db.Model(&Stocktake{}).Where("id = ? AND status = ?", id, StatusOpen).Updates(columns)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrStocktakeNotOpen
+	}
+	return nil
+}
+
+// MigrateQuantitiesToThousandths converts expected and counted quantities
+// stored as whole units to thousandths.
+var MigrateQuantitiesToThousandths = db.Migration{
+	ID: "20261017_stocktake_quantity_thousandths",
+	Up: func(tx *db.DB) error {
+		tables := []struct {
+			name    string
+			columns []string
+		}{
+			{"stocktake_lines", []string{"expected_quantity", "counted_quantity"}},
+			{"stocktake_counts", []string{"quantity"}},
+		}
+		for _, t := range tables {
+			if err := quantity.MigrateColumns(tx.DB, t.name, t.columns...); err != nil {
+				return err
+			}
+		}
+		return nil
+	},
+}
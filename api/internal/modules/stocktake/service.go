@@ -0,0 +1,300 @@
+package stocktake
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/common"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/config"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/db"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/modules/auth"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/modules/inventory"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/modules/location"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/modules/product"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/errors"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/logger"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/validator"
+)
+
+// StocktakeService handles business logic for stocktakes.
+type StocktakeService struct {
+	db           *db.DB
+	repo         *StocktakeRepository
+	inventoryCfg config.InventoryConfig
+}
+
+// NewStocktakeService creates a new stocktake service.
+func NewStocktakeService(database *db.DB, repo *StocktakeRepository, inventoryCfg config.InventoryConfig) *StocktakeService {
+	return &StocktakeService{db: database, repo: repo, inventoryCfg: inventoryCfg}
+}
+
+// GetAll retrieves stocktakes, optionally only those with a status.
+func (s *StocktakeService) GetAll(status Status) ([]Stocktake, error) {
+	return s.repo.FindAll(status)
+}
+
+// GetByID retrieves a stocktake with its lines. The expected quantities of
+// an open blind count are left out.
+func (s *StocktakeService) GetByID(id uuid.UUID) (*Stocktake, error) {
+	stocktake, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	return blind(stocktake), nil
+}
+
+// Create starts a stocktake, freezing the expected quantity of every batch
+// in scope.
+func (s *StocktakeService) Create(req CreateStocktakeRequest, user *auth.User) (*Stocktake, error) {
+	if err := validator.Struct(req); err != nil {
+		return nil, err
+	}
+
+	stocktake := &Stocktake{
+		Name:       req.Name,
+		CategoryID: req.CategoryID,
+		LocationID: req.LocationID,
+		Blind:      req.Blind,
+		Status:     StatusOpen,
+		AuditFields: common.AuditFields{
+			CreatedBy: user.ID,
+			UpdatedBy: user.ID,
+		},
+	}
+
+	err := s.db.Transaction(func(tx *db.DB) error {
+		batches := inventory.NewBatchRepository(tx)
+
+		if req.LocationID != nil {
+			if _, err := location.NewLocationRepository(tx).FindByID(*req.LocationID); err != nil {
+				if errors.IsNotFound(err) {
+					return validator.ValidationErrors{{Field: "locationId", Message: "location not found"}}
+				}
+				return err
+			}
+		}
+
+		var inScope []inventory.ProductBatch
+		var err error
+		if req.CategoryID != nil {
+			categories := product.NewCategoryRepository(tx)
+			if _, err := categories.FindByID(*req.CategoryID); err != nil {
+				if errors.IsNotFound(err) {
+					return validator.ValidationErrors{{Field: "categoryId", Message: "category not found"}}
+				}
+				return err
+			}
+			hierarchy, err := categories.FindHierarchy()
+			if err != nil {
+				return err
+			}
+			inScope, err = batches.FindByCategoryIDs(hierarchy.Subtree(*req.CategoryID), req.LocationID)
+		} else {
+			inScope, err = batches.FindAll(req.LocationID)
+		}
+		if err != nil {
+			return err
+		}
+		if len(inScope) == 0 {
+			return validator.ValidationErrors{{Field: "categoryId", Message: "there are no batches to count"}}
+		}
+
+		stocktake.Lines = make([]Line, len(inScope))
+		for i, batch := range inScope {
+			expected := batch.QuantityAvailable
+			stocktake.Lines[i] = Line{
+				BatchID:          batch.ID,
+				ProductID:        batch.ProductID,
+				CostPrice:        batch.CostPrice,
+				ExpectedQuantity: &expected,
+			}
+		}
+
+		return NewStocktakeRepository(tx).Create(stocktake)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("stocktake started", "stocktake_id", stocktake.ID, "lines", len(stocktake.Lines), "blind", stocktake.Blind, "created_by", user.ID)
+	return blind(stocktake), nil
+}
+
+// SubmitCounts records the quantities a counter found. Each replaces the
+// counter's earlier count of the same batch.
+func (s *StocktakeService) SubmitCounts(id uuid.UUID, req SubmitCountsRequest, user *auth.User) (*Stocktake, error) {
+	if err := validator.Struct(req); err != nil {
+		return nil, err
+	}
+
+	err := s.db.Transaction(func(tx *db.DB) error {
+		stocktakes := NewStocktakeRepository(tx)
+		products := product.NewProductRepository(tx)
+
+		stocktake, err := stocktakes.FindByID(id)
+		if err != nil {
+			return err
+		}
+		if stocktake.Status != StatusOpen {
+			return ErrStocktakeNotOpen
+		}
+
+		for i, c := range req.Counts {
+			line, err := stocktakes.FindLineByBatchID(id, c.BatchID)
+			if err != nil {
+				if errors.IsNotFound(err) {
+					return validator.ValidationErrors{{
+						Field:   fmt.Sprintf("counts[%d].batchId", i),
+						Message: "batch is not part of this stocktake",
+					}}
+				}
+				return err
+			}
+			p, err := products.FindByID(line.ProductID)
+			if err != nil {
+				return err
+			}
+			counted, err := p.ToBase(c.Unit, c.Quantity)
+			if err != nil {
+				return validator.Nest(fmt.Sprintf("counts[%d]", i), err)
+			}
+
+			count := &Count{
+				StocktakeID: id,
+				LineID:      line.ID,
+				Quantity:    counted,
+				CountedBy:   user.ID,
+			}
+			if err := stocktakes.SaveCount(count); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetByID(id)
+}
+
+// Variance reports the variances of a stocktake so far. The variances of an
+// open blind count are hidden until it is finalized.
+func (s *StocktakeService) Variance(id uuid.UUID) (*VarianceReport, error) {
+	stocktake, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if stocktake.Blind && stocktake.Status == StatusOpen {
+		return nil, errors.New(http.StatusForbidden, errors.ErrForbidden, "variances of a blind count are hidden until it is finalized")
+	}
+	return report(stocktake), nil
+}
+
+// Finalize closes a stocktake and posts the variances of its counted lines
+// as a single miscount stock adjustment. Lines nobody counted are left as
+// they are.
+func (s *StocktakeService) Finalize(id uuid.UUID, user *auth.User) (*VarianceReport, error) {
+	var variances *VarianceReport
+	err := s.db.Transaction(func(tx *db.DB) error {
+		stocktakes := NewStocktakeRepository(tx)
+
+		stocktake, err := stocktakes.FindByID(id)
+		if err != nil {
+			return err
+		}
+		if stocktake.Status != StatusOpen {
+			return ErrStocktakeNotOpen
+		}
+		variances = report(stocktake)
+
+		req := inventory.CreateAdjustmentRequest{
+			Reason: inventory.ReasonMiscount,
+			Note:   fmt.Sprintf("stocktake %s", stocktake.Name),
+		}
+		for _, line := range variances.Lines {
+			if line.Variance != 0 {
+				req.Lines = append(req.Lines, inventory.AdjustmentLineRequest{
+					BatchID:  line.BatchID,
+					Quantity: line.Variance,
+				})
+			}
+		}
+		if len(req.Lines) > 0 {
+			adjustments := inventory.NewAdjustmentService(tx, inventory.NewAdjustmentRepository(tx), s.inventoryCfg)
+			adjustment, err := adjustments.Post(req, user)
+			if err != nil {
+				return err
+			}
+			variances.AdjustmentID = &adjustment.ID
+		}
+
+		if err := stocktakes.Finalize(id, user.ID, variances.AdjustmentID, time.Now()); err != nil {
+			return err
+		}
+		variances.Status = StatusFinalized
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("stocktake finalized", "stocktake_id", id, "variance", variances.Variance,
+		"variance_value", variances.VarianceValue.String(), "finalized_by", user.ID)
+	return variances, nil
+}
+
+// Cancel abandons an open stocktake without changing any stock.
+func (s *StocktakeService) Cancel(id uuid.UUID, user *auth.User) (*Stocktake, error) {
+	if _, err := s.repo.FindByID(id); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Cancel(id, user.ID); err != nil {
+		return nil, err
+	}
+
+	logger.Info("stocktake cancelled", "stocktake_id", id, "cancelled_by", user.ID)
+	return s.GetByID(id)
+}
+
+// report works out the variances of a stocktake's counted lines.
+func report(stocktake *Stocktake) *VarianceReport {
+	variances := &VarianceReport{
+		StocktakeID:  stocktake.ID,
+		Status:       stocktake.Status,
+		AdjustmentID: stocktake.AdjustmentID,
+		Lines:        make([]VarianceLine, len(stocktake.Lines)),
+	}
+	for i, line := range stocktake.Lines {
+		v := VarianceLine{
+			BatchID:          line.BatchID,
+			ProductID:        line.ProductID,
+			ExpectedQuantity: *line.ExpectedQuantity,
+			CountedQuantity:  line.CountedQuantity,
+		}
+		if line.CountedQuantity == nil {
+			variances.Uncounted++
+		} else {
+			v.Variance = *line.CountedQuantity - *line.ExpectedQuantity
+			v.VarianceValue = line.CostPrice.Mul(v.Variance)
+			variances.Counted++
+			variances.Variance += v.Variance
+			variances.VarianceValue += v.VarianceValue
+		}
+		variances.Lines[i] = v
+	}
+	return variances
+}
+
+// blind leaves out the expected quantities of an open blind count, so
+// counters cannot see what they are expected to find.
+func blind(stocktake *Stocktake) *Stocktake {
+	if stocktake.Blind && stocktake.Status == StatusOpen {
+		for i := range stocktake.Lines {
+			stocktake.Lines[i].ExpectedQuantity = nil
+		}
+	}
+	return stocktake
+}
@@ -0,0 +1,37 @@
+package supplier
+
+import (
+	"github.com/google/uuid"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/common"
+)
+
+// Supplier is a business the store buys stock from.
+type Supplier struct {
+	ID          uuid.UUID `gorm:"type:char(36);primaryKey" json:"id"`
+	Name        string    `gorm:"unique;not null" json:"name"`
+	ContactName string    `json:"contactName"`
+	Email       string    `json:"email"`
+	Phone       string    `json:"phone"`
+	Address     string    `json:"address"`
+	Active      bool      `gorm:"not null;default:true" json:"active"`
+	common.AuditFields
+}
+
+// CreateSupplierRequest represents a request to create a supplier.
+type CreateSupplierRequest struct {
+	Name        string `json:"name" validate:"required,min=1,max=255"`
+	ContactName string `json:"contactName" validate:"max=255"`
+	Email       string `json:"email" validate:"omitempty,email"`
+	Phone       string `json:"phone" validate:"max=20"`
+	Address     string `json:"address" validate:"max=1000"`
+}
+
+// UpdateSupplierRequest represents a request to update a supplier.
+type UpdateSupplierRequest struct {
+	Name        string `json:"name" validate:"required,min=1,max=255"`
+	ContactName string `json:"contactName" validate:"max=255"`
+	Email       string `json:"email" validate:"omitempty,email"`
+	Phone       string `json:"phone" validate:"max=20"`
+	Address     string `json:"address" validate:"max=1000"`
+	Active      bool   `json:"active"`
+}
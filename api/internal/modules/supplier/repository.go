@@ -0,0 +1,70 @@
+package supplier
+
+import (
+	"github.com/google/uuid"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/db"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/errors"
+)
+
+// SupplierRepository handles data access for suppliers.
+type SupplierRepository struct {
+	db *db.DB
+}
+
+// NewSupplierRepository creates a new supplier repository.
+func NewSupplierRepository(database *db.DB) *SupplierRepository {
+	return &SupplierRepository{db: database}
+}
+
+// FindAll retrieves all suppliers.
+func (r *SupplierRepository) FindAll() ([]Supplier, error) {
+	var suppliers []Supplier
+	if err := r.db.Order("name ASC").Find(&suppliers).Error; err != nil {
+		return nil, err
+	}
+	return suppliers, nil
+}
+
+// FindByID retrieves a supplier by ID.
+func (r *SupplierRepository) FindByID(id uuid.UUID) (*Supplier, error) {
+	var supplier Supplier
+	if err := r.db.First(&supplier, id).Error; err != nil {
+		return nil, err
+	}
+	return &supplier, nil
+}
+
+// FindByName retrieves the supplier with a name.
+func (r *SupplierRepository) FindByName(name string) (*Supplier, error) {
+	var supplier Supplier
+	if err := r.db.Where("name = ?", name).First(&supplier).Error; err != nil {
+		return nil, err
+	}
+	return &supplier, nil
+}
+
+// Create creates a new supplier.
+func (r *SupplierRepository) Create(supplier *Supplier) error {
+	if supplier.ID == uuid.Nil {
+		supplier.ID = uuid.New()
+	}
+	return r.db.Create(supplier).Error
+}
+
+// Update updates an existing supplier.
+func (r *SupplierRepository) Update(supplier *Supplier) error {
+	return r.db.Save(supplier).Error
+}
+
+// Delete deactivates a supplier. Suppliers are kept for the purchase
+// orders placed with them.
+func (r *SupplierRepository) Delete(id uuid.UUID) error {
+	result := r.db.Model(&Supplier{}).Where("id = ?", id).Update("active", false)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errors.ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package supplier
+
+import (
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/common"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/modules/auth"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/errors"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/logger"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/validator"
+)
+
+// SupplierService handles business logic for suppliers.
+type SupplierService struct {
+	repo *SupplierRepository
+}
+
+// NewSupplierService creates a new supplier service.
+func NewSupplierService(repo *SupplierRepository) *SupplierService {
+	return &SupplierService{repo: repo}
+}
+
+// GetAll retrieves all suppliers.
+func (s *SupplierService) GetAll() ([]Supplier, error) {
+	return s.repo.FindAll()
+}
+
+// GetByID retrieves a supplier by ID.
+func (s *SupplierService) GetByID(id uuid.UUID) (*Supplier, error) {
+	return s.repo.FindByID(id)
+}
+
+// Create creates a new supplier.
+func (s *SupplierService) Create(req CreateSupplierRequest, user *auth.User) (*Supplier, error) {
+	if err := validator.Struct(req); err != nil {
+		return nil, err
+	}
+
+	if err := s.checkName(uuid.Nil, req.Name); err != nil {
+		return nil, err
+	}
+
+	supplier := &Supplier{
+		Name:        req.Name,
+		ContactName: req.ContactName,
+		Email:       req.Email,
+		Phone:       req.Phone,
+		Address:     req.Address,
+		Active:      true,
+		AuditFields: common.AuditFields{
+			CreatedBy: user.ID,
+			UpdatedBy: user.ID,
+		},
+	}
+
+	if err := s.repo.Create(supplier); err != nil {
+		return nil, err
+	}
+
+	return supplier, nil
+}
+
+// Update updates an existing supplier.
+func (s *SupplierService) Update(id uuid.UUID, req UpdateSupplierRequest, user *auth.User) (*Supplier, error) {
+	if err := validator.Struct(req); err != nil {
+		return nil, err
+	}
+
+	supplier, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkName(id, req.Name); err != nil {
+		return nil, err
+	}
+
+	supplier.Name = req.Name
+	supplier.ContactName = req.ContactName
+	supplier.Email = req.Email
+	supplier.Phone = req.Phone
+	supplier.Address = req.Address
+	supplier.Active = req.Active
+	supplier.UpdatedBy = user.ID
+
+	if err := s.repo.Update(supplier); err != nil {
+		return nil, err
+	}
+
+	return supplier, nil
+}
+
+// Delete deactivates a supplier.
+func (s *SupplierService) Delete(id uuid.UUID, user *auth.User) error {
+	if err := s.repo.Delete(id); err != nil {
+		return err
+	}
+
+	logger.Info("supplier deactivated", "supplier_id", id, "deleted_by", user.ID)
+	return nil
+}
+
+// checkName fails with a conflict if another supplier has the name.
+func (s *SupplierService) checkName(id uuid.UUID, name string) error {
+	existing, err := s.repo.FindByName(name)
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	if existing != nil && existing.ID != id {
+		return errors.Newf(http.StatusConflict, errors.ErrConflict, "supplier %q already exists", name)
+	}
+	return nil
+}
@@ -0,0 +1,151 @@
+package tax
+
+import (
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/config"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/money"
+)
+
+// Rounding modes.
+const (
+	RoundingLine    = "line"
+	RoundingInvoice = "invoice"
+)
+
+// Engine calculates tax on items from the rates in effect at a point in
+// time, for prices that either include or exclude tax.
+type Engine struct {
+	rates *RateRepository
+	cfg   config.TaxConfig
+}
+
+// NewEngine creates a new tax engine.
+func NewEngine(rates *RateRepository, cfg config.TaxConfig) *Engine {
+	return &Engine{rates: rates, cfg: cfg}
+}
+
+// share is an item's part of the base a rate is charged on.
+type share struct {
+	index  int
+	amount money.Amount
+}
+
+// Calculate taxes the items at the rates in effect at the given time.
+//
+// Each rate is charged on the item amount when prices exclude tax, or
+// extracted from it (amount × rate / (1 + sum of the class's rates)) when
+// they include tax. With line rounding the tax of every item and rate is
+// rounded on its own; with invoice rounding it is rounded once per rate on
+// the summed base and the rounded total is spread back over the items by
+// largest remainder, so item taxes still add up to the breakdown.
+func (e *Engine) Calculate(items []Item, at time.Time) (*Calculation, error) {
+	seen := make(map[uuid.UUID]bool)
+	var classIDs []uuid.UUID
+	for _, item := range items {
+		if item.ClassID != nil && !seen[*item.ClassID] {
+			seen[*item.ClassID] = true
+			classIDs = append(classIDs, *item.ClassID)
+		}
+	}
+
+	rates, err := e.rates.FindEffective(classIDs, at.UTC())
+	if err != nil {
+		return nil, err
+	}
+	classRate := make(map[uuid.UUID]int64)
+	for _, rate := range rates {
+		classRate[rate.ClassID] += int64(rate.Rate)
+	}
+
+	calc := &Calculation{
+		PricesIncludeTax: e.cfg.PricesIncludeTax,
+		Items:            make([]ItemTax, len(items)),
+	}
+
+	members := make([][]share, len(rates))
+	for r, rate := range rates {
+		for i, item := range items {
+			if item.ClassID != nil && *item.ClassID == rate.ClassID {
+				members[r] = append(members[r], share{index: i, amount: item.Amount})
+			}
+		}
+
+		den := int64(RateScale)
+		if e.cfg.PricesIncludeTax {
+			den += classRate[rate.ClassID]
+		}
+		amounts := e.allocate(members[r], int64(rate.Rate), den)
+
+		breakdown := Breakdown{RateID: rate.ID, Name: rate.Name, Rate: rate.Rate}
+		for k, sh := range members[r] {
+			calc.Items[sh.index].Tax += amounts[k]
+			breakdown.Amount += amounts[k]
+		}
+		calc.Breakdown = append(calc.Breakdown, breakdown)
+	}
+
+	for i, item := range items {
+		it := &calc.Items[i]
+		if e.cfg.PricesIncludeTax {
+			it.Gross = item.Amount
+			it.Net = item.Amount - it.Tax
+		} else {
+			it.Net = item.Amount
+			it.Gross = item.Amount + it.Tax
+		}
+		calc.Net += it.Net
+		calc.Tax += it.Tax
+		calc.Gross += it.Gross
+	}
+
+	for r := range calc.Breakdown {
+		for _, sh := range members[r] {
+			calc.Breakdown[r].Taxable += calc.Items[sh.index].Net
+		}
+	}
+
+	return calc, nil
+}
+
+// allocate returns the tax at num/den of each share's amount, rounded per
+// the configured mode.
+func (e *Engine) allocate(shares []share, num, den int64) []money.Amount {
+	amounts := make([]money.Amount, len(shares))
+	if e.cfg.Rounding != RoundingInvoice {
+		for k, sh := range shares {
+			amounts[k] = sh.amount.MulRat(num, den)
+		}
+		return amounts
+	}
+
+	var base money.Amount
+	remainders := make([]int64, len(shares))
+	var allocated money.Amount
+	for k, sh := range shares {
+		base += sh.amount
+		exact := sh.amount.Cents() * num
+		floor := exact / den
+		if exact%den < 0 {
+			floor--
+		}
+		amounts[k] = money.Amount(floor)
+		remainders[k] = exact - floor*den
+		allocated += amounts[k]
+	}
+
+	order := make([]int, len(shares))
+	for k := range order {
+		order[k] = k
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return remainders[order[a]] > remainders[order[b]]
+	})
+	left := int(base.MulRat(num, den) - allocated)
+	for _, k := range order[:left] {
+		amounts[k]++
+	}
+	return amounts
+}
@@ -0,0 +1,169 @@
+package tax
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/common"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/money"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/period"
+)
+
+// RateScale is the number of Rate units in 100%. Rates are held exactly to
+// four decimal places of a percent, enough for rates such as 8.875%.
+const RateScale = 1_000_000
+
+// Rate is a tax rate in millionths (ten-thousandths of a percent). It is
+// written to JSON as a percentage, so 20% is 20 and 8.875% is 8.875.
+type Rate int64
+
+// String formats the rate as a percentage without trailing zeros.
+func (r Rate) String() string {
+	sign := ""
+	units := int64(r)
+	if units < 0 {
+		sign = "-"
+		units = -units
+	}
+	const perPercent = RateScale / 100
+	text := fmt.Sprintf("%s%d.%04d", sign, units/perPercent, units%perPercent)
+	return strings.TrimSuffix(strings.TrimRight(text, "0"), ".")
+}
+
+// MarshalJSON writes the rate as a JSON number in percent.
+func (r Rate) MarshalJSON() ([]byte, error) {
+	return []byte(r.String()), nil
+}
+
+// UnmarshalJSON reads a rate in percent from a JSON number or numeric
+// string without going through floating point.
+func (r *Rate) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+	text := string(data)
+	if unquoted, err := strconv.Unquote(text); err == nil {
+		text = unquoted
+	}
+
+	percent, ok := new(big.Rat).SetString(text)
+	if !ok {
+		return fmt.Errorf("invalid tax rate %q", text)
+	}
+	units := percent.Mul(percent, big.NewRat(RateScale/100, 1))
+	if !units.IsInt() || !units.Num().IsInt64() {
+		return fmt.Errorf("invalid tax rate %q: at most four decimal places", text)
+	}
+	*r = Rate(units.Num().Int64())
+	return nil
+}
+
+// Class groups the tax rates that apply to a kind of goods, such as
+// "Standard" or "Zero rated". Categories carry a class, and products may
+// override their category's.
+type Class struct {
+	ID          uuid.UUID `gorm:"type:char(36);primaryKey" json:"id"`
+	Name        string    `gorm:"unique;not null" json:"name"`
+	Description string    `json:"description"`
+
+	Rates []ClassRate `gorm:"foreignKey:ClassID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"rates"`
+
+	common.AuditFields
+}
+
+// TableName specifies the table name for the Class model.
+func (Class) TableName() string {
+	return "tax_classes"
+}
+
+// ClassRate is a named rate charged on goods of a class between two dates.
+// Several rates of a class may be in effect at once (for example a state and
+// a city tax); they are each charged on the same price, not compounded.
+// EffectiveTo is exclusive, and nil for a rate still in effect.
+type ClassRate struct {
+	ID            uuid.UUID  `gorm:"type:char(36);primaryKey" json:"id"`
+	ClassID       uuid.UUID  `gorm:"type:char(36);index;not null" json:"classId"`
+	Name          string     `gorm:"not null" json:"name"`
+	Rate          Rate       `gorm:"not null" json:"rate"`
+	EffectiveFrom time.Time  `gorm:"not null;index" json:"effectiveFrom"`
+	EffectiveTo   *time.Time `gorm:"index" json:"effectiveTo"`
+
+	common.AuditFields
+}
+
+// TableName specifies the table name for the ClassRate model.
+func (ClassRate) TableName() string {
+	return "tax_rates"
+}
+
+// Period returns the period the rate is in effect for.
+func (r ClassRate) Period() period.Period {
+	return period.Period{From: r.EffectiveFrom, To: r.EffectiveTo}
+}
+
+// EffectiveAt reports whether the rate is in effect at t.
+func (r ClassRate) EffectiveAt(t time.Time) bool {
+	return !t.Before(r.EffectiveFrom) && (r.EffectiveTo == nil || t.Before(*r.EffectiveTo))
+}
+
+// CreateClassRequest represents a request to create a tax class.
+type CreateClassRequest struct {
+	Name        string `json:"name" validate:"required,min=1,max=255"`
+	Description string `json:"description" validate:"max=1000"`
+}
+
+// UpdateClassRequest represents a request to update a tax class.
+type UpdateClassRequest struct {
+	Name        string `json:"name" validate:"required,min=1,max=255"`
+	Description string `json:"description" validate:"max=1000"`
+}
+
+// CreateRateRequest represents a request to add a rate to a tax class.
+// A rate starting while an open-ended rate of the same name is in effect
+// supersedes it from EffectiveFrom.
+type CreateRateRequest struct {
+	Name          string     `json:"name" validate:"required,min=1,max=100"`
+	Rate          Rate       `json:"rate"`
+	EffectiveFrom time.Time  `json:"effectiveFrom" validate:"required"`
+	EffectiveTo   *time.Time `json:"effectiveTo"`
+}
+
+// Item is an amount to be taxed under a class. ClassID is nil for untaxed
+// goods.
+type Item struct {
+	ClassID *uuid.UUID
+	Amount  money.Amount
+}
+
+// ItemTax is the tax on an item. Net excludes tax, Gross includes it, and
+// the item's amount is one or the other depending on the pricing mode.
+type ItemTax struct {
+	Net   money.Amount `json:"net"`
+	Tax   money.Amount `json:"tax"`
+	Gross money.Amount `json:"gross"`
+}
+
+// Breakdown is the tax charged at one rate across a calculation.
+type Breakdown struct {
+	RateID  uuid.UUID    `json:"rateId"`
+	Name    string       `json:"name"`
+	Rate    Rate         `json:"rate"`
+	Taxable money.Amount `json:"taxable"`
+	Amount  money.Amount `json:"amount"`
+}
+
+// Calculation is the result of taxing a list of items. Items is in the
+// order the items were given, and the item taxes always add up to Tax.
+type Calculation struct {
+	PricesIncludeTax bool         `json:"pricesIncludeTax"`
+	Items            []ItemTax    `json:"items"`
+	Breakdown        []Breakdown  `json:"breakdown"`
+	Net              money.Amount `json:"net"`
+	Tax              money.Amount `json:"tax"`
+	Gross            money.Amount `json:"gross"`
+}
@@ -0,0 +1,103 @@
+package tax
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/db"
+	"gorm.io/gorm"
+)
+
+// ClassRepository handles data access for tax classes.
+type ClassRepository struct {
+	db *db.DB
+}
+
+// NewClassRepository creates a new tax class repository.
+func NewClassRepository(database *db.DB) *ClassRepository {
+	return &ClassRepository{db: database}
+}
+
+// FindAll retrieves all tax classes with their rates.
+func (r *ClassRepository) FindAll() ([]Class, error) {
+	var classes []Class
+	if err := r.db.Preload("Rates", orderByEffectiveFrom).Order("name ASC").Find(&classes).Error; err != nil {
+		return nil, err
+	}
+	return classes, nil
+}
+
+// FindByID retrieves a tax class with its rates.
+func (r *ClassRepository) FindByID(id uuid.UUID) (*Class, error) {
+	var class Class
+	if err := r.db.Preload("Rates", orderByEffectiveFrom).First(&class, id).Error; err != nil {
+		return nil, err
+	}
+	return &class, nil
+}
+
+// Create creates a new tax class.
+func (r *ClassRepository) Create(class *Class) error {
+	if class.ID == uuid.Nil {
+		class.ID = uuid.New()
+	}
+	return r.db.Omit("Rates").Create(class).Error
+}
+
+// Update updates an existing tax class. Its rates are left untouched.
+func (r *ClassRepository) Update(class *Class) error {
+	return r.db.Omit("Rates").Save(class).Error
+}
+
+// RateRepository handles data access for tax rates.
+type RateRepository struct {
+	db *db.DB
+}
+
+// NewRateRepository creates a new tax rate repository.
+func NewRateRepository(database *db.DB) *RateRepository {
+	return &RateRepository{db: database}
+}
+
+// FindByClassIDAndName retrieves every period of a named rate of a class.
+func (r *RateRepository) FindByClassIDAndName(classID uuid.UUID, name string) ([]ClassRate, error) {
+	var rates []ClassRate
+	if err := r.db.Where("class_id = ? AND name = ?", classID, name).
+		Order("effective_from ASC").Find(&rates).Error; err != nil {
+		return nil, err
+	}
+	return rates, nil
+}
+
+// FindEffective retrieves the rates of the given classes in effect at t.
+func (r *RateRepository) FindEffective(classIDs []uuid.UUID, at time.Time) ([]ClassRate, error) {
+	var rates []ClassRate
+	if len(classIDs) == 0 {
+		return rates, nil
+	}
+	if err := r.db.Where("class_id IN ?", classIDs).
+		Where("effective_from <= ? AND (effective_to IS NULL OR effective_to > ?)", at, at).
+		Order("name ASC, id ASC").Find(&rates).Error; err != nil {
+		return nil, err
+	}
+	return rates, nil
+}
+
+// Create creates a new tax rate.
+func (r *RateRepository) Create(rate *ClassRate) error {
+	if rate.ID == uuid.Nil {
+		rate.ID = uuid.New()
+	}
+	return r.db.Create(rate).Error
+}
+
+// End sets the end of an open-ended rate's period.
+func (r *RateRepository) End(id uuid.UUID, at time.Time, userID uuid.UUID) error {
+	return r.db.Model(&ClassRate{}).Where("id = ? AND effective_to IS NULL", id).
+		Updates(map[string]any{"effective_to": at, "updated_by": userID}).Error
+}
+
+// orderByEffectiveFrom orders preloaded rates oldest first.
+func orderByEffectiveFrom(tx *gorm.DB) *gorm.DB {
+	return tx.Order("effective_from ASC")
+}
@@ -0,0 +1,162 @@
+package tax
+
+import (
+	"net/http"
+
+	"github.com/google/uuid"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/common"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/db"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/modules/auth"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/errors"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/logger"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/period"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/validator"
+)
+
+// ClassService handles business logic for tax classes and their rates.
+type ClassService struct {
+	db   *db.DB
+	repo *ClassRepository
+}
+
+// NewClassService creates a new tax class service.
+func NewClassService(database *db.DB, repo *ClassRepository) *ClassService {
+	return &ClassService{db: database, repo: repo}
+}
+
+// GetAll retrieves all tax classes.
+func (s *ClassService) GetAll() ([]Class, error) {
+	return s.repo.FindAll()
+}
+
+// GetByID retrieves a tax class by ID.
+func (s *ClassService) GetByID(id uuid.UUID) (*Class, error) {
+	return s.repo.FindByID(id)
+}
+
+// Create creates a new tax class.
+func (s *ClassService) Create(req CreateClassRequest, user *auth.User) (*Class, error) {
+	if err := validator.Struct(req); err != nil {
+		return nil, err
+	}
+
+	class := &Class{
+		Name:        req.Name,
+		Description: req.Description,
+		Rates:       []ClassRate{},
+		AuditFields: common.AuditFields{
+			CreatedBy: user.ID,
+			UpdatedBy: user.ID,
+		},
+	}
+
+	if err := s.repo.Create(class); err != nil {
+		return nil, err
+	}
+
+	return class, nil
+}
+
+// Update updates an existing tax class.
+func (s *ClassService) Update(id uuid.UUID, req UpdateClassRequest, user *auth.User) (*Class, error) {
+	if err := validator.Struct(req); err != nil {
+		return nil, err
+	}
+
+	class, err := s.repo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	class.Name = req.Name
+	class.Description = req.Description
+	class.UpdatedBy = user.ID
+
+	if err := s.repo.Update(class); err != nil {
+		return nil, err
+	}
+
+	return class, nil
+}
+
+// AddRate adds a rate to a tax class. Rates are never edited, so sales keep
+// the rate they were charged at: a change of rate is a new period, which
+// ends the open-ended period of the same name it supersedes. A bounded
+// period, such as a tax holiday, only interrupts the open-ended period,
+// which resumes as a new period at its end. Periods of the same name may
+// not otherwise overlap.
+func (s *ClassService) AddRate(classID uuid.UUID, req CreateRateRequest, user *auth.User) (*ClassRate, error) {
+	if err := validator.Struct(req); err != nil {
+		return nil, err
+	}
+	if req.Rate < 0 || req.Rate > RateScale {
+		return nil, validator.ValidationErrors{{Field: "rate", Message: "must be between 0 and 100"}}
+	}
+	if req.EffectiveTo != nil && !req.EffectiveTo.After(req.EffectiveFrom) {
+		return nil, validator.ValidationErrors{{Field: "effectiveTo", Message: "must be after effectiveFrom"}}
+	}
+
+	rate := &ClassRate{
+		ClassID:       classID,
+		Name:          req.Name,
+		Rate:          req.Rate,
+		EffectiveFrom: req.EffectiveFrom.UTC(),
+		EffectiveTo:   common.UTC(req.EffectiveTo),
+		AuditFields: common.AuditFields{
+			CreatedBy: user.ID,
+			UpdatedBy: user.ID,
+		},
+	}
+
+	err := s.db.Transaction(func(tx *db.DB) error {
+		if _, err := NewClassRepository(tx).FindByID(classID); err != nil {
+			return err
+		}
+
+		rates := NewRateRepository(tx)
+		periods, err := rates.FindByClassIDAndName(classID, req.Name)
+		if err != nil {
+			return err
+		}
+
+		series := make([]period.Period, len(periods))
+		for i, p := range periods {
+			series[i] = p.Period()
+		}
+		change := period.Add(series, rate.Period())
+		if change.Conflict >= 0 {
+			return errors.Newf(http.StatusConflict, errors.ErrConflict,
+				"rate %q overlaps its period from %s", req.Name, periods[change.Conflict].EffectiveFrom.Format("2006-01-02"))
+		}
+
+		if change.Superseded >= 0 {
+			superseded := periods[change.Superseded]
+			if err := rates.End(superseded.ID, rate.EffectiveFrom, user.ID); err != nil {
+				return err
+			}
+			if change.Resumes != nil {
+				resumed := &ClassRate{
+					ClassID:       classID,
+					Name:          superseded.Name,
+					Rate:          superseded.Rate,
+					EffectiveFrom: *change.Resumes,
+					AuditFields: common.AuditFields{
+						CreatedBy: user.ID,
+						UpdatedBy: user.ID,
+					},
+				}
+				if err := rates.Create(resumed); err != nil {
+					return err
+				}
+			}
+		}
+
+		return rates.Create(rate)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("tax rate added", "rate_id", rate.ID, "class_id", classID, "rate", rate.Rate.String(), "created_by", user.ID)
+	return rate, nil
+}
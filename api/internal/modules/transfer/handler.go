@@ -0,0 +1,174 @@
+package transfer
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/db"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/modules/auth"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/errors"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/response"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/validator"
+)
+
+// Handler handles HTTP requests for transfers.
+type Handler struct {
+	service *TransferService
+}
+
+// NewHandler creates a new transfer handler.
+func NewHandler(database *db.DB) *Handler {
+	repo := NewTransferRepository(database)
+	service := NewTransferService(database, repo)
+	return &Handler{service: service}
+}
+
+// Routes returns the transfer routes.
+func (h *Handler) Routes() chi.Router {
+	r := chi.NewRouter()
+	r.Get("/", h.GetAll)
+	r.Post("/", h.Create)
+	r.Get("/{id}", h.GetByID)
+	r.Post("/{id}/receive", h.Receive)
+	r.Post("/{id}/cancel", h.Cancel)
+	return r
+}
+
+// GetAll handles retrieving transfers, optionally filtered by the status
+// query parameter.
+func (h *Handler) GetAll(w http.ResponseWriter, r *http.Request) {
+	status := Status(r.URL.Query().Get("status"))
+	switch status {
+	case "", StatusInTransit, StatusReceived, StatusCancelled:
+	default:
+		response.Error(w, http.StatusBadRequest, "invalid transfer status")
+		return
+	}
+
+	transfers, err := h.service.GetAll(status)
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to retrieve transfers")
+		return
+	}
+
+	response.Success(w, transfers)
+}
+
+// GetByID handles retrieving a transfer by ID.
+func (h *Handler) GetByID(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid transfer ID")
+		return
+	}
+
+	transfer, err := h.service.GetByID(id)
+	if err != nil {
+		response.Error(w, http.StatusNotFound, "transfer not found")
+		return
+	}
+
+	response.Success(w, transfer)
+}
+
+// Create handles dispatching a transfer.
+func (h *Handler) Create(w http.ResponseWriter, r *http.Request) {
+	var req CreateTransferRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid request body")
+		return
+	}
+
+	user, err := auth.GetUserFromContext(r.Context())
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to retrieve user")
+		return
+	}
+
+	transfer, err := h.service.Create(req, user)
+	if err != nil {
+		if validator.IsValidationError(err) {
+			response.Error(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if errors.IsConflict(err) {
+			response.Error(w, http.StatusConflict, err.Error())
+			return
+		}
+		if errors.IsUnprocessable(err) {
+			response.Error(w, http.StatusUnprocessableEntity, err.Error())
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, "failed to create transfer")
+		return
+	}
+
+	response.Created(w, transfer)
+}
+
+// Receive handles receiving an in-transit transfer at its destination.
+func (h *Handler) Receive(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid transfer ID")
+		return
+	}
+
+	user, err := auth.GetUserFromContext(r.Context())
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to retrieve user")
+		return
+	}
+
+	transfer, err := h.service.Receive(id, user)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			response.Error(w, http.StatusNotFound, "transfer not found")
+			return
+		}
+		if errors.IsConflict(err) {
+			response.Error(w, http.StatusConflict, err.Error())
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, "failed to receive transfer")
+		return
+	}
+
+	response.Success(w, transfer)
+}
+
+// Cancel handles cancelling an in-transit transfer.
+func (h *Handler) Cancel(w http.ResponseWriter, r *http.Request) {
+	idStr := chi.URLParam(r, "id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		response.Error(w, http.StatusBadRequest, "invalid transfer ID")
+		return
+	}
+
+	user, err := auth.GetUserFromContext(r.Context())
+	if err != nil {
+		response.Error(w, http.StatusInternalServerError, "failed to retrieve user")
+		return
+	}
+
+	transfer, err := h.service.Cancel(id, user)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			response.Error(w, http.StatusNotFound, "transfer not found")
+			return
+		}
+		if errors.IsConflict(err) {
+			response.Error(w, http.StatusConflict, err.Error())
+			return
+		}
+		response.Error(w, http.StatusInternalServerError, "failed to cancel transfer")
+		return
+	}
+
+	response.Success(w, transfer)
+}
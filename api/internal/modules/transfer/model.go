@@ -0,0 +1,84 @@
+package transfer
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/common"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/modules/location"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/quantity"
+)
+
+// Status is where a transfer is in its lifecycle.
+type Status string
+
+// Transfer statuses.
+const (
+	StatusInTransit Status = "in_transit"
+	StatusReceived  Status = "received"
+	StatusCancelled Status = "cancelled"
+)
+
+// Transfer is a document moving stock from one location to another.
+//
+// The stock leaves its source batches when the transfer is dispatched and
+// is held on the transfer while it is in transit, counted at neither
+// location. Receiving it places each line into a new batch at the
+// destination with the source batch's prices and dates; cancelling it
+// returns the stock to the source batches.
+type Transfer struct {
+	ID             uuid.UUID  `gorm:"type:char(36);primaryKey" json:"id"`
+	FromLocationID uuid.UUID  `gorm:"type:char(36);index;not null" json:"fromLocationId"`
+	ToLocationID   uuid.UUID  `gorm:"type:char(36);index;not null" json:"toLocationId"`
+	Status         Status     `gorm:"not null;index" json:"status"`
+	Notes          string     `json:"notes"`
+	DispatchedAt   time.Time  `gorm:"not null" json:"dispatchedAt"`
+	ReceivedAt     *time.Time `json:"receivedAt"`
+	ReceivedBy     *uuid.UUID `gorm:"type:char(36)" json:"receivedBy"`
+	CancelledAt    *time.Time `json:"cancelledAt"`
+	CancelledBy    *uuid.UUID `gorm:"type:char(36)" json:"cancelledBy"`
+
+	FromLocation location.Location `gorm:"foreignKey:FromLocationID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:RESTRICT;" json:"-"`
+	ToLocation   location.Location `gorm:"foreignKey:ToLocationID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:RESTRICT;" json:"-"`
+	Lines        []Line            `gorm:"foreignKey:TransferID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"lines"`
+
+	common.AuditFields
+}
+
+// Line is a quantity taken from one source batch. DestinationBatchID is
+// the batch it was received into, set once the transfer is received.
+type Line struct {
+	ID                 uuid.UUID         `gorm:"type:char(36);primaryKey" json:"id"`
+	TransferID         uuid.UUID         `gorm:"type:char(36);index;not null" json:"transferId"`
+	ProductID          uuid.UUID         `gorm:"type:char(36);index;not null" json:"productId"`
+	BatchID            uuid.UUID         `gorm:"type:char(36);index;not null" json:"batchId"`
+	Quantity           quantity.Quantity `gorm:"not null" json:"quantity"`
+	DestinationBatchID *uuid.UUID        `gorm:"type:char(36);index" json:"destinationBatchId"`
+}
+
+// TableName specifies the table name for the Line model.
+func (Line) TableName() string {
+	return "transfer_lines"
+}
+
+// CreateTransferRequest represents a request to dispatch stock to another
+// location. Without a source location, stock is sent from the user's
+// default location.
+type CreateTransferRequest struct {
+	FromLocationID *uuid.UUID            `json:"fromLocationId"`
+	ToLocationID   uuid.UUID             `json:"toLocationId" validate:"required"`
+	Notes          string                `json:"notes" validate:"max=1000"`
+	Lines          []TransferLineRequest `json:"lines" validate:"required,min=1,dive"`
+}
+
+// TransferLineRequest represents a quantity of a product to transfer, in
+// Unit, which defaults to the product's base unit. Transfer lines record the
+// equivalent quantity of the base unit.
+// When BatchID is omitted the quantity is picked from the source location's
+// batches first-expiry-first-out and may produce several transfer lines.
+type TransferLineRequest struct {
+	ProductID uuid.UUID         `json:"productId" validate:"required"`
+	BatchID   *uuid.UUID        `json:"batchId"`
+	Unit      string            `json:"unit" validate:"max=20"`
+	Quantity  quantity.Quantity `json:"quantity" validate:"required,gt=0"`
+}
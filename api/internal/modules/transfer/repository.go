@@ -0,0 +1,102 @@
+package transfer
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/db"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/errors"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/quantity"
+)
+
+// ErrTransferNotInTransit is returned when receiving or cancelling a
+// transfer that has already been received or cancelled.
+var ErrTransferNotInTransit = errors.New(http.StatusConflict, errors.ErrConflict, "transfer is not in transit")
+
+// TransferRepository handles data access for transfers.
+type TransferRepository struct {
+	db *db.DB
+}
+
+// NewTransferRepository creates a new transfer repository.
+func NewTransferRepository(database *db.DB) *TransferRepository {
+	return &TransferRepository{db: database}
+}
+
+// FindAll retrieves transfers with their lines, newest first, optionally
+// only those with the given status.
+func (r *TransferRepository) FindAll(status Status) ([]Transfer, error) {
+	var transfers []Transfer
+	query := r.db.Preload("Lines")
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if err := query.Order("created_at DESC").Find(&transfers).Error; err != nil {
+		return nil, err
+	}
+	return transfers, nil
+}
+
+// FindByID retrieves a transfer with its lines.
+func (r *TransferRepository) FindByID(id uuid.UUID) (*Transfer, error) {
+	var transfer Transfer
+	if err := r.db.Preload("Lines").First(&transfer, id).Error; err != nil {
+		return nil, err
+	}
+	return &transfer, nil
+}
+
+// Create creates a transfer with its lines. It does not move any stock.
+func (r *TransferRepository) Create(transfer *Transfer) error {
+	if transfer.ID == uuid.Nil {
+		transfer.ID = uuid.New()
+	}
+	for i := range transfer.Lines {
+		if transfer.Lines[i].ID == uuid.Nil {
+			transfer.Lines[i].ID = uuid.New()
+		}
+	}
+	return r.db.Omit("FromLocation", "ToLocation").Create(transfer).Error
+}
+
+// SetDestinationBatch records the batch a transfer line was received into.
+func (r *TransferRepository) SetDestinationBatch(lineID, batchID uuid.UUID) error {
+	return r.db.Model(&Line{}).Where("id = ?", lineID).Update("destination_batch_id", batchID).Error
+}
+
+// Receive marks an in-transit transfer as received.
+func (r *TransferRepository) Receive(id uuid.UUID, userID uuid.UUID, at time.Time) error {
+	return r.transition(id, StatusReceived,
+		map[string]any{"received_by": userID, "received_at": at, "updated_by": userID})
+}
+
+// Cancel marks an in-transit transfer as cancelled.
+func (r *TransferRepository) Cancel(id uuid.UUID, userID uuid.UUID, at time.Time) error {
+	return r.transition(id, StatusCancelled,
+		map[string]any{"cancelled_by": userID, "cancelled_at": at, "updated_by": userID})
+}
+
+// transition moves an in-transit transfer to another status in a single
+// conditional update, so a transfer cannot be both received and cancelled.
+// It fails with ErrTransferNotInTransit if the transfer is not in transit.
+func (r *TransferRepository) transition(id uuid.UUID, to Status, columns map[string]any) error {
+	columns["status"] = to
+	result := r.db.Model(&Transfer{}).Where("id = ? AND status = ?", id, StatusInTransit).Updates(columns)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrTransferNotInTransit
+	}
+	return nil
+}
+
+// MigrateQuantitiesToThousandths converts transfer line quantities stored as
+// whole units to thousandths.
+var MigrateQuantitiesToThousandths = db.Migration{
+	ID: "20261017_transfer_quantity_thousandths",
+	Up: func(tx *db.DB) error {
+		return quantity.MigrateColumns(tx.DB, "transfer_lines", "quantity")
+	},
+}
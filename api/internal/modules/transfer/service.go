@@ -0,0 +1,294 @@
+package transfer
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/common"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/db"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/modules/auth"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/modules/inventory"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/modules/location"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/modules/product"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/errors"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/logger"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/validator"
+)
+
+// TransferService handles business logic for transfers.
+type TransferService struct {
+	db   *db.DB
+	repo *TransferRepository
+}
+
+// NewTransferService creates a new transfer service.
+func NewTransferService(database *db.DB, repo *TransferRepository) *TransferService {
+	return &TransferService{db: database, repo: repo}
+}
+
+// GetAll retrieves transfers, optionally only those with a status.
+func (s *TransferService) GetAll(status Status) ([]Transfer, error) {
+	return s.repo.FindAll(status)
+}
+
+// GetByID retrieves a transfer with its lines.
+func (s *TransferService) GetByID(id uuid.UUID) (*Transfer, error) {
+	return s.repo.FindByID(id)
+}
+
+// Create dispatches stock from one location to another. The quantities
+// leave the source batches straight away and the transfer stays in transit
+// until it is received or cancelled.
+func (s *TransferService) Create(req CreateTransferRequest, user *auth.User) (*Transfer, error) {
+	if err := validator.Struct(req); err != nil {
+		return nil, err
+	}
+
+	transfer := &Transfer{
+		ID:           uuid.New(),
+		ToLocationID: req.ToLocationID,
+		Status:       StatusInTransit,
+		Notes:        req.Notes,
+		DispatchedAt: time.Now(),
+		AuditFields: common.AuditFields{
+			CreatedBy: user.ID,
+			UpdatedBy: user.ID,
+		},
+	}
+
+	err := s.db.Transaction(func(tx *db.DB) error {
+		if req.FromLocationID != nil {
+			if err := checkLocation(tx, *req.FromLocationID, "fromLocationId"); err != nil {
+				return err
+			}
+		}
+		fromID, err := location.NewResolver(tx).Resolve(req.FromLocationID, user)
+		if err != nil {
+			return err
+		}
+		if err := checkLocation(tx, req.ToLocationID, "toLocationId"); err != nil {
+			return err
+		}
+		if fromID == req.ToLocationID {
+			return validator.ValidationErrors{{Field: "toLocationId", Message: "must differ from the source location"}}
+		}
+		transfer.FromLocationID = fromID
+
+		batches := inventory.NewBatchRepository(tx)
+		products := product.NewProductRepository(tx)
+		entry := inventory.StockMovement{
+			Type:          inventory.MovementTransfer,
+			ReferenceType: "transfer",
+			ReferenceID:   &transfer.ID,
+			Note:          req.Notes,
+			CreatedBy:     user.ID,
+		}
+		for i, line := range req.Lines {
+			p, err := products.FindByID(line.ProductID)
+			if err != nil {
+				if errors.IsNotFound(err) {
+					return lineError(i, "productId", "product not found")
+				}
+				return err
+			}
+			line.Quantity, err = p.ToBase(line.Unit, line.Quantity)
+			if err != nil {
+				return validator.Nest(fmt.Sprintf("lines[%d]", i), err)
+			}
+
+			allocations, err := dispatchLine(batches, i, line, fromID, entry)
+			if err != nil {
+				return err
+			}
+			for _, a := range allocations {
+				transfer.Lines = append(transfer.Lines, Line{
+					ProductID: a.ProductID,
+					BatchID:   a.BatchID,
+					Quantity:  a.Quantity,
+				})
+			}
+		}
+
+		return NewTransferRepository(tx).Create(transfer)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("transfer dispatched", "transfer_id", transfer.ID, "from_location_id", transfer.FromLocationID,
+		"to_location_id", transfer.ToLocationID, "lines", len(transfer.Lines), "created_by", user.ID)
+	return transfer, nil
+}
+
+// Receive places the stock of an in-transit transfer at its destination.
+// Each line is received into a new batch with the source batch's prices and
+// dates.
+func (s *TransferService) Receive(id uuid.UUID, user *auth.User) (*Transfer, error) {
+	err := s.db.Transaction(func(tx *db.DB) error {
+		transfers := NewTransferRepository(tx)
+		batches := inventory.NewBatchRepository(tx)
+
+		transfer, err := transfers.FindByID(id)
+		if err != nil {
+			return err
+		}
+		if err := transfers.Receive(id, user.ID, time.Now()); err != nil {
+			return err
+		}
+
+		for _, line := range transfer.Lines {
+			source, err := batches.FindByID(line.BatchID)
+			if err != nil {
+				if errors.IsNotFound(err) {
+					return errors.Newf(http.StatusConflict, errors.ErrConflict, "source batch %s no longer exists", line.BatchID)
+				}
+				return err
+			}
+
+			batch := &inventory.ProductBatch{
+				ProductID:    source.ProductID,
+				LocationID:   transfer.ToLocationID,
+				CostPrice:    source.CostPrice,
+				SellingPrice: source.SellingPrice,
+				PurchasedAt:  source.PurchasedAt,
+				ExpiresAt:    source.ExpiresAt,
+				AuditFields: common.AuditFields{
+					CreatedBy: user.ID,
+					UpdatedBy: user.ID,
+				},
+			}
+			if err := batches.Create(batch); err != nil {
+				return err
+			}
+			movement := &inventory.StockMovement{
+				BatchID:       batch.ID,
+				ProductID:     batch.ProductID,
+				Type:          inventory.MovementTransfer,
+				Quantity:      line.Quantity,
+				ReferenceType: "transfer",
+				ReferenceID:   &transfer.ID,
+				Note:          transfer.Notes,
+				CreatedBy:     user.ID,
+			}
+			if err := batches.ApplyMovement(movement); err != nil {
+				return err
+			}
+			if err := transfers.SetDestinationBatch(line.ID, batch.ID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("transfer received", "transfer_id", id, "received_by", user.ID)
+	return s.repo.FindByID(id)
+}
+
+// Cancel returns the stock of an in-transit transfer to its source
+// batches.
+func (s *TransferService) Cancel(id uuid.UUID, user *auth.User) (*Transfer, error) {
+	err := s.db.Transaction(func(tx *db.DB) error {
+		transfers := NewTransferRepository(tx)
+		batches := inventory.NewBatchRepository(tx)
+
+		transfer, err := transfers.FindByID(id)
+		if err != nil {
+			return err
+		}
+		if err := transfers.Cancel(id, user.ID, time.Now()); err != nil {
+			return err
+		}
+
+		for _, line := range transfer.Lines {
+			movement := &inventory.StockMovement{
+				BatchID:       line.BatchID,
+				ProductID:     line.ProductID,
+				Type:          inventory.MovementTransfer,
+				Quantity:      line.Quantity,
+				ReferenceType: "transfer",
+				ReferenceID:   &transfer.ID,
+				Note:          "transfer cancelled",
+				CreatedBy:     user.ID,
+			}
+			if err := batches.ApplyMovement(movement); err != nil {
+				if errors.IsNotFound(err) {
+					return errors.Newf(http.StatusConflict, errors.ErrConflict, "source batch %s no longer exists", line.BatchID)
+				}
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("transfer cancelled", "transfer_id", id, "cancelled_by", user.ID)
+	return s.repo.FindByID(id)
+}
+
+// dispatchLine takes the stock for a transfer line out of the source
+// location, recording each decrement as a movement built from entry. Lines
+// naming a batch are taken from that batch; otherwise the allocation engine
+// picks the batches.
+func dispatchLine(batches *inventory.BatchRepository, index int, line TransferLineRequest, fromID uuid.UUID, entry inventory.StockMovement) ([]inventory.Allocation, error) {
+	if line.BatchID == nil {
+		return inventory.NewAllocationService(batches).Allocate(line.ProductID, &fromID, line.Quantity, time.Now(), entry)
+	}
+
+	batch, err := batches.FindByID(*line.BatchID)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil, lineError(index, "batchId", "batch not found")
+		}
+		return nil, err
+	}
+	if batch.ProductID != line.ProductID {
+		return nil, lineError(index, "batchId", "batch does not belong to product")
+	}
+	if batch.LocationID != fromID {
+		return nil, lineError(index, "batchId", "batch is not held at the source location")
+	}
+
+	entry.BatchID = batch.ID
+	entry.ProductID = batch.ProductID
+	entry.Quantity = -line.Quantity
+	if err := batches.ApplyMovement(&entry); err != nil {
+		return nil, err
+	}
+
+	return []inventory.Allocation{{
+		BatchID:   batch.ID,
+		ProductID: batch.ProductID,
+		Quantity:  line.Quantity,
+	}}, nil
+}
+
+// checkLocation fails with a validation error on field unless the location
+// exists and is active.
+func checkLocation(tx *db.DB, id uuid.UUID, field string) error {
+	l, err := location.NewLocationRepository(tx).FindByID(id)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return validator.ValidationErrors{{Field: field, Message: "location not found"}}
+		}
+		return err
+	}
+	if !l.Active {
+		return validator.ValidationErrors{{Field: field, Message: "location is not active"}}
+	}
+	return nil
+}
+
+// lineError builds a validation error for a field of a transfer line.
+func lineError(index int, field, message string) error {
+	return validator.ValidationErrors{{
+		Field:   fmt.Sprintf("lines[%d].%s", index, field),
+		Message: message,
+	}}
+}
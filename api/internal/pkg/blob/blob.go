@@ -0,0 +1,101 @@
+package blob
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/errors"
+)
+
+// ErrNotFound is returned when reading a blob that is not in the store.
+// It wraps errors.ErrNotFound, so errors.IsNotFound recognises it.
+var ErrNotFound = fmt.Errorf("blob %w", errors.ErrNotFound)
+
+// Store keeps blobs under slash-separated keys. Putting a key that exists
+// replaces its blob, and deleting a key that does not exist is not an
+// error, so callers can clean up without checking first.
+type Store interface {
+	Put(key string, r io.Reader) error
+	Get(key string) (io.ReadCloser, error)
+	Delete(key string) error
+}
+
+// DiskStore is a Store that keeps each blob as a file under a directory.
+type DiskStore struct {
+	root string
+}
+
+// NewDiskStore creates a store keeping blobs under root, creating the
+// directory if it does not exist.
+func NewDiskStore(root string) (*DiskStore, error) {
+	if err := os.MkdirAll(root, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create blob directory: %w", err)
+	}
+	return &DiskStore{root: root}, nil
+}
+
+// Put writes a blob. It is written to a temporary file first and renamed
+// into place, so readers never see a partial blob.
+func (s *DiskStore) Put(key string, r io.Reader) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// Get opens a blob for reading. The caller closes it.
+func (s *DiskStore) Get(key string) (io.ReadCloser, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return f, nil
+}
+
+// Delete removes a blob.
+func (s *DiskStore) Delete(key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// path returns the file a key is kept in. Keys that would reach outside
+// the store's directory are rejected.
+func (s *DiskStore) path(key string) (string, error) {
+	rel := filepath.FromSlash(key)
+	if !filepath.IsLocal(rel) {
+		return "", fmt.Errorf("invalid blob key %q", key)
+	}
+	return filepath.Join(s.root, rel), nil
+}
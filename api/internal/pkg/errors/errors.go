@@ -16,6 +16,7 @@ var (
 	ErrInternalServer    = errors.New("internal server error")
 	ErrConflict          = errors.New("conflict")
 	ErrUnprocessable     = errors.New("unprocessable entity")
+	ErrUnavailable       = errors.New("service unavailable")
 )
 
 // AppError represents an application error with additional context.
@@ -74,3 +75,35 @@ func IsNotFound(err error) bool {
 	}
 	return errors.Is(err, ErrNotFound) || errors.Is(err, gorm.ErrRecordNotFound)
 }
+
+// IsConflict checks if the error is a "conflict" error.
+func IsConflict(err error) bool {
+	if err == nil {
+		return false
+	}
+	return errors.Is(err, ErrConflict)
+}
+
+// IsForbidden checks if the error is a "forbidden" error.
+func IsForbidden(err error) bool {
+	if err == nil {
+		return false
+	}
+	return errors.Is(err, ErrForbidden)
+}
+
+// IsUnprocessable checks if the error is an "unprocessable entity" error.
+func IsUnprocessable(err error) bool {
+	if err == nil {
+		return false
+	}
+	return errors.Is(err, ErrUnprocessable)
+}
+
+// IsUnavailable checks if the error is a "service unavailable" error.
+func IsUnavailable(err error) bool {
+	if err == nil {
+		return false
+	}
+	return errors.Is(err, ErrUnavailable)
+}
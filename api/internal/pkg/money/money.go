@@ -0,0 +1,145 @@
+package money
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/quantity"
+	"gorm.io/gorm"
+)
+
+// Scale is the number of minor units in a major unit of the store currency.
+const Scale = 100
+
+// decimals is the number of decimal places of the store currency.
+const decimals = 2
+
+// ErrInvalidAmount is returned when parsing text that is not an amount of
+// money with at most two decimal places.
+var ErrInvalidAmount = errors.New("invalid amount of money")
+
+// Amount is an exact amount of money in minor units (cents) of the store
+// currency. Amounts are stored as integers and written to JSON as decimal
+// numbers with two decimal places, so they never pick up floating-point
+// drift when summed.
+type Amount int64
+
+// Parse parses a decimal amount such as "12.5" or "-3.05". More than two
+// decimal places is an error rather than being silently rounded.
+func Parse(text string) (Amount, error) {
+	s := strings.TrimSpace(text)
+	negative := strings.HasPrefix(s, "-")
+	if negative {
+		s = s[1:]
+	}
+
+	units, fraction, hasPoint := strings.Cut(s, ".")
+	if units == "" || (hasPoint && fraction == "") || len(fraction) > decimals {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidAmount, text)
+	}
+	for _, part := range []string{units, fraction} {
+		for _, c := range part {
+			if c < '0' || c > '9' {
+				return 0, fmt.Errorf("%w: %q", ErrInvalidAmount, text)
+			}
+		}
+	}
+
+	fraction += strings.Repeat("0", decimals-len(fraction))
+	cents, err := strconv.ParseInt(units+fraction, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidAmount, text)
+	}
+	if negative {
+		cents = -cents
+	}
+	return Amount(cents), nil
+}
+
+// Cents returns the amount in minor units.
+func (a Amount) Cents() int64 {
+	return int64(a)
+}
+
+// Float returns the amount in major units. It is meant for display and
+// validation only; arithmetic should stay in Amount.
+func (a Amount) Float() float64 {
+	return float64(a) / Scale
+}
+
+// Mul returns the amount multiplied by a quantity, rounded half away from
+// zero to the nearest minor unit.
+func (a Amount) Mul(q quantity.Quantity) Amount {
+	return a.MulRat(int64(q), quantity.Scale)
+}
+
+// MulRat returns the amount multiplied by num/den, rounded half away from
+// zero to the nearest minor unit. It is the single rounding rule used for
+// percentages, rates and fractional quantities.
+func (a Amount) MulRat(num, den int64) Amount {
+	return Amount(divRound(int64(a)*num, den))
+}
+
+// String formats the amount as a decimal with two decimal places.
+func (a Amount) String() string {
+	sign := ""
+	cents := int64(a)
+	if cents < 0 {
+		sign = "-"
+		cents = -cents
+	}
+	return fmt.Sprintf("%s%d.%0*d", sign, cents/Scale, decimals, cents%Scale)
+}
+
+// MarshalJSON writes the amount as a JSON number with two decimal places.
+func (a Amount) MarshalJSON() ([]byte, error) {
+	return []byte(a.String()), nil
+}
+
+// UnmarshalJSON reads an amount from a JSON number or numeric string
+// without going through floating point.
+func (a *Amount) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+	text := string(data)
+	if unquoted, err := strconv.Unquote(text); err == nil {
+		text = unquoted
+	}
+
+	parsed, err := Parse(text)
+	if err != nil {
+		return err
+	}
+	*a = parsed
+	return nil
+}
+
+// divRound divides n by d, rounding half away from zero.
+func divRound(n, d int64) int64 {
+	if d < 0 {
+		n, d = -n, -d
+	}
+	if n < 0 {
+		return -((-n + d/2) / d)
+	}
+	return (n + d/2) / d
+}
+
+// MigrateColumns converts amounts stored as decimal REAL values in the
+// given columns of a table to minor units, rounding half away from zero.
+// It is meant for data migrations moving a table onto Amount. Columns that
+// keep a REAL declared type hold the minor units as whole numbers, which
+// read back exactly.
+func MigrateColumns(tx *gorm.DB, table string, columns ...string) error {
+	for _, column := range columns {
+		expr := gorm.Expr(fmt.Sprintf("CAST(ROUND(%s * %d) AS INTEGER)", column, Scale))
+		if err := tx.Table(table).Where("1 = 1").Update(column, expr).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
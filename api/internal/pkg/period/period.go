@@ -0,0 +1,55 @@
+package period
+
+import "time"
+
+// Period is the span a value is in effect for: from From until To, or from
+// From on when To is nil.
+type Period struct {
+	From time.Time
+	To   *time.Time
+}
+
+// Overlaps reports whether two periods share any instant.
+func (p Period) Overlaps(q Period) bool {
+	pEndsAfterQ := p.To == nil || p.To.After(q.From)
+	qEndsAfterP := q.To == nil || q.To.After(p.From)
+	return pEndsAfterQ && qEndsAfterP
+}
+
+// Change is what adding a period to a series of periods does to the
+// periods already in it. Indexes are into the series, and are -1 when
+// there is no such period.
+type Change struct {
+	// Superseded is the open-ended period the new period starts within. It
+	// ends where the new period starts.
+	Superseded int
+	// Resumes is when the superseded period resumes: the end of the new
+	// period when it is bounded, or nil when the new period is open-ended
+	// and supersedes it for good.
+	Resumes *time.Time
+	// Conflict is the first period the new period overlaps otherwise, in
+	// which case it may not be added.
+	Conflict int
+}
+
+// Add works out how a new period fits a series of periods that do not
+// overlap. A new period starting within the series' open-ended period
+// supersedes it from its start. When the new period is bounded, such as a
+// temporary change, the open-ended period resumes at its end rather than
+// being ended for good. The new period may not otherwise overlap any
+// period of the series.
+func Add(series []Period, p Period) Change {
+	change := Change{Superseded: -1, Conflict: -1}
+	for i, existing := range series {
+		if existing.To == nil && existing.From.Before(p.From) {
+			change.Superseded = i
+			change.Resumes = p.To
+			continue
+		}
+		if existing.Overlaps(p) {
+			change.Conflict = i
+			return change
+		}
+	}
+	return change
+}
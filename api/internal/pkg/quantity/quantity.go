@@ -0,0 +1,153 @@
+package quantity
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// Scale is the number of thousandths in a whole unit.
+const Scale = 1000
+
+// decimals is the number of decimal places a quantity can carry.
+const decimals = 3
+
+// ErrInvalidQuantity is returned when parsing text that is not a quantity
+// with at most three decimal places.
+var ErrInvalidQuantity = errors.New("invalid quantity")
+
+// Quantity is an exact quantity in thousandths of a unit, so that weighed
+// goods can be counted to the gram. Quantities are stored as integers and
+// written to JSON as decimal numbers without trailing zeros, so whole
+// quantities still read as plain integers.
+type Quantity int64
+
+// FromInt returns a whole quantity.
+func FromInt(n int) Quantity {
+	return Quantity(n) * Scale
+}
+
+// Parse parses a decimal quantity such as "24", "0.25" or "-1.5". More than
+// three decimal places is an error rather than being silently rounded.
+func Parse(text string) (Quantity, error) {
+	s := strings.TrimSpace(text)
+	negative := strings.HasPrefix(s, "-")
+	if negative {
+		s = s[1:]
+	}
+
+	units, fraction, hasPoint := strings.Cut(s, ".")
+	if units == "" || (hasPoint && fraction == "") || len(fraction) > decimals {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidQuantity, text)
+	}
+	for _, part := range []string{units, fraction} {
+		for _, c := range part {
+			if c < '0' || c > '9' {
+				return 0, fmt.Errorf("%w: %q", ErrInvalidQuantity, text)
+			}
+		}
+	}
+
+	fraction += strings.Repeat("0", decimals-len(fraction))
+	thousandths, err := strconv.ParseInt(units+fraction, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidQuantity, text)
+	}
+	if negative {
+		thousandths = -thousandths
+	}
+	return Quantity(thousandths), nil
+}
+
+// IsWhole reports whether the quantity is a whole number of units.
+func (q Quantity) IsWhole() bool {
+	return q%Scale == 0
+}
+
+// Floor returns the number of whole units in the quantity, rounding toward
+// negative infinity.
+func (q Quantity) Floor() int {
+	if q < 0 && !q.IsWhole() {
+		return int(q/Scale) - 1
+	}
+	return int(q / Scale)
+}
+
+// Mul returns the quantity multiplied by a conversion factor, rounded half
+// away from zero to the nearest thousandth.
+func (q Quantity) Mul(factor Quantity) Quantity {
+	return Quantity(divRound(int64(q)*int64(factor), Scale))
+}
+
+// Div returns the quantity divided by a conversion factor, rounded half
+// away from zero to the nearest thousandth.
+func (q Quantity) Div(factor Quantity) Quantity {
+	return Quantity(divRound(int64(q)*Scale, int64(factor)))
+}
+
+// String formats the quantity as a decimal without trailing zeros.
+func (q Quantity) String() string {
+	sign := ""
+	thousandths := int64(q)
+	if thousandths < 0 {
+		sign = "-"
+		thousandths = -thousandths
+	}
+	if thousandths%Scale == 0 {
+		return fmt.Sprintf("%s%d", sign, thousandths/Scale)
+	}
+	fraction := strings.TrimRight(fmt.Sprintf("%0*d", decimals, thousandths%Scale), "0")
+	return fmt.Sprintf("%s%d.%s", sign, thousandths/Scale, fraction)
+}
+
+// MarshalJSON writes the quantity as a JSON number.
+func (q Quantity) MarshalJSON() ([]byte, error) {
+	return []byte(q.String()), nil
+}
+
+// UnmarshalJSON reads a quantity from a JSON number or numeric string
+// without going through floating point.
+func (q *Quantity) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+	text := string(data)
+	if unquoted, err := strconv.Unquote(text); err == nil {
+		text = unquoted
+	}
+
+	parsed, err := Parse(text)
+	if err != nil {
+		return err
+	}
+	*q = parsed
+	return nil
+}
+
+// divRound divides n by d, rounding half away from zero.
+func divRound(n, d int64) int64 {
+	if d < 0 {
+		n, d = -n, -d
+	}
+	if n < 0 {
+		return -((-n + d/2) / d)
+	}
+	return (n + d/2) / d
+}
+
+// MigrateColumns converts whole quantities stored in the given columns of a
+// table to thousandths. It is meant for data migrations moving a table onto
+// Quantity.
+func MigrateColumns(tx *gorm.DB, table string, columns ...string) error {
+	for _, column := range columns {
+		expr := gorm.Expr(fmt.Sprintf("%s * %d", column, Scale))
+		if err := tx.Table(table).Where("1 = 1").Update(column, expr).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
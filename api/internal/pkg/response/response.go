@@ -38,6 +38,13 @@ func Created(w http.ResponseWriter, data any) {
 	})
 }
 
+// Raw writes a non-JSON response body with the given content type.
+func Raw(w http.ResponseWriter, statusCode int, contentType string, body []byte) {
+	w.Header().Set("Content-Type", contentType)
+	w.WriteHeader(statusCode)
+	w.Write(body)
+}
+
 // NoContent writes a 204 No Content response.
 func NoContent(w http.ResponseWriter) {
 	w.WriteHeader(http.StatusNoContent)
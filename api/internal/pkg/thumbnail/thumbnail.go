@@ -0,0 +1,63 @@
+package thumbnail
+
+import (
+	"image"
+	"image/draw"
+)
+
+// Fit scales an image down to fit in a square size pixels wide, keeping
+// its aspect ratio. Each pixel of the thumbnail is the average of the
+// pixels it covers, which keeps detail without aliasing. Images already
+// small enough keep their size.
+func Fit(src image.Image, size int) *image.RGBA {
+	b := src.Bounds()
+	sw, sh := b.Dx(), b.Dy()
+	w, h := sw, sh
+	if w > size || h > size {
+		if w >= h {
+			w, h = size, max(sh*size/sw, 1)
+		} else {
+			w, h = max(sw*size/sh, 1), size
+		}
+	}
+
+	// Averaging works on premultiplied colours so transparent pixels do
+	// not darken the edges around them.
+	rgba := image.NewRGBA(image.Rect(0, 0, sw, sh))
+	draw.Draw(rgba, rgba.Bounds(), src, b.Min, draw.Src)
+
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	for dy := range h {
+		y0, y1 := span(dy, h, sh)
+		for dx := range w {
+			x0, x1 := span(dx, w, sw)
+			var r, g, bl, a, n int
+			for y := y0; y < y1; y++ {
+				row := rgba.Pix[y*rgba.Stride:]
+				for x := x0; x < x1; x++ {
+					p := row[x*4 : x*4+4]
+					r += int(p[0])
+					g += int(p[1])
+					bl += int(p[2])
+					a += int(p[3])
+					n++
+				}
+			}
+			p := dst.Pix[dy*dst.Stride+dx*4:]
+			p[0] = uint8(r / n)
+			p[1] = uint8(g / n)
+			p[2] = uint8(bl / n)
+			p[3] = uint8(a / n)
+		}
+	}
+	return dst
+}
+
+// span returns the source pixels [from, to) that thumbnail pixel i of n
+// covers along an edge of length src. Every thumbnail pixel covers at
+// least one source pixel.
+func span(i, n, src int) (from, to int) {
+	from = i * src / n
+	to = max((i+1)*src/n, from+1)
+	return from, to
+}
@@ -3,9 +3,11 @@ package validator
 import (
 	"errors"
 	"fmt"
+	"reflect"
 	"strings"
 
 	"github.com/go-playground/validator/v10"
+	"github.com/shanmugharajk/go-react-web-api/api/internal/pkg/money"
 )
 
 // validate is the package-level validator instance.
@@ -13,6 +15,12 @@ var validate *validator.Validate
 
 func init() {
 	validate = validator.New(validator.WithRequiredStructEnabled())
+
+	// Money is validated in major units, so "gte=0" or "lte=1000.50" read
+	// the same as the amounts in the request body.
+	validate.RegisterCustomTypeFunc(func(v reflect.Value) any {
+		return v.Interface().(money.Amount).Float()
+	}, money.Amount(0))
 }
 
 // ValidationError represents a validation error with field details.
@@ -119,3 +127,17 @@ func GetValidationErrors(err error) (ValidationErrors, bool) {
 	}
 	return nil, false
 }
+
+// Nest returns validation errors with their fields nested under a parent
+// field, such as "lines[0]". Other errors are returned unchanged.
+func Nest(parent string, err error) error {
+	validationErrs, ok := GetValidationErrors(err)
+	if !ok {
+		return err
+	}
+	nested := make(ValidationErrors, len(validationErrs))
+	for i, e := range validationErrs {
+		nested[i] = ValidationError{Field: parent + "." + e.Field, Message: e.Message}
+	}
+	return nested
+}